@@ -98,7 +98,7 @@ func init() {
 	// The commands in this file are only usable with a wallet server.
 	flags := UFWalletOnly
 
-	MustRegisterCmd("createnewaccount", (*CreateNewAccountCmd)(nil), flags)
+	MustRegisterCmd("createnewaccount", (*CreateNewAccountCmd)(nil), flags|UFNonIdempotent)
 	MustRegisterCmd("dumpwallet", (*DumpWalletCmd)(nil), flags)
 	MustRegisterCmd("importaddress", (*ImportAddressCmd)(nil), flags)
 	MustRegisterCmd("importpubkey", (*ImportPubKeyCmd)(nil), flags)
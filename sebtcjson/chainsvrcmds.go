@@ -48,6 +48,32 @@ func NewAddNodeCmd(addr string, subCmd AddNodeSubCmd) *AddNodeCmd {
 	}
 }
 
+// AnalyzePsbtCmd defines the analyzepsbt JSON-RPC command.
+type AnalyzePsbtCmd struct {
+	Psbt string
+}
+
+// NewAnalyzePsbtCmd returns a new instance which can be used to issue an
+// analyzepsbt JSON-RPC command.
+func NewAnalyzePsbtCmd(psbt string) *AnalyzePsbtCmd {
+	return &AnalyzePsbtCmd{
+		Psbt: psbt,
+	}
+}
+
+// CombinePsbtCmd defines the combinepsbt JSON-RPC command.
+type CombinePsbtCmd struct {
+	Psbts []string
+}
+
+// NewCombinePsbtCmd returns a new instance which can be used to issue a
+// combinepsbt JSON-RPC command.
+func NewCombinePsbtCmd(psbts []string) *CombinePsbtCmd {
+	return &CombinePsbtCmd{
+		Psbts: psbts,
+	}
+}
+
 // TransactionInput represents the inputs to a transaction.  Specifically a
 // transaction hash and output number pair.
 type TransactionInput struct {
@@ -58,7 +84,7 @@ type TransactionInput struct {
 // CreateRawTransactionCmd defines the createrawtransaction JSON-RPC command.
 type CreateRawTransactionCmd struct {
 	Inputs   []TransactionInput
-	Amounts  map[string]float64 `jsonrpcusage:"{\"address\":amount,...}"` // In BTC
+	Amounts  map[string]Amount `jsonrpcusage:"{\"address\":amount,...}"` // In BTC
 	LockTime *int64
 }
 
@@ -71,11 +97,24 @@ func NewCreateRawTransactionCmd(inputs []TransactionInput, amounts map[string]fl
 
 	return &CreateRawTransactionCmd{
 		Inputs:   inputs,
-		Amounts:  amounts,
+		Amounts:  amountMap(amounts),
 		LockTime: lockTime,
 	}
 }
 
+// DecodePsbtCmd defines the decodepsbt JSON-RPC command.
+type DecodePsbtCmd struct {
+	Psbt string
+}
+
+// NewDecodePsbtCmd returns a new instance which can be used to issue a
+// decodepsbt JSON-RPC command.
+func NewDecodePsbtCmd(psbt string) *DecodePsbtCmd {
+	return &DecodePsbtCmd{
+		Psbt: psbt,
+	}
+}
+
 // DecodeRawTransactionCmd defines the decoderawtransaction JSON-RPC command.
 type DecodeRawTransactionCmd struct {
 	HexTx string
@@ -102,6 +141,78 @@ func NewDecodeScriptCmd(hexScript string) *DecodeScriptCmd {
 	}
 }
 
+// DescriptorRange represents the range of child indexes a ranged output
+// descriptor is derived with, as accepted by the deriveaddresses and
+// scantxoutset JSON-RPC commands.  A range starting at index 0 is marshalled as its end index alone
+// and any other range as a [begin,end] array.
+type DescriptorRange struct {
+	Begin int
+	End   int
+}
+
+// MarshalJSON provides a custom Marshal method for DescriptorRange.
+func (r DescriptorRange) MarshalJSON() ([]byte, error) {
+	if r.Begin == 0 {
+		return json.Marshal(r.End)
+	}
+	return json.Marshal([]int{r.Begin, r.End})
+}
+
+// UnmarshalJSON provides a custom Unmarshal method for DescriptorRange.
+func (r *DescriptorRange) UnmarshalJSON(data []byte) error {
+	var end int
+	if err := json.Unmarshal(data, &end); err == nil {
+		r.Begin, r.End = 0, end
+		return nil
+	}
+
+	var bounds []int
+	if err := json.Unmarshal(data, &bounds); err != nil || len(bounds) != 2 {
+		str := "the range field must be an integer or an array of two " +
+			"integers"
+		return makeError(ErrInvalidType, str)
+	}
+	r.Begin, r.End = bounds[0], bounds[1]
+	return nil
+}
+
+// DeriveAddressesCmd defines the deriveaddresses JSON-RPC command.
+type DeriveAddressesCmd struct {
+	Descriptor string
+	Range      *DescriptorRange
+}
+
+// NewDeriveAddressesCmd returns a new instance which can be used to issue a
+// deriveaddresses JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.  The range is required
+// for ranged descriptors only.
+func NewDeriveAddressesCmd(descriptor string, rangeVals *DescriptorRange) *DeriveAddressesCmd {
+	return &DeriveAddressesCmd{
+		Descriptor: descriptor,
+		Range:      rangeVals,
+	}
+}
+
+// FinalizePsbtCmd defines the finalizepsbt JSON-RPC command.
+type FinalizePsbtCmd struct {
+	Psbt    string
+	Extract *bool `jsonrpcdefault:"true"`
+}
+
+// NewFinalizePsbtCmd returns a new instance which can be used to issue a
+// finalizepsbt JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewFinalizePsbtCmd(psbt string, extract *bool) *FinalizePsbtCmd {
+	return &FinalizePsbtCmd{
+		Psbt:    psbt,
+		Extract: extract,
+	}
+}
+
 // GetAddedNodeInfoCmd defines the getaddednodeinfo JSON-RPC command.
 type GetAddedNodeInfoCmd struct {
 	DNS  bool
@@ -120,6 +231,58 @@ func NewGetAddedNodeInfoCmd(dns bool, node *string) *GetAddedNodeInfoCmd {
 	}
 }
 
+// GetAddressBalanceRequest represents the request object provided with a
+// GetAddressBalanceCmd command.
+type GetAddressBalanceRequest struct {
+	Addresses []string `json:"addresses"`
+	Mempool   bool     `json:"mempool,omitempty"`
+}
+
+// GetAddressBalanceCmd defines the getaddressbalance JSON-RPC command.
+//
+// NOTE: This is an extension of the bitcore fork of Bitcoin Core which
+// requires the address index to be enabled with -addressindex.
+type GetAddressBalanceCmd struct {
+	Request GetAddressBalanceRequest
+}
+
+// NewGetAddressBalanceCmd returns a new instance which can be used to issue a
+// getaddressbalance JSON-RPC command.
+func NewGetAddressBalanceCmd(addresses []string, mempool bool) *GetAddressBalanceCmd {
+	return &GetAddressBalanceCmd{
+		Request: GetAddressBalanceRequest{
+			Addresses: addresses,
+			Mempool:   mempool,
+		},
+	}
+}
+
+// GetAddressUtxosRequest represents the request object provided with a
+// GetAddressUtxosCmd command.
+type GetAddressUtxosRequest struct {
+	Addresses []string `json:"addresses"`
+	ChainInfo bool     `json:"chainInfo,omitempty"`
+}
+
+// GetAddressUtxosCmd defines the getaddressutxos JSON-RPC command.
+//
+// NOTE: This is an extension of the bitcore fork of Bitcoin Core which
+// requires the address index to be enabled with -addressindex.
+type GetAddressUtxosCmd struct {
+	Request GetAddressUtxosRequest
+}
+
+// NewGetAddressUtxosCmd returns a new instance which can be used to issue a
+// getaddressutxos JSON-RPC command.
+func NewGetAddressUtxosCmd(addresses []string, chainInfo bool) *GetAddressUtxosCmd {
+	return &GetAddressUtxosCmd{
+		Request: GetAddressUtxosRequest{
+			Addresses: addresses,
+			ChainInfo: chainInfo,
+		},
+	}
+}
+
 // GetBestBlockHashCmd defines the getbestblockhash JSON-RPC command.
 type GetBestBlockHashCmd struct{}
 
@@ -167,6 +330,39 @@ func NewGetBlockCountCmd() *GetBlockCountCmd {
 	return &GetBlockCountCmd{}
 }
 
+// GetBlockFilterCmd defines the getblockfilter JSON-RPC command.
+type GetBlockFilterCmd struct {
+	BlockHash  string
+	FilterType *string `jsonrpcdefault:"\"basic\""`
+}
+
+// NewGetBlockFilterCmd returns a new instance which can be used to issue a
+// getblockfilter JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetBlockFilterCmd(blockHash string, filterType *string) *GetBlockFilterCmd {
+	return &GetBlockFilterCmd{
+		BlockHash:  blockHash,
+		FilterType: filterType,
+	}
+}
+
+// GetBlockFromPeerCmd defines the getblockfrompeer JSON-RPC command.
+type GetBlockFromPeerCmd struct {
+	BlockHash string
+	PeerID    int64
+}
+
+// NewGetBlockFromPeerCmd returns a new instance which can be used to issue a
+// getblockfrompeer JSON-RPC command.
+func NewGetBlockFromPeerCmd(blockHash string, peerID int64) *GetBlockFromPeerCmd {
+	return &GetBlockFromPeerCmd{
+		BlockHash: blockHash,
+		PeerID:    peerID,
+	}
+}
+
 // GetBlockHashCmd defines the getblockhash JSON-RPC command.
 type GetBlockHashCmd struct {
 	Index int64
@@ -195,6 +391,54 @@ func NewGetBlockHeaderCmd(hash string, verbose *bool) *GetBlockHeaderCmd {
 	}
 }
 
+// HashOrHeight represents a block which is identified by either its hash or
+// its height, as accepted by the getblockstats JSON-RPC command.  The value is
+// a string for a hash and an int for a height.
+type HashOrHeight struct {
+	Value interface{}
+}
+
+// MarshalJSON provides a custom Marshal method for HashOrHeight.
+func (h HashOrHeight) MarshalJSON() ([]byte, error) {
+	return json.Marshal(h.Value)
+}
+
+// UnmarshalJSON provides a custom Unmarshal method for HashOrHeight.
+func (h *HashOrHeight) UnmarshalJSON(data []byte) error {
+	var height int
+	if err := json.Unmarshal(data, &height); err == nil {
+		h.Value = height
+		return nil
+	}
+
+	var hash string
+	if err := json.Unmarshal(data, &hash); err != nil {
+		str := "the hash_or_height field must be a string or an integer"
+		return makeError(ErrInvalidType, str)
+	}
+	h.Value = hash
+	return nil
+}
+
+// GetBlockStatsCmd defines the getblockstats JSON-RPC command.
+type GetBlockStatsCmd struct {
+	HashOrHeight HashOrHeight
+	Stats        *[]string
+}
+
+// NewGetBlockStatsCmd returns a new instance which can be used to issue a
+// getblockstats JSON-RPC command.  Either a block hash or a block height may be
+// passed as the value of hashOrHeight.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetBlockStatsCmd(hashOrHeight HashOrHeight, stats *[]string) *GetBlockStatsCmd {
+	return &GetBlockStatsCmd{
+		HashOrHeight: hashOrHeight,
+		Stats:        stats,
+	}
+}
+
 // TemplateRequest is a request object as defined in BIP22
 // (https://en.bitcoin.it/wiki/BIP_0022), it is optionally provided as an
 // pointer argument to GetBlockTemplateCmd.
@@ -202,6 +446,10 @@ type TemplateRequest struct {
 	Mode         string   `json:"mode,omitempty"`
 	Capabilities []string `json:"capabilities,omitempty"`
 
+	// Rules supported by the client as defined in BIP 0009.  Newer servers
+	// require "segwit" to be present.
+	Rules []string `json:"rules,omitempty"`
+
 	// Optional long polling.
 	LongPollID string `json:"longpollid,omitempty"`
 
@@ -292,6 +540,24 @@ func NewGetChainTipsCmd() *GetChainTipsCmd {
 	return &GetChainTipsCmd{}
 }
 
+// GetChainTxStatsCmd defines the getchaintxstats JSON-RPC command.
+type GetChainTxStatsCmd struct {
+	NBlocks   *int32
+	BlockHash *string
+}
+
+// NewGetChainTxStatsCmd returns a new instance which can be used to issue a
+// getchaintxstats JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetChainTxStatsCmd(nBlocks *int32, blockHash *string) *GetChainTxStatsCmd {
+	return &GetChainTxStatsCmd{
+		NBlocks:   nBlocks,
+		BlockHash: blockHash,
+	}
+}
+
 // GetConnectionCountCmd defines the getconnectioncount JSON-RPC command.
 type GetConnectionCountCmd struct{}
 
@@ -301,6 +567,19 @@ func NewGetConnectionCountCmd() *GetConnectionCountCmd {
 	return &GetConnectionCountCmd{}
 }
 
+// GetDescriptorInfoCmd defines the getdescriptorinfo JSON-RPC command.
+type GetDescriptorInfoCmd struct {
+	Descriptor string
+}
+
+// NewGetDescriptorInfoCmd returns a new instance which can be used to issue a
+// getdescriptorinfo JSON-RPC command.
+func NewGetDescriptorInfoCmd(descriptor string) *GetDescriptorInfoCmd {
+	return &GetDescriptorInfoCmd{
+		Descriptor: descriptor,
+	}
+}
+
 // GetDifficultyCmd defines the getdifficulty JSON-RPC command.
 type GetDifficultyCmd struct{}
 
@@ -489,10 +768,20 @@ func NewGetTxOutProofCmd(txIDs []string, blockHash *string) *GetTxOutProofCmd {
 }
 
 // GetTxOutSetInfoCmd defines the gettxoutsetinfo JSON-RPC command.
-type GetTxOutSetInfoCmd struct{}
+//
+// The parameters are optional and positional, so a nil parameter followed by
+// a provided one is sent as null, which the server treats as its default.
+// UseIndex selects whether the coinstatsindex is used when the server has it
+// enabled.
+type GetTxOutSetInfoCmd struct {
+	HashType     *string
+	HashOrHeight *HashOrHeight
+	UseIndex     *bool `jsonrpcdefault:"true"`
+}
 
 // NewGetTxOutSetInfoCmd returns a new instance which can be used to issue a
-// gettxoutsetinfo JSON-RPC command.
+// gettxoutsetinfo JSON-RPC command with the server defaults.  The optional
+// parameters may be set on the returned command.
 func NewGetTxOutSetInfoCmd() *GetTxOutSetInfoCmd {
 	return &GetTxOutSetInfoCmd{}
 }
@@ -513,6 +802,15 @@ func NewGetWorkCmd(data *string) *GetWorkCmd {
 	}
 }
 
+// GetZmqNotificationsCmd defines the getzmqnotifications JSON-RPC command.
+type GetZmqNotificationsCmd struct{}
+
+// NewGetZmqNotificationsCmd returns a new instance which can be used to issue a
+// getzmqnotifications JSON-RPC command.
+func NewGetZmqNotificationsCmd() *GetZmqNotificationsCmd {
+	return &GetZmqNotificationsCmd{}
+}
+
 // HelpCmd defines the help JSON-RPC command.
 type HelpCmd struct {
 	Command *string
@@ -577,6 +875,66 @@ func NewReconsiderBlockCmd(blockHash string) *ReconsiderBlockCmd {
 	}
 }
 
+// ScanObject is an output descriptor to scan the unspent transaction output
+// set for with the scantxoutset JSON-RPC command.  A scan object without a
+// range is marshalled as the bare descriptor and any other as an object with
+// the desc and range fields.
+type ScanObject struct {
+	Descriptor string
+	Range      *DescriptorRange
+}
+
+// scanObjectJSON is the object form of a ScanObject.
+type scanObjectJSON struct {
+	Desc  string           `json:"desc"`
+	Range *DescriptorRange `json:"range,omitempty"`
+}
+
+// MarshalJSON provides a custom Marshal method for ScanObject.
+func (o ScanObject) MarshalJSON() ([]byte, error) {
+	if o.Range == nil {
+		return json.Marshal(o.Descriptor)
+	}
+	return json.Marshal(scanObjectJSON{Desc: o.Descriptor, Range: o.Range})
+}
+
+// UnmarshalJSON provides a custom Unmarshal method for ScanObject.
+func (o *ScanObject) UnmarshalJSON(data []byte) error {
+	var desc string
+	if err := json.Unmarshal(data, &desc); err == nil {
+		o.Descriptor, o.Range = desc, nil
+		return nil
+	}
+
+	var obj scanObjectJSON
+	if err := json.Unmarshal(data, &obj); err != nil {
+		str := "a scan object must be a descriptor or an object with " +
+			"the desc and range fields"
+		return makeError(ErrInvalidType, str)
+	}
+	o.Descriptor, o.Range = obj.Desc, obj.Range
+	return nil
+}
+
+// ScanTxOutSetCmd defines the scantxoutset JSON-RPC command.
+type ScanTxOutSetCmd struct {
+	Action      string
+	ScanObjects *[]ScanObject
+}
+
+// NewScanTxOutSetCmd returns a new instance which can be used to issue a
+// scantxoutset JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.  The scan objects are
+// required for the start action only.
+func NewScanTxOutSetCmd(action string, scanObjects *[]ScanObject) *ScanTxOutSetCmd {
+	return &ScanTxOutSetCmd{
+		Action:      action,
+		ScanObjects: scanObjects,
+	}
+}
+
 // SearchRawTransactionsCmd defines the searchrawtransactions JSON-RPC command.
 type SearchRawTransactionsCmd struct {
 	Address     string
@@ -641,6 +999,41 @@ func NewSetGenerateCmd(generate bool, genProcLimit *int) *SetGenerateCmd {
 	}
 }
 
+// RawTxWitnessInput models the data needed for a previous output that is used
+// in the SignRawTransactionWithKeyCmd struct.  The amount is required when
+// signing segwit inputs.
+type RawTxWitnessInput struct {
+	Txid          string   `json:"txid"`
+	Vout          uint32   `json:"vout"`
+	ScriptPubKey  string   `json:"scriptPubKey"`
+	RedeemScript  *string  `json:"redeemScript,omitempty"`
+	WitnessScript *string  `json:"witnessScript,omitempty"`
+	Amount        *float64 `json:"amount,omitempty"`
+}
+
+// SignRawTransactionWithKeyCmd defines the signrawtransactionwithkey JSON-RPC
+// command.
+type SignRawTransactionWithKeyCmd struct {
+	RawTx       string
+	PrivKeys    []string
+	PrevTxs     *[]RawTxWitnessInput
+	SigHashType *string `jsonrpcdefault:"\"ALL\""`
+}
+
+// NewSignRawTransactionWithKeyCmd returns a new instance which can be used to
+// issue a signrawtransactionwithkey JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewSignRawTransactionWithKeyCmd(hexEncodedTx string, privKeys []string, prevTxs *[]RawTxWitnessInput, sigHashType *string) *SignRawTransactionWithKeyCmd {
+	return &SignRawTransactionWithKeyCmd{
+		RawTx:       hexEncodedTx,
+		PrivKeys:    privKeys,
+		PrevTxs:     prevTxs,
+		SigHashType: sigHashType,
+	}
+}
+
 // StopCmd defines the stop JSON-RPC command.
 type StopCmd struct{}
 
@@ -675,6 +1068,24 @@ func NewSubmitBlockCmd(hexBlock string, options *SubmitBlockOptions) *SubmitBloc
 	}
 }
 
+// TestMempoolAcceptCmd defines the testmempoolaccept JSON-RPC command.
+type TestMempoolAcceptCmd struct {
+	RawTxns    []string
+	MaxFeeRate *float64 // In BTC/kvB
+}
+
+// NewTestMempoolAcceptCmd returns a new instance which can be used to issue a
+// testmempoolaccept JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewTestMempoolAcceptCmd(rawTxns []string, maxFeeRate *float64) *TestMempoolAcceptCmd {
+	return &TestMempoolAcceptCmd{
+		RawTxns:    rawTxns,
+		MaxFeeRate: maxFeeRate,
+	}
+}
+
 // UptimeCmd defines the uptime JSON-RPC command.
 type UptimeCmd struct{}
 
@@ -749,19 +1160,31 @@ func init() {
 	flags := UsageFlag(0)
 
 	MustRegisterCmd("addnode", (*AddNodeCmd)(nil), flags)
+	MustRegisterCmd("analyzepsbt", (*AnalyzePsbtCmd)(nil), flags)
+	MustRegisterCmd("combinepsbt", (*CombinePsbtCmd)(nil), flags)
 	MustRegisterCmd("createrawtransaction", (*CreateRawTransactionCmd)(nil), flags)
+	MustRegisterCmd("decodepsbt", (*DecodePsbtCmd)(nil), flags)
 	MustRegisterCmd("decoderawtransaction", (*DecodeRawTransactionCmd)(nil), flags)
 	MustRegisterCmd("decodescript", (*DecodeScriptCmd)(nil), flags)
+	MustRegisterCmd("deriveaddresses", (*DeriveAddressesCmd)(nil), flags)
+	MustRegisterCmd("finalizepsbt", (*FinalizePsbtCmd)(nil), flags)
 	MustRegisterCmd("getaddednodeinfo", (*GetAddedNodeInfoCmd)(nil), flags)
+	MustRegisterCmd("getaddressbalance", (*GetAddressBalanceCmd)(nil), flags)
+	MustRegisterCmd("getaddressutxos", (*GetAddressUtxosCmd)(nil), flags)
 	MustRegisterCmd("getbestblockhash", (*GetBestBlockHashCmd)(nil), flags)
 	MustRegisterCmd("getblock", (*GetBlockCmd)(nil), flags)
 	MustRegisterCmd("getblockchaininfo", (*GetBlockChainInfoCmd)(nil), flags)
 	MustRegisterCmd("getblockcount", (*GetBlockCountCmd)(nil), flags)
+	MustRegisterCmd("getblockfilter", (*GetBlockFilterCmd)(nil), flags)
+	MustRegisterCmd("getblockfrompeer", (*GetBlockFromPeerCmd)(nil), flags)
 	MustRegisterCmd("getblockhash", (*GetBlockHashCmd)(nil), flags)
 	MustRegisterCmd("getblockheader", (*GetBlockHeaderCmd)(nil), flags)
+	MustRegisterCmd("getblockstats", (*GetBlockStatsCmd)(nil), flags)
 	MustRegisterCmd("getblocktemplate", (*GetBlockTemplateCmd)(nil), flags)
 	MustRegisterCmd("getchaintips", (*GetChainTipsCmd)(nil), flags)
+	MustRegisterCmd("getchaintxstats", (*GetChainTxStatsCmd)(nil), flags)
 	MustRegisterCmd("getconnectioncount", (*GetConnectionCountCmd)(nil), flags)
+	MustRegisterCmd("getdescriptorinfo", (*GetDescriptorInfoCmd)(nil), flags)
 	MustRegisterCmd("getdifficulty", (*GetDifficultyCmd)(nil), flags)
 	MustRegisterCmd("getgenerate", (*GetGenerateCmd)(nil), flags)
 	MustRegisterCmd("gethashespersec", (*GetHashesPerSecCmd)(nil), flags)
@@ -779,16 +1202,20 @@ func init() {
 	MustRegisterCmd("gettxoutproof", (*GetTxOutProofCmd)(nil), flags)
 	MustRegisterCmd("gettxoutsetinfo", (*GetTxOutSetInfoCmd)(nil), flags)
 	MustRegisterCmd("getwork", (*GetWorkCmd)(nil), flags)
+	MustRegisterCmd("getzmqnotifications", (*GetZmqNotificationsCmd)(nil), flags)
 	MustRegisterCmd("help", (*HelpCmd)(nil), flags)
 	MustRegisterCmd("invalidateblock", (*InvalidateBlockCmd)(nil), flags)
 	MustRegisterCmd("ping", (*PingCmd)(nil), flags)
 	MustRegisterCmd("preciousblock", (*PreciousBlockCmd)(nil), flags)
 	MustRegisterCmd("reconsiderblock", (*ReconsiderBlockCmd)(nil), flags)
+	MustRegisterCmd("scantxoutset", (*ScanTxOutSetCmd)(nil), flags)
 	MustRegisterCmd("searchrawtransactions", (*SearchRawTransactionsCmd)(nil), flags)
 	MustRegisterCmd("sendrawtransaction", (*SendRawTransactionCmd)(nil), flags)
 	MustRegisterCmd("setgenerate", (*SetGenerateCmd)(nil), flags)
+	MustRegisterCmd("signrawtransactionwithkey", (*SignRawTransactionWithKeyCmd)(nil), flags)
 	MustRegisterCmd("stop", (*StopCmd)(nil), flags)
 	MustRegisterCmd("submitblock", (*SubmitBlockCmd)(nil), flags)
+	MustRegisterCmd("testmempoolaccept", (*TestMempoolAcceptCmd)(nil), flags)
 	MustRegisterCmd("uptime", (*UptimeCmd)(nil), flags)
 	MustRegisterCmd("validateaddress", (*ValidateAddressCmd)(nil), flags)
 	MustRegisterCmd("verifychain", (*VerifyChainCmd)(nil), flags)
@@ -39,6 +39,28 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"addnode","params":["127.0.0.1","remove"],"id":1}`,
 			unmarshalled: &AddNodeCmd{Addr: "127.0.0.1", SubCmd: ANRemove},
 		},
+		{
+			name: "analyzepsbt",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("analyzepsbt", "cHNidP8=")
+			},
+			staticCmd: func() interface{} {
+				return NewAnalyzePsbtCmd("cHNidP8=")
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"analyzepsbt","params":["cHNidP8="],"id":1}`,
+			unmarshalled: &AnalyzePsbtCmd{Psbt: "cHNidP8="},
+		},
+		{
+			name: "combinepsbt",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("combinepsbt", `["cHNidP8=","cHNidP9="]`)
+			},
+			staticCmd: func() interface{} {
+				return NewCombinePsbtCmd([]string{"cHNidP8=", "cHNidP9="})
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"combinepsbt","params":[["cHNidP8=","cHNidP9="]],"id":1}`,
+			unmarshalled: &CombinePsbtCmd{Psbts: []string{"cHNidP8=", "cHNidP9="}},
+		},
 		{
 			name: "createrawtransaction",
 			newCmd: func() (interface{}, error) {
@@ -52,10 +74,10 @@ func TestChainSvrCmds(t *testing.T) {
 				amounts := map[string]float64{"456": .0123}
 				return NewCreateRawTransactionCmd(txInputs, amounts, nil)
 			},
-			marshalled: `{"jsonrpc":"1.0","method":"createrawtransaction","params":[[{"txid":"123","vout":1}],{"456":0.0123}],"id":1}`,
+			marshalled: `{"jsonrpc":"1.0","method":"createrawtransaction","params":[[{"txid":"123","vout":1}],{"456":0.01230000}],"id":1}`,
 			unmarshalled: &CreateRawTransactionCmd{
 				Inputs:  []TransactionInput{{Txid: "123", Vout: 1}},
-				Amounts: map[string]float64{"456": .0123},
+				Amounts: map[string]Amount{"456": .0123},
 			},
 		},
 		{
@@ -71,14 +93,25 @@ func TestChainSvrCmds(t *testing.T) {
 				amounts := map[string]float64{"456": .0123}
 				return NewCreateRawTransactionCmd(txInputs, amounts, Int64(12312333333))
 			},
-			marshalled: `{"jsonrpc":"1.0","method":"createrawtransaction","params":[[{"txid":"123","vout":1}],{"456":0.0123},12312333333],"id":1}`,
+			marshalled: `{"jsonrpc":"1.0","method":"createrawtransaction","params":[[{"txid":"123","vout":1}],{"456":0.01230000},12312333333],"id":1}`,
 			unmarshalled: &CreateRawTransactionCmd{
 				Inputs:   []TransactionInput{{Txid: "123", Vout: 1}},
-				Amounts:  map[string]float64{"456": .0123},
+				Amounts:  map[string]Amount{"456": .0123},
 				LockTime: Int64(12312333333),
 			},
 		},
 
+		{
+			name: "decodepsbt",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("decodepsbt", "cHNidP8=")
+			},
+			staticCmd: func() interface{} {
+				return NewDecodePsbtCmd("cHNidP8=")
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"decodepsbt","params":["cHNidP8="],"id":1}`,
+			unmarshalled: &DecodePsbtCmd{Psbt: "cHNidP8="},
+		},
 		{
 			name: "decoderawtransaction",
 			newCmd: func() (interface{}, error) {
@@ -101,6 +134,75 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"decodescript","params":["00"],"id":1}`,
 			unmarshalled: &DecodeScriptCmd{HexScript: "00"},
 		},
+		{
+			name: "deriveaddresses",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("deriveaddresses", "addr(mkmZxiEcEd8ZqjQWVZuC6so5dFMKEFpN2j)#02wpgw69")
+			},
+			staticCmd: func() interface{} {
+				return NewDeriveAddressesCmd("addr(mkmZxiEcEd8ZqjQWVZuC6so5dFMKEFpN2j)#02wpgw69", nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"deriveaddresses","params":["addr(mkmZxiEcEd8ZqjQWVZuC6so5dFMKEFpN2j)#02wpgw69"],"id":1}`,
+			unmarshalled: &DeriveAddressesCmd{
+				Descriptor: "addr(mkmZxiEcEd8ZqjQWVZuC6so5dFMKEFpN2j)#02wpgw69",
+			},
+		},
+		{
+			name: "deriveaddresses range end",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("deriveaddresses", "wpkh(tpub/0/*)", DescriptorRange{End: 2})
+			},
+			staticCmd: func() interface{} {
+				return NewDeriveAddressesCmd("wpkh(tpub/0/*)", &DescriptorRange{End: 2})
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"deriveaddresses","params":["wpkh(tpub/0/*)",2],"id":1}`,
+			unmarshalled: &DeriveAddressesCmd{
+				Descriptor: "wpkh(tpub/0/*)",
+				Range:      &DescriptorRange{End: 2},
+			},
+		},
+		{
+			name: "deriveaddresses range",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("deriveaddresses", "wpkh(tpub/0/*)", DescriptorRange{Begin: 1, End: 5})
+			},
+			staticCmd: func() interface{} {
+				return NewDeriveAddressesCmd("wpkh(tpub/0/*)", &DescriptorRange{Begin: 1, End: 5})
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"deriveaddresses","params":["wpkh(tpub/0/*)",[1,5]],"id":1}`,
+			unmarshalled: &DeriveAddressesCmd{
+				Descriptor: "wpkh(tpub/0/*)",
+				Range:      &DescriptorRange{Begin: 1, End: 5},
+			},
+		},
+		{
+			name: "finalizepsbt",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("finalizepsbt", "cHNidP8=")
+			},
+			staticCmd: func() interface{} {
+				return NewFinalizePsbtCmd("cHNidP8=", nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"finalizepsbt","params":["cHNidP8="],"id":1}`,
+			unmarshalled: &FinalizePsbtCmd{
+				Psbt:    "cHNidP8=",
+				Extract: Bool(true),
+			},
+		},
+		{
+			name: "finalizepsbt optional",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("finalizepsbt", "cHNidP8=", false)
+			},
+			staticCmd: func() interface{} {
+				return NewFinalizePsbtCmd("cHNidP8=", Bool(false))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"finalizepsbt","params":["cHNidP8=",false],"id":1}`,
+			unmarshalled: &FinalizePsbtCmd{
+				Psbt:    "cHNidP8=",
+				Extract: Bool(false),
+			},
+		},
 		{
 			name: "getaddednodeinfo",
 			newCmd: func() (interface{}, error) {
@@ -126,6 +228,72 @@ func TestChainSvrCmds(t *testing.T) {
 				Node: String("127.0.0.1"),
 			},
 		},
+		{
+			name: "getaddressbalance",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("getaddressbalance", GetAddressBalanceRequest{
+					Addresses: []string{"1Address"},
+				})
+			},
+			staticCmd: func() interface{} {
+				return NewGetAddressBalanceCmd([]string{"1Address"}, false)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getaddressbalance","params":[{"addresses":["1Address"]}],"id":1}`,
+			unmarshalled: &GetAddressBalanceCmd{
+				Request: GetAddressBalanceRequest{
+					Addresses: []string{"1Address"},
+				},
+			},
+		},
+		{
+			name: "getaddressbalance mempool",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("getaddressbalance", `{"addresses":["1Address","1Other"],"mempool":true}`)
+			},
+			staticCmd: func() interface{} {
+				return NewGetAddressBalanceCmd([]string{"1Address", "1Other"}, true)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getaddressbalance","params":[{"addresses":["1Address","1Other"],"mempool":true}],"id":1}`,
+			unmarshalled: &GetAddressBalanceCmd{
+				Request: GetAddressBalanceRequest{
+					Addresses: []string{"1Address", "1Other"},
+					Mempool:   true,
+				},
+			},
+		},
+		{
+			name: "getaddressutxos",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("getaddressutxos", GetAddressUtxosRequest{
+					Addresses: []string{"1Address"},
+				})
+			},
+			staticCmd: func() interface{} {
+				return NewGetAddressUtxosCmd([]string{"1Address"}, false)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getaddressutxos","params":[{"addresses":["1Address"]}],"id":1}`,
+			unmarshalled: &GetAddressUtxosCmd{
+				Request: GetAddressUtxosRequest{
+					Addresses: []string{"1Address"},
+				},
+			},
+		},
+		{
+			name: "getaddressutxos chaininfo",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("getaddressutxos", `{"addresses":["1Address","1Other"],"chainInfo":true}`)
+			},
+			staticCmd: func() interface{} {
+				return NewGetAddressUtxosCmd([]string{"1Address", "1Other"}, true)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getaddressutxos","params":[{"addresses":["1Address","1Other"],"chainInfo":true}],"id":1}`,
+			unmarshalled: &GetAddressUtxosCmd{
+				Request: GetAddressUtxosRequest{
+					Addresses: []string{"1Address", "1Other"},
+					ChainInfo: true,
+				},
+			},
+		},
 		{
 			name: "getbestblockhash",
 			newCmd: func() (interface{}, error) {
@@ -208,6 +376,48 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"getblockcount","params":[],"id":1}`,
 			unmarshalled: &GetBlockCountCmd{},
 		},
+		{
+			name: "getblockfilter",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("getblockfilter", "123")
+			},
+			staticCmd: func() interface{} {
+				return NewGetBlockFilterCmd("123", nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getblockfilter","params":["123"],"id":1}`,
+			unmarshalled: &GetBlockFilterCmd{
+				BlockHash:  "123",
+				FilterType: String("basic"),
+			},
+		},
+		{
+			name: "getblockfilter optional",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("getblockfilter", "123", "basic")
+			},
+			staticCmd: func() interface{} {
+				return NewGetBlockFilterCmd("123", String("basic"))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getblockfilter","params":["123","basic"],"id":1}`,
+			unmarshalled: &GetBlockFilterCmd{
+				BlockHash:  "123",
+				FilterType: String("basic"),
+			},
+		},
+		{
+			name: "getblockfrompeer",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("getblockfrompeer", "123", 7)
+			},
+			staticCmd: func() interface{} {
+				return NewGetBlockFromPeerCmd("123", 7)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getblockfrompeer","params":["123",7],"id":1}`,
+			unmarshalled: &GetBlockFromPeerCmd{
+				BlockHash: "123",
+				PeerID:    7,
+			},
+		},
 		{
 			name: "getblockhash",
 			newCmd: func() (interface{}, error) {
@@ -233,6 +443,46 @@ func TestChainSvrCmds(t *testing.T) {
 				Verbose: Bool(true),
 			},
 		},
+		{
+			name: "getblockstats height",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("getblockstats", HashOrHeight{Value: 123})
+			},
+			staticCmd: func() interface{} {
+				return NewGetBlockStatsCmd(HashOrHeight{Value: 123}, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getblockstats","params":[123],"id":1}`,
+			unmarshalled: &GetBlockStatsCmd{
+				HashOrHeight: HashOrHeight{Value: 123},
+			},
+		},
+		{
+			name: "getblockstats hash",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("getblockstats", HashOrHeight{Value: "deadbeef"})
+			},
+			staticCmd: func() interface{} {
+				return NewGetBlockStatsCmd(HashOrHeight{Value: "deadbeef"}, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getblockstats","params":["deadbeef"],"id":1}`,
+			unmarshalled: &GetBlockStatsCmd{
+				HashOrHeight: HashOrHeight{Value: "deadbeef"},
+			},
+		},
+		{
+			name: "getblockstats optional stats",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("getblockstats", HashOrHeight{Value: 123}, []string{"avgfeerate", "height"})
+			},
+			staticCmd: func() interface{} {
+				return NewGetBlockStatsCmd(HashOrHeight{Value: 123}, &[]string{"avgfeerate", "height"})
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getblockstats","params":[123,["avgfeerate","height"]],"id":1}`,
+			unmarshalled: &GetBlockStatsCmd{
+				HashOrHeight: HashOrHeight{Value: 123},
+				Stats:        &[]string{"avgfeerate", "height"},
+			},
+		},
 		{
 			name: "getblocktemplate",
 			newCmd: func() (interface{}, error) {
@@ -264,6 +514,28 @@ func TestChainSvrCmds(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "getblocktemplate optional - template request with rules",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("getblocktemplate", `{"mode":"template","capabilities":["coinbasevalue"],"rules":["segwit"]}`)
+			},
+			staticCmd: func() interface{} {
+				template := TemplateRequest{
+					Mode:         "template",
+					Capabilities: []string{"coinbasevalue"},
+					Rules:        []string{"segwit"},
+				}
+				return NewGetBlockTemplateCmd(&template)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getblocktemplate","params":[{"mode":"template","capabilities":["coinbasevalue"],"rules":["segwit"]}],"id":1}`,
+			unmarshalled: &GetBlockTemplateCmd{
+				Request: &TemplateRequest{
+					Mode:         "template",
+					Capabilities: []string{"coinbasevalue"},
+					Rules:        []string{"segwit"},
+				},
+			},
+		},
 		{
 			name: "getblocktemplate optional - template request with tweaks",
 			newCmd: func() (interface{}, error) {
@@ -327,6 +599,44 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"getchaintips","params":[],"id":1}`,
 			unmarshalled: &GetChainTipsCmd{},
 		},
+		{
+			name: "getchaintxstats",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("getchaintxstats")
+			},
+			staticCmd: func() interface{} {
+				return NewGetChainTxStatsCmd(nil, nil)
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getchaintxstats","params":[],"id":1}`,
+			unmarshalled: &GetChainTxStatsCmd{},
+		},
+		{
+			name: "getchaintxstats optional nblocks",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("getchaintxstats", Int32(1000))
+			},
+			staticCmd: func() interface{} {
+				return NewGetChainTxStatsCmd(Int32(1000), nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getchaintxstats","params":[1000],"id":1}`,
+			unmarshalled: &GetChainTxStatsCmd{
+				NBlocks: Int32(1000),
+			},
+		},
+		{
+			name: "getchaintxstats optional nblocks and blockhash",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("getchaintxstats", Int32(1000), String("0000afaf"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetChainTxStatsCmd(Int32(1000), String("0000afaf"))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getchaintxstats","params":[1000,"0000afaf"],"id":1}`,
+			unmarshalled: &GetChainTxStatsCmd{
+				NBlocks:   Int32(1000),
+				BlockHash: String("0000afaf"),
+			},
+		},
 		{
 			name: "getconnectioncount",
 			newCmd: func() (interface{}, error) {
@@ -338,6 +648,17 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"getconnectioncount","params":[],"id":1}`,
 			unmarshalled: &GetConnectionCountCmd{},
 		},
+		{
+			name: "getdescriptorinfo",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("getdescriptorinfo", "wpkh(tpub/0/*)")
+			},
+			staticCmd: func() interface{} {
+				return NewGetDescriptorInfoCmd("wpkh(tpub/0/*)")
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getdescriptorinfo","params":["wpkh(tpub/0/*)"],"id":1}`,
+			unmarshalled: &GetDescriptorInfoCmd{Descriptor: "wpkh(tpub/0/*)"},
+		},
 		{
 			name: "getdifficulty",
 			newCmd: func() (interface{}, error) {
@@ -518,6 +839,19 @@ func TestChainSvrCmds(t *testing.T) {
 				Verbose: Bool(false),
 			},
 		},
+		{
+			name: "getrawmempool verbose",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("getrawmempool", true)
+			},
+			staticCmd: func() interface{} {
+				return NewGetRawMempoolCmd(Bool(true))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getrawmempool","params":[true],"id":1}`,
+			unmarshalled: &GetRawMempoolCmd{
+				Verbose: Bool(true),
+			},
+		},
 		{
 			name: "getrawtransaction",
 			newCmd: func() (interface{}, error) {
@@ -614,8 +948,43 @@ func TestChainSvrCmds(t *testing.T) {
 			staticCmd: func() interface{} {
 				return NewGetTxOutSetInfoCmd()
 			},
-			marshalled:   `{"jsonrpc":"1.0","method":"gettxoutsetinfo","params":[],"id":1}`,
-			unmarshalled: &GetTxOutSetInfoCmd{},
+			marshalled: `{"jsonrpc":"1.0","method":"gettxoutsetinfo","params":[],"id":1}`,
+			unmarshalled: &GetTxOutSetInfoCmd{
+				UseIndex: Bool(true),
+			},
+		},
+		{
+			name: "gettxoutsetinfo use index",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("gettxoutsetinfo", (*string)(nil), (*HashOrHeight)(nil), false)
+			},
+			staticCmd: func() interface{} {
+				cmd := NewGetTxOutSetInfoCmd()
+				cmd.UseIndex = Bool(false)
+				return cmd
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"gettxoutsetinfo","params":[null,null,false],"id":1}`,
+			unmarshalled: &GetTxOutSetInfoCmd{
+				UseIndex: Bool(false),
+			},
+		},
+		{
+			name: "gettxoutsetinfo at height",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("gettxoutsetinfo", "muhash", HashOrHeight{Value: 1000})
+			},
+			staticCmd: func() interface{} {
+				cmd := NewGetTxOutSetInfoCmd()
+				cmd.HashType = String("muhash")
+				cmd.HashOrHeight = &HashOrHeight{Value: 1000}
+				return cmd
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"gettxoutsetinfo","params":["muhash",1000],"id":1}`,
+			unmarshalled: &GetTxOutSetInfoCmd{
+				HashType:     String("muhash"),
+				HashOrHeight: &HashOrHeight{Value: 1000},
+				UseIndex:     Bool(true),
+			},
 		},
 		{
 			name: "getwork",
@@ -643,6 +1012,17 @@ func TestChainSvrCmds(t *testing.T) {
 				Data: String("00112233"),
 			},
 		},
+		{
+			name: "getzmqnotifications",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("getzmqnotifications")
+			},
+			staticCmd: func() interface{} {
+				return NewGetZmqNotificationsCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getzmqnotifications","params":[],"id":1}`,
+			unmarshalled: &GetZmqNotificationsCmd{},
+		},
 		{
 			name: "help",
 			newCmd: func() (interface{}, error) {
@@ -719,6 +1099,42 @@ func TestChainSvrCmds(t *testing.T) {
 				BlockHash: "123",
 			},
 		},
+		{
+			name: "scantxoutset",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("scantxoutset", "status")
+			},
+			staticCmd: func() interface{} {
+				return NewScanTxOutSetCmd("status", nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"scantxoutset","params":["status"],"id":1}`,
+			unmarshalled: &ScanTxOutSetCmd{
+				Action: "status",
+			},
+		},
+		{
+			name: "scantxoutset scan objects",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("scantxoutset", "start", []ScanObject{
+					{Descriptor: "addr(mkmZxiEcEd8ZqjQWVZuC6so5dFMKEFpN2j)"},
+					{Descriptor: "wpkh(tpub/0/*)", Range: &DescriptorRange{Begin: 1, End: 5}},
+				})
+			},
+			staticCmd: func() interface{} {
+				return NewScanTxOutSetCmd("start", &[]ScanObject{
+					{Descriptor: "addr(mkmZxiEcEd8ZqjQWVZuC6so5dFMKEFpN2j)"},
+					{Descriptor: "wpkh(tpub/0/*)", Range: &DescriptorRange{Begin: 1, End: 5}},
+				})
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"scantxoutset","params":["start",["addr(mkmZxiEcEd8ZqjQWVZuC6so5dFMKEFpN2j)",{"desc":"wpkh(tpub/0/*)","range":[1,5]}]],"id":1}`,
+			unmarshalled: &ScanTxOutSetCmd{
+				Action: "start",
+				ScanObjects: &[]ScanObject{
+					{Descriptor: "addr(mkmZxiEcEd8ZqjQWVZuC6so5dFMKEFpN2j)"},
+					{Descriptor: "wpkh(tpub/0/*)", Range: &DescriptorRange{Begin: 1, End: 5}},
+				},
+			},
+		},
 		{
 			name: "searchrawtransactions",
 			newCmd: func() (interface{}, error) {
@@ -914,6 +1330,58 @@ func TestChainSvrCmds(t *testing.T) {
 				GenProcLimit: Int(6),
 			},
 		},
+		{
+			name: "signrawtransactionwithkey",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("signrawtransactionwithkey", "001122", `["abc"]`)
+			},
+			staticCmd: func() interface{} {
+				return NewSignRawTransactionWithKeyCmd("001122", []string{"abc"}, nil, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"signrawtransactionwithkey","params":["001122",["abc"]],"id":1}`,
+			unmarshalled: &SignRawTransactionWithKeyCmd{
+				RawTx:       "001122",
+				PrivKeys:    []string{"abc"},
+				PrevTxs:     nil,
+				SigHashType: String("ALL"),
+			},
+		},
+		{
+			name: "signrawtransactionwithkey optional",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("signrawtransactionwithkey", "001122", `["abc"]`,
+					`[{"txid":"123","vout":1,"scriptPubKey":"00","witnessScript":"01","amount":0.5}]`,
+					"SINGLE")
+			},
+			staticCmd: func() interface{} {
+				prevTxs := []RawTxWitnessInput{
+					{
+						Txid:          "123",
+						Vout:          1,
+						ScriptPubKey:  "00",
+						WitnessScript: String("01"),
+						Amount:        Float64(0.5),
+					},
+				}
+				return NewSignRawTransactionWithKeyCmd("001122", []string{"abc"},
+					&prevTxs, String("SINGLE"))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"signrawtransactionwithkey","params":["001122",["abc"],[{"txid":"123","vout":1,"scriptPubKey":"00","witnessScript":"01","amount":0.5}],"SINGLE"],"id":1}`,
+			unmarshalled: &SignRawTransactionWithKeyCmd{
+				RawTx:    "001122",
+				PrivKeys: []string{"abc"},
+				PrevTxs: &[]RawTxWitnessInput{
+					{
+						Txid:          "123",
+						Vout:          1,
+						ScriptPubKey:  "00",
+						WitnessScript: String("01"),
+						Amount:        Float64(0.5),
+					},
+				},
+				SigHashType: String("SINGLE"),
+			},
+		},
 		{
 			name: "stop",
 			newCmd: func() (interface{}, error) {
@@ -958,6 +1426,33 @@ func TestChainSvrCmds(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "testmempoolaccept",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("testmempoolaccept", []string{"01000000", "02000000"})
+			},
+			staticCmd: func() interface{} {
+				return NewTestMempoolAcceptCmd([]string{"01000000", "02000000"}, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"testmempoolaccept","params":[["01000000","02000000"]],"id":1}`,
+			unmarshalled: &TestMempoolAcceptCmd{
+				RawTxns: []string{"01000000", "02000000"},
+			},
+		},
+		{
+			name: "testmempoolaccept optional",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("testmempoolaccept", []string{"01000000"}, 0.5)
+			},
+			staticCmd: func() interface{} {
+				return NewTestMempoolAcceptCmd([]string{"01000000"}, Float64(0.5))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"testmempoolaccept","params":[["01000000"],0.5],"id":1}`,
+			unmarshalled: &TestMempoolAcceptCmd{
+				RawTxns:    []string{"01000000"},
+				MaxFeeRate: Float64(0.5),
+			},
+		},
 		{
 			name: "uptime",
 			newCmd: func() (interface{}, error) {
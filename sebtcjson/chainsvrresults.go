@@ -5,7 +5,41 @@
 
 package sebtcjson
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// GetAddressBalanceResult models the data returned from the getaddressbalance
+// command.  The amounts are in satoshi.  The mempool balance is only reported
+// when the unconfirmed balance is requested.
+type GetAddressBalanceResult struct {
+	Balance        int64 `json:"balance"`
+	Received       int64 `json:"received"`
+	MempoolBalance int64 `json:"mempoolBalance,omitempty"`
+}
+
+// AddressUtxo models an unspent output returned by the getaddressutxos command.
+type AddressUtxo struct {
+	Address     string `json:"address"`
+	TxID        string `json:"txid"`
+	OutputIndex uint32 `json:"outputIndex"`
+	Script      string `json:"script"`
+	Satoshis    int64  `json:"satoshis"`
+	Height      int64  `json:"height"`
+}
+
+// GetAddressUtxosChainInfoResult models the data returned from the
+// getaddressutxos command when chain info is requested.  The hash and height
+// are those of the chain tip the unspent outputs were read at.
+type GetAddressUtxosChainInfoResult struct {
+	Utxos  []AddressUtxo `json:"utxos"`
+	Hash   string        `json:"hash"`
+	Height int64         `json:"height"`
+}
 
 // GetBlockHeaderVerboseResult models the data from the getblockheader command when
 // the verbose flag is set.  When the verbose flag is not set, getblockheader
@@ -48,6 +82,47 @@ type GetBlockVerboseResult struct {
 	NextHash      string        `json:"nextblockhash,omitempty"`
 }
 
+// GetBlockVerboseTxResult models the data from the getblock command when the
+// verbosity is 2, which reports every transaction of the block decoded in full
+// rather than only its hash.
+type GetBlockVerboseTxResult struct {
+	GetBlockHeaderVerboseResult
+	StrippedSize int32         `json:"strippedsize"`
+	Size         int32         `json:"size"`
+	Weight       int32         `json:"weight"`
+	Tx           []TxRawResult `json:"tx"`
+}
+
+// AnalyzePsbtInputMissing models the data an input of a PSBT is missing before
+// it can be finalized, as returned by the analyzepsbt command.
+type AnalyzePsbtInputMissing struct {
+	Pubkeys       []string `json:"pubkeys,omitempty"`
+	Signatures    []string `json:"signatures,omitempty"`
+	RedeemScript  string   `json:"redeemscript,omitempty"`
+	WitnessScript string   `json:"witnessscript,omitempty"`
+}
+
+// AnalyzePsbtInput models the data of an input returned by the analyzepsbt
+// command.
+type AnalyzePsbtInput struct {
+	HasUtxo bool                     `json:"has_utxo"`
+	IsFinal bool                     `json:"is_final"`
+	Missing *AnalyzePsbtInputMissing `json:"missing,omitempty"`
+	Next    string                   `json:"next,omitempty"`
+}
+
+// AnalyzePsbtResult models the data returned from the analyzepsbt command.
+// The estimates and the fee are only present once all of the spent outputs are
+// known.  The fee rate is in BTC/kB.
+type AnalyzePsbtResult struct {
+	Inputs           []AnalyzePsbtInput `json:"inputs,omitempty"`
+	EstimatedVSize   *int64             `json:"estimated_vsize,omitempty"`
+	EstimatedFeeRate *float64           `json:"estimated_feerate,omitempty"`
+	Fee              *float64           `json:"fee,omitempty"`
+	Next             string             `json:"next"`
+	Error            string             `json:"error,omitempty"`
+}
+
 // CreateMultiSigResult models the data returned from the createmultisig
 // command.
 type CreateMultiSigResult struct {
@@ -55,6 +130,63 @@ type CreateMultiSigResult struct {
 	RedeemScript string `json:"redeemScript"`
 }
 
+// DecodePsbtScript models a redeem or witness script of a PSBT as returned by
+// the decodepsbt command.
+type DecodePsbtScript struct {
+	Asm  string `json:"asm"`
+	Hex  string `json:"hex"`
+	Type string `json:"type"`
+}
+
+// DecodePsbtBip32Deriv models the BIP 32 derivation path of a public key of a
+// PSBT as returned by the decodepsbt command.
+type DecodePsbtBip32Deriv struct {
+	PubKey            string `json:"pubkey"`
+	MasterFingerprint string `json:"master_fingerprint"`
+	Path              string `json:"path"`
+}
+
+// DecodePsbtWitnessUtxo models the segwit output spent by an input of a PSBT
+// as returned by the decodepsbt command.  The amount is in BTC.
+type DecodePsbtWitnessUtxo struct {
+	Amount       float64            `json:"amount"`
+	ScriptPubKey ScriptPubKeyResult `json:"scriptPubKey"`
+}
+
+// DecodePsbtInput models the data of an input returned by the decodepsbt
+// command.  The partial signatures are keyed by public key.
+type DecodePsbtInput struct {
+	NonWitnessUtxo     *TxRawResult           `json:"non_witness_utxo,omitempty"`
+	WitnessUtxo        *DecodePsbtWitnessUtxo `json:"witness_utxo,omitempty"`
+	PartialSignatures  map[string]string      `json:"partial_signatures,omitempty"`
+	Sighash            string                 `json:"sighash,omitempty"`
+	RedeemScript       *DecodePsbtScript      `json:"redeem_script,omitempty"`
+	WitnessScript      *DecodePsbtScript      `json:"witness_script,omitempty"`
+	Bip32Derivs        []DecodePsbtBip32Deriv `json:"bip32_derivs,omitempty"`
+	FinalScriptSig     *ScriptSig             `json:"final_scriptsig,omitempty"`
+	FinalScriptWitness []string               `json:"final_scriptwitness,omitempty"`
+	Unknown            map[string]string      `json:"unknown,omitempty"`
+}
+
+// DecodePsbtOutput models the data of an output returned by the decodepsbt
+// command.
+type DecodePsbtOutput struct {
+	RedeemScript  *DecodePsbtScript      `json:"redeem_script,omitempty"`
+	WitnessScript *DecodePsbtScript      `json:"witness_script,omitempty"`
+	Bip32Derivs   []DecodePsbtBip32Deriv `json:"bip32_derivs,omitempty"`
+	Unknown       map[string]string      `json:"unknown,omitempty"`
+}
+
+// DecodePsbtResult models the data returned from the decodepsbt command.  The
+// fee is only present once all of the spent outputs are known.
+type DecodePsbtResult struct {
+	Tx      TxRawDecodeResult  `json:"tx"`
+	Unknown map[string]string  `json:"unknown"`
+	Inputs  []DecodePsbtInput  `json:"inputs"`
+	Outputs []DecodePsbtOutput `json:"outputs"`
+	Fee     *float64           `json:"fee,omitempty"`
+}
+
 // DecodeScriptResult models the data returned from the decodescript command.
 type DecodeScriptResult struct {
 	Asm       string   `json:"asm"`
@@ -64,6 +196,25 @@ type DecodeScriptResult struct {
 	P2sh      string   `json:"p2sh,omitempty"`
 }
 
+// FinalizePsbtResult models the data returned from the finalizepsbt command.
+// The network serialized transaction is only returned once the PSBT is
+// complete and was extracted, and the PSBT otherwise.
+type FinalizePsbtResult struct {
+	Psbt     string `json:"psbt,omitempty"`
+	Hex      string `json:"hex,omitempty"`
+	Complete bool   `json:"complete"`
+}
+
+// GetDescriptorInfoResult models the data returned from the getdescriptorinfo
+// command.
+type GetDescriptorInfoResult struct {
+	Descriptor     string `json:"descriptor"`
+	Checksum       string `json:"checksum"`
+	IsRange        bool   `json:"isrange"`
+	IsSolvable     bool   `json:"issolvable"`
+	HasPrivateKeys bool   `json:"hasprivatekeys"`
+}
+
 // GetAddedNodeInfoResultAddr models the data of the addresses portion of the
 // getaddednodeinfo command.
 type GetAddedNodeInfoResultAddr struct {
@@ -98,11 +249,23 @@ type SoftForkDescription struct {
 // Bip9SoftForkDescription describes the current state of a defined BIP0009
 // version bits soft-fork.
 type Bip9SoftForkDescription struct {
-	Status    string `json:"status"`
-	Bit       uint8  `json:"bit"`
-	StartTime int64  `json:"startTime"`
-	Timeout   int64  `json:"timeout"`
-	Since     int32  `json:"since"`
+	Status     string                  `json:"status"`
+	Bit        uint8                   `json:"bit"`
+	StartTime  int64                   `json:"startTime"`
+	Timeout    int64                   `json:"timeout"`
+	Since      int32                   `json:"since"`
+	Statistics *Bip9SoftForkStatistics `json:"statistics,omitempty"`
+}
+
+// Bip9SoftForkStatistics describes the signalling for a BIP0009 version bits
+// soft-fork within the current retarget period.  It is only reported while the
+// soft-fork is in the started state.
+type Bip9SoftForkStatistics struct {
+	Period    int32 `json:"period"`
+	Threshold int32 `json:"threshold"`
+	Elapsed   int32 `json:"elapsed"`
+	Count     int32 `json:"count"`
+	Possible  bool  `json:"possible"`
 }
 
 // GetBlockChainInfoResult models the data returned from the getblockchaininfo
@@ -117,15 +280,114 @@ type GetBlockChainInfoResult struct {
 	VerificationProgress float64                             `json:"verificationprogress,omitempty"`
 	Pruned               bool                                `json:"pruned"`
 	PruneHeight          int32                               `json:"pruneheight,omitempty"`
+	AutomaticPruning     *bool                               `json:"automatic_pruning,omitempty"`
+	PruneTargetSize      *int64                              `json:"prune_target_size,omitempty"`
 	ChainWork            string                              `json:"chainwork,omitempty"`
 	SoftForks            []*SoftForkDescription              `json:"softforks"`
 	Bip9SoftForks        map[string]*Bip9SoftForkDescription `json:"bip9_softforks"`
+	Warnings             Warnings                            `json:"warnings,omitempty"`
+}
+
+// Warnings models the warnings field of the getnetworkinfo and
+// getblockchaininfo results.  Older servers report the warnings as a single
+// string while newer ones report an array of strings.  Both are normalized to a
+// list which is empty when there are no warnings.
+type Warnings []string
+
+// UnmarshalJSON provides a custom Unmarshal method for Warnings which accepts
+// both the string and the array representations.
+func (w *Warnings) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			return err
+		}
+		*w = nil
+		if str != "" {
+			*w = Warnings{str}
+		}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*w = Warnings(list)
+	return nil
+}
+
+// GetBlockFilterResult models the data returned from the getblockfilter
+// command.
+type GetBlockFilterResult struct {
+	Filter string `json:"filter"`
+	Header string `json:"header"`
+}
+
+// GetBlockStatsResult models the data returned from the getblockstats command.
+// Fees and amounts are in satoshis and fee rates in satoshis per virtual byte.
+// Only the requested stats are populated when the stats parameter is provided.
+type GetBlockStatsResult struct {
+	AverageFee         int64   `json:"avgfee"`
+	AverageFeeRate     int64   `json:"avgfeerate"`
+	AverageTxSize      int64   `json:"avgtxsize"`
+	Hash               string  `json:"blockhash"`
+	FeeRatePercentiles []int64 `json:"feerate_percentiles"`
+	Height             int64   `json:"height"`
+	Ins                int64   `json:"ins"`
+	MaxFee             int64   `json:"maxfee"`
+	MaxFeeRate         int64   `json:"maxfeerate"`
+	MaxTxSize          int64   `json:"maxtxsize"`
+	MedianFee          int64   `json:"medianfee"`
+	MedianTime         int64   `json:"mediantime"`
+	MedianTxSize       int64   `json:"mediantxsize"`
+	MinFee             int64   `json:"minfee"`
+	MinFeeRate         int64   `json:"minfeerate"`
+	MinTxSize          int64   `json:"mintxsize"`
+	Outs               int64   `json:"outs"`
+	SegWitTotalSize    int64   `json:"swtotal_size"`
+	SegWitTotalWeight  int64   `json:"swtotal_weight"`
+	SegWitTxs          int64   `json:"swtxs"`
+	Subsidy            int64   `json:"subsidy"`
+	Time               int64   `json:"time"`
+	TotalOut           int64   `json:"total_out"`
+	TotalSize          int64   `json:"total_size"`
+	TotalWeight        int64   `json:"total_weight"`
+	TotalFee           int64   `json:"totalfee"`
+	Txs                int64   `json:"txs"`
+	UTXOIncrease       int64   `json:"utxo_increase"`
+	UTXOSizeIncrease   int64   `json:"utxo_size_inc"`
+}
+
+// GetChainTipsResult models the data returned from the getchaintips command.
+// The status is one of active, valid-fork, valid-headers, headers-only and
+// invalid.
+type GetChainTipsResult struct {
+	Height    int32  `json:"height"`
+	Hash      string `json:"hash"`
+	BranchLen int32  `json:"branchlen"`
+	Status    string `json:"status"`
+}
+
+// GetChainTxStatsResult models the data returned from the getchaintxstats
+// command.  The window interval and the transaction rate are only present when
+// the window holds more than one block.
+type GetChainTxStatsResult struct {
+	Time                   int64   `json:"time"`
+	TxCount                int64   `json:"txcount"`
+	WindowFinalBlockHash   string  `json:"window_final_block_hash"`
+	WindowFinalBlockHeight int32   `json:"window_final_block_height"`
+	WindowBlockCount       int32   `json:"window_block_count"`
+	WindowTxCount          int64   `json:"window_tx_count,omitempty"`
+	WindowInterval         int64   `json:"window_interval,omitempty"`
+	TxRate                 float64 `json:"txrate,omitempty"`
 }
 
 // GetBlockTemplateResultTx models the transactions field of the
 // getblocktemplate command.
 type GetBlockTemplateResultTx struct {
 	Data    string  `json:"data"`
+	TxID    string  `json:"txid,omitempty"`
 	Hash    string  `json:"hash"`
 	Depends []int64 `json:"depends"`
 	Fee     int64   `json:"fee"`
@@ -181,8 +443,23 @@ type GetBlockTemplateResult struct {
 	RejectReasion string   `json:"reject-reason,omitempty"`
 }
 
+// GetMempoolEntryFeesResult models the fees nested in the data returned from
+// the getmempoolentry command by Bitcoin Core 0.17 and later.  The fees are in
+// BTC.
+type GetMempoolEntryFeesResult struct {
+	Base       float64 `json:"base"`
+	Modified   float64 `json:"modified"`
+	Ancestor   float64 `json:"ancestor"`
+	Descendant float64 `json:"descendant"`
+}
+
 // GetMempoolEntryResult models the data returned from the getmempoolentry
 // command.
+//
+// Fee and ModifiedFee are in BTC, while DescendantFees and AncestorFees are in
+// satoshi.  Servers which only report the fees nested under fees, such as
+// Bitcoin Core 23.0 and later, have them copied to the flat fields, so the flat
+// fields may be used with every server version.
 type GetMempoolEntryResult struct {
 	Size             int32    `json:"size"`
 	Fee              float64  `json:"fee"`
@@ -198,13 +475,54 @@ type GetMempoolEntryResult struct {
 	AncestorSize     int64    `json:"ancestorsize"`
 	AncestorFees     float64  `json:"ancestorfees"`
 	Depends          []string `json:"depends"`
+
+	// BIP125Replaceable is only reported by Bitcoin Core 0.19 and later.
+	BIP125Replaceable *bool `json:"bip125-replaceable,omitempty"`
+
+	// Fees is only reported by Bitcoin Core 0.17 and later.
+	Fees *GetMempoolEntryFeesResult `json:"fees,omitempty"`
+}
+
+// UnmarshalJSON provides a custom Unmarshal method for GetMempoolEntryResult
+// which fills the flat fee fields from the nested fees when the server does
+// not report them.
+func (r *GetMempoolEntryResult) UnmarshalJSON(data []byte) error {
+	type result GetMempoolEntryResult
+	aux := struct {
+		*result
+		Fee *float64 `json:"fee"`
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if aux.Fee != nil || r.Fees == nil {
+		if aux.Fee != nil {
+			r.Fee = *aux.Fee
+		}
+		return nil
+	}
+	r.Fee = r.Fees.Base
+	r.ModifiedFee = r.Fees.Modified
+	r.AncestorFees = satoshiFee(r.Fees.Ancestor)
+	r.DescendantFees = satoshiFee(r.Fees.Descendant)
+	return nil
+}
+
+// satoshiFee converts the passed fee in BTC to satoshi, which is the unit of
+// the flat ancestor and descendant fees of memory pool entries.
+func satoshiFee(btc float64) float64 {
+	return math.Round(btc * 1e8)
 }
 
 // GetMempoolInfoResult models the data returned from the getmempoolinfo
 // command.
 type GetMempoolInfoResult struct {
-	Size  int64 `json:"size"`
-	Bytes int64 `json:"bytes"`
+	Size          int64   `json:"size"`
+	Bytes         int64   `json:"bytes"`
+	Usage         int64   `json:"usage,omitempty"`
+	MaxMempool    int64   `json:"maxmempool,omitempty"`
+	MempoolMinFee float64 `json:"mempoolminfee,omitempty"`
+	MinRelayTxFee float64 `json:"minrelaytxfee,omitempty"`
 }
 
 // NetworksResult models the networks data from the getnetworkinfo command.
@@ -224,8 +542,89 @@ type LocalAddressesResult struct {
 	Score   int32  `json:"score"`
 }
 
+// ServiceFlag identifies the services supported by a peer.  The values match
+// the service flags advertised on the peer-to-peer network.
+type ServiceFlag uint64
+
+const (
+	// SFNodeNetwork indicates a peer is a full node (NODE_NETWORK).
+	SFNodeNetwork ServiceFlag = 1 << 0
+
+	// SFNodeGetUTXO indicates a peer supports the getutxos and utxos
+	// commands (NODE_GETUTXO).
+	SFNodeGetUTXO ServiceFlag = 1 << 1
+
+	// SFNodeBloom indicates a peer supports bloom filtering (NODE_BLOOM).
+	SFNodeBloom ServiceFlag = 1 << 2
+
+	// SFNodeWitness indicates a peer supports blocks and transactions
+	// including witness data (NODE_WITNESS).
+	SFNodeWitness ServiceFlag = 1 << 3
+
+	// SFNodeCompactFilters indicates a peer supports serving compact
+	// block filters (NODE_COMPACT_FILTERS).
+	SFNodeCompactFilters ServiceFlag = 1 << 6
+
+	// SFNodeNetworkLimited indicates a peer only serves the most recent
+	// blocks (NODE_NETWORK_LIMITED).
+	SFNodeNetworkLimited ServiceFlag = 1 << 10
+)
+
+// ServiceBits models the services field of the getpeerinfo and getnetworkinfo
+// results.  Servers report it as a hex-encoded string such as
+// "000000000000040d", while some versions report a plain integer.  Integers are
+// normalized to the hex-encoded form when unmarshalled.
+type ServiceBits string
+
+// UnmarshalJSON provides a custom Unmarshal method for ServiceBits which
+// accepts both the hex-encoded string and the integer representations.
+func (s *ServiceBits) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			return err
+		}
+		*s = ServiceBits(str)
+		return nil
+	}
+
+	var bits uint64
+	if err := json.Unmarshal(data, &bits); err != nil {
+		return err
+	}
+	*s = ServiceBits(fmt.Sprintf("%016x", bits))
+	return nil
+}
+
+// Flags parses the service bits into a ServiceFlag.
+func (s ServiceBits) Flags() (ServiceFlag, error) {
+	str := strings.TrimPrefix(strings.TrimPrefix(string(s), "0x"), "0X")
+	if str == "" {
+		return 0, nil
+	}
+	bits, err := strconv.ParseUint(str, 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid services %q: %v", string(s), err)
+	}
+	return ServiceFlag(bits), nil
+}
+
+// HasService returns whether or not the service bits include the passed
+// service.  Service bits which fail to parse do not include any service.
+func (s ServiceBits) HasService(flag ServiceFlag) bool {
+	flags, err := s.Flags()
+	if err != nil {
+		return false
+	}
+	return flags&flag == flag
+}
+
 // GetNetworkInfoResult models the data returned from the getnetworkinfo
 // command.
+//
+// LocalServices is always the hex-encoded form, even when the server reports an
+// integer.  Newer servers report the warnings as an array, which is joined with
+// "; " into Warnings.  Use WarningList for the individual warnings.
 type GetNetworkInfoResult struct {
 	Version         int32                  `json:"version"`
 	SubVersion      string                 `json:"subversion"`
@@ -240,9 +639,62 @@ type GetNetworkInfoResult struct {
 	IncrementalFee  float64                `json:"incrementalfee"`
 	LocalAddresses  []LocalAddressesResult `json:"localaddresses"`
 	Warnings        string                 `json:"warnings"`
+
+	// warnings houses the individual warnings when they were reported as
+	// an array.
+	warnings Warnings
+}
+
+// GetZmqNotificationsResult models the data returned from the
+// getzmqnotifications command, which holds one entry for each active ZMQ
+// publisher.
+type GetZmqNotificationsResult []struct {
+	Type    string `json:"type"`    // Type of the notification, such as pubrawblock
+	Address string `json:"address"` // Address of the publisher
+	HWM     int    `json:"hwm"`     // Outbound message high water mark
+}
+
+// UnmarshalJSON provides a custom Unmarshal method for GetNetworkInfoResult
+// which normalizes the representations of the services and warnings used
+// across server versions.
+func (r *GetNetworkInfoResult) UnmarshalJSON(data []byte) error {
+	type result GetNetworkInfoResult
+	aux := struct {
+		*result
+		LocalServices ServiceBits `json:"localservices"`
+		Warnings      Warnings    `json:"warnings"`
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	r.LocalServices = string(aux.LocalServices)
+	r.Warnings = strings.Join(aux.Warnings, "; ")
+	r.warnings = aux.Warnings
+	return nil
+}
+
+// WarningList returns the warnings reported by the server as a list which is
+// empty when there are no warnings.
+func (r *GetNetworkInfoResult) WarningList() []string {
+	if r.warnings != nil {
+		return r.warnings
+	}
+	if r.Warnings == "" {
+		return nil
+	}
+	return []string{r.Warnings}
+}
+
+// HasService returns whether or not the local node advertises the passed
+// service.
+func (r *GetNetworkInfoResult) HasService(flag ServiceFlag) bool {
+	return ServiceBits(r.LocalServices).HasService(flag)
 }
 
 // GetPeerInfoResult models the data returned from the getpeerinfo command.
+//
+// Services is always the hex-encoded form, even when the server reports an
+// integer.
 type GetPeerInfoResult struct {
 	ID             int32   `json:"id"`
 	Addr           string  `json:"addr"`
@@ -262,23 +714,88 @@ type GetPeerInfoResult struct {
 	Inbound        bool    `json:"inbound"`
 	StartingHeight int32   `json:"startingheight"`
 	CurrentHeight  int32   `json:"currentheight,omitempty"`
+	SyncedHeaders  int32   `json:"synced_headers,omitempty"`
+	SyncedBlocks   int32   `json:"synced_blocks,omitempty"`
 	BanScore       int32   `json:"banscore"`
 	FeeFilter      int64   `json:"feefilter"`
 	SyncNode       bool    `json:"syncnode"`
 }
 
+// UnmarshalJSON provides a custom Unmarshal method for GetPeerInfoResult which
+// accepts both the hex-encoded string and the integer representations of the
+// services.
+func (r *GetPeerInfoResult) UnmarshalJSON(data []byte) error {
+	type result GetPeerInfoResult
+	aux := struct {
+		*result
+		Services ServiceBits `json:"services"`
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	r.Services = string(aux.Services)
+	return nil
+}
+
+// HasService returns whether or not the peer advertises the passed service.
+func (r *GetPeerInfoResult) HasService(flag ServiceFlag) bool {
+	return ServiceBits(r.Services).HasService(flag)
+}
+
 // GetRawMempoolVerboseResult models the data returned from the getrawmempool
 // command when the verbose flag is set.  When the verbose flag is not set,
 // getrawmempool returns an array of transaction hashes.
+//
+// The fields mirror those of GetMempoolEntryResult, including the flat fee
+// fields which are filled from the nested fees when the server does not report
+// them.
 type GetRawMempoolVerboseResult struct {
 	Size             int32    `json:"size"`
 	Vsize            int32    `json:"vsize"`
 	Fee              float64  `json:"fee"`
+	ModifiedFee      float64  `json:"modifiedfee"`
 	Time             int64    `json:"time"`
 	Height           int64    `json:"height"`
 	StartingPriority float64  `json:"startingpriority"`
 	CurrentPriority  float64  `json:"currentpriority"`
+	DescendantCount  int64    `json:"descendantcount"`
+	DescendantSize   int64    `json:"descendantsize"`
+	DescendantFees   float64  `json:"descendantfees"`
+	AncestorCount    int64    `json:"ancestorcount"`
+	AncestorSize     int64    `json:"ancestorsize"`
+	AncestorFees     float64  `json:"ancestorfees"`
 	Depends          []string `json:"depends"`
+
+	// BIP125Replaceable is only reported by Bitcoin Core 0.19 and later.
+	BIP125Replaceable *bool `json:"bip125-replaceable,omitempty"`
+
+	// Fees is only reported by Bitcoin Core 0.17 and later.
+	Fees *GetMempoolEntryFeesResult `json:"fees,omitempty"`
+}
+
+// UnmarshalJSON provides a custom Unmarshal method for
+// GetRawMempoolVerboseResult which fills the flat fee fields from the nested
+// fees when the server does not report them.
+func (r *GetRawMempoolVerboseResult) UnmarshalJSON(data []byte) error {
+	type result GetRawMempoolVerboseResult
+	aux := struct {
+		*result
+		Fee *float64 `json:"fee"`
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if aux.Fee != nil || r.Fees == nil {
+		if aux.Fee != nil {
+			r.Fee = *aux.Fee
+		}
+		return nil
+	}
+	r.Fee = r.Fees.Base
+	r.ModifiedFee = r.Fees.Modified
+	r.AncestorFees = satoshiFee(r.Fees.Ancestor)
+	r.DescendantFees = satoshiFee(r.Fees.Descendant)
+	return nil
 }
 
 // ScriptPubKeyResult models the scriptPubKey data of a tx script.  It is
@@ -301,6 +818,66 @@ type GetTxOutResult struct {
 	Coinbase      bool               `json:"coinbase"`
 }
 
+// GetTxOutSetInfoResult models the data from the gettxoutsetinfo command.  The
+// hash and the transaction count reported depend on the server version and on
+// whether the coinstatsindex was used.
+type GetTxOutSetInfoResult struct {
+	Height                 int64   `json:"height"`
+	BestBlock              string  `json:"bestblock"`
+	Transactions           int64   `json:"transactions,omitempty"`
+	TxOuts                 int64   `json:"txouts"`
+	BogoSize               int64   `json:"bogosize"`
+	HashSerialized2        string  `json:"hash_serialized_2,omitempty"`
+	HashSerialized3        string  `json:"hash_serialized_3,omitempty"`
+	MuHash                 string  `json:"muhash,omitempty"`
+	DiskSize               int64   `json:"disk_size,omitempty"`
+	TotalAmount            float64 `json:"total_amount"`
+	TotalUnspendableAmount float64 `json:"total_unspendable_amount,omitempty"`
+}
+
+// ScanTxOutSetUnspent models an unspent output found by the scantxoutset
+// command.
+type ScanTxOutSetUnspent struct {
+	TxID         string  `json:"txid"`
+	Vout         uint32  `json:"vout"`
+	ScriptPubKey string  `json:"scriptPubKey"`
+	Descriptor   string  `json:"desc,omitempty"`
+	Amount       float64 `json:"amount"`
+	Height       int64   `json:"height"`
+}
+
+// ScanTxOutSetResult models the data returned from the scantxoutset command.
+// Only Progress is set for the status action while a scan is in progress, and
+// only Success for the abort action.  The amount is in BTC.
+type ScanTxOutSetResult struct {
+	Success     bool                  `json:"success"`
+	Progress    float64               `json:"progress,omitempty"`
+	TxOuts      int64                 `json:"txouts,omitempty"`
+	Height      int64                 `json:"height,omitempty"`
+	BestBlock   string                `json:"bestblock,omitempty"`
+	Unspents    []ScanTxOutSetUnspent `json:"unspents,omitempty"`
+	TotalAmount float64               `json:"total_amount,omitempty"`
+}
+
+// TestMempoolAcceptFees models the fees of a transaction returned from the
+// testmempoolaccept command.  The fee is in BTC.
+type TestMempoolAcceptFees struct {
+	Base float64 `json:"base"`
+}
+
+// TestMempoolAcceptResult models the data of a transaction returned from the
+// testmempoolaccept command.  The virtual size and the fees are only returned
+// for allowed transactions and by servers of version 0.21 or later, and the
+// reject reason only for rejected transactions.
+type TestMempoolAcceptResult struct {
+	TxID         string                 `json:"txid"`
+	Wtxid        string                 `json:"wtxid,omitempty"`
+	Allowed      bool                   `json:"allowed"`
+	Vsize        *int32                 `json:"vsize,omitempty"`
+	Fees         *TestMempoolAcceptFees `json:"fees,omitempty"`
+	RejectReason string                 `json:"reject-reason,omitempty"`
+}
+
 // GetNetTotalsResult models the data returned from the getnettotals command.
 type GetNetTotalsResult struct {
 	TotalBytesRecv uint64 `json:"totalbytesrecv"`
@@ -320,12 +897,23 @@ type ScriptSig struct {
 // getrawtransaction, decoderawtransaction, and searchrawtransaction use the
 // same structure.
 type Vin struct {
-	Coinbase  string     `json:"coinbase"`
-	Txid      string     `json:"txid"`
-	Vout      uint32     `json:"vout"`
-	ScriptSig *ScriptSig `json:"scriptSig"`
-	Sequence  uint32     `json:"sequence"`
-	Witness   []string   `json:"txinwitness"`
+	Coinbase  string       `json:"coinbase"`
+	Txid      string       `json:"txid"`
+	Vout      uint32       `json:"vout"`
+	ScriptSig *ScriptSig   `json:"scriptSig"`
+	Sequence  uint32       `json:"sequence"`
+	Witness   []string     `json:"txinwitness"`
+	PrevOut   *TxInPrevOut `json:"prevout,omitempty"`
+}
+
+// TxInPrevOut models the prevout object newer servers attach to the inputs of
+// a verbose transaction.  It is only present on some server versions and when
+// the spent output is known to the server.
+type TxInPrevOut struct {
+	Generated    bool               `json:"generated"`
+	Height       int64              `json:"height"`
+	Value        float64            `json:"value"`
+	ScriptPubKey ScriptPubKeyResult `json:"scriptPubKey"`
 }
 
 // IsCoinBase returns a bool to show if a Vin is a Coinbase one or not.
@@ -356,30 +944,34 @@ func (v *Vin) MarshalJSON() ([]byte, error) {
 
 	if v.HasWitness() {
 		txStruct := struct {
-			Txid      string     `json:"txid"`
-			Vout      uint32     `json:"vout"`
-			ScriptSig *ScriptSig `json:"scriptSig"`
-			Witness   []string   `json:"txinwitness"`
-			Sequence  uint32     `json:"sequence"`
+			Txid      string       `json:"txid"`
+			Vout      uint32       `json:"vout"`
+			ScriptSig *ScriptSig   `json:"scriptSig"`
+			Witness   []string     `json:"txinwitness"`
+			PrevOut   *TxInPrevOut `json:"prevout,omitempty"`
+			Sequence  uint32       `json:"sequence"`
 		}{
 			Txid:      v.Txid,
 			Vout:      v.Vout,
 			ScriptSig: v.ScriptSig,
 			Witness:   v.Witness,
+			PrevOut:   v.PrevOut,
 			Sequence:  v.Sequence,
 		}
 		return json.Marshal(txStruct)
 	}
 
 	txStruct := struct {
-		Txid      string     `json:"txid"`
-		Vout      uint32     `json:"vout"`
-		ScriptSig *ScriptSig `json:"scriptSig"`
-		Sequence  uint32     `json:"sequence"`
+		Txid      string       `json:"txid"`
+		Vout      uint32       `json:"vout"`
+		ScriptSig *ScriptSig   `json:"scriptSig"`
+		PrevOut   *TxInPrevOut `json:"prevout,omitempty"`
+		Sequence  uint32       `json:"sequence"`
 	}{
 		Txid:      v.Txid,
 		Vout:      v.Vout,
 		ScriptSig: v.ScriptSig,
+		PrevOut:   v.PrevOut,
 		Sequence:  v.Sequence,
 	}
 	return json.Marshal(txStruct)
@@ -522,6 +1114,10 @@ type TxRawResult struct {
 	Confirmations uint64 `json:"confirmations,omitempty"`
 	Time          int64  `json:"time,omitempty"`
 	Blocktime     int64  `json:"blocktime,omitempty"`
+
+	// Fee is only reported by getrawtransaction with a verbosity of 2,
+	// and only when the spent outputs are known to the server.
+	Fee float64 `json:"fee,omitempty"`
 }
 
 // SearchRawTransactionsResult models the data from the searchrawtransaction
@@ -7,6 +7,7 @@ package sebtcjson
 
 import (
 	"encoding/json"
+	"reflect"
 	"testing"
 )
 
@@ -42,6 +43,28 @@ func TestChainSvrCustomResults(t *testing.T) {
 			},
 			expected: `{"txid":"123","vout":1,"scriptSig":{"asm":"0","hex":"00"},"sequence":4294967295}`,
 		},
+		{
+			name: "custom vin marshal with prevout",
+			result: &Vin{
+				Txid: "123",
+				Vout: 1,
+				ScriptSig: &ScriptSig{
+					Asm: "0",
+					Hex: "00",
+				},
+				PrevOut: &TxInPrevOut{
+					Height: 100,
+					Value:  0.5,
+					ScriptPubKey: ScriptPubKeyResult{
+						Asm:  "0",
+						Hex:  "00",
+						Type: "witness_v0_keyhash",
+					},
+				},
+				Sequence: 4294967295,
+			},
+			expected: `{"txid":"123","vout":1,"scriptSig":{"asm":"0","hex":"00"},"prevout":{"generated":false,"height":100,"value":0.5,"scriptPubKey":{"asm":"0","hex":"00","type":"witness_v0_keyhash"}},"sequence":4294967295}`,
+		},
 		{
 			name: "custom vinprevout marshal with coinbase",
 			result: &VinPrevOut{
@@ -85,3 +108,418 @@ func TestChainSvrCustomResults(t *testing.T) {
 		}
 	}
 }
+
+// TestTxRawResultPrevOut ensures the optional prevout object of verbose
+// transaction inputs is parsed when present and left nil when absent.
+func TestTxRawResultPrevOut(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		result  string
+		prevOut *TxInPrevOut
+	}{
+		{
+			name:    "without prevout",
+			result:  `{"txid":"abc","vin":[{"txid":"123","vout":1,"scriptSig":{"asm":"0","hex":"00"},"sequence":4294967295}]}`,
+			prevOut: nil,
+		},
+		{
+			name:   "with prevout",
+			result: `{"txid":"abc","vin":[{"txid":"123","vout":1,"scriptSig":{"asm":"0","hex":"00"},"prevout":{"generated":true,"height":100,"value":0.5,"scriptPubKey":{"asm":"0","hex":"00","type":"witness_v0_keyhash"}},"sequence":4294967295}]}`,
+			prevOut: &TxInPrevOut{
+				Generated: true,
+				Height:    100,
+				Value:     0.5,
+				ScriptPubKey: ScriptPubKeyResult{
+					Asm:  "0",
+					Hex:  "00",
+					Type: "witness_v0_keyhash",
+				},
+			},
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		var result TxRawResult
+		if err := json.Unmarshal([]byte(test.result), &result); err != nil {
+			t.Errorf("Test #%d (%s) unexpected error: %v", i,
+				test.name, err)
+			continue
+		}
+		if len(result.Vin) != 1 {
+			t.Errorf("Test #%d (%s) unexpected number of inputs - "+
+				"got %d, want 1", i, test.name, len(result.Vin))
+			continue
+		}
+		if !reflect.DeepEqual(result.Vin[0].PrevOut, test.prevOut) {
+			t.Errorf("Test #%d (%s) unexpected prevout - got %+v, "+
+				"want %+v", i, test.name, result.Vin[0].PrevOut,
+				test.prevOut)
+		}
+	}
+}
+
+// TestServiceBits ensures the services field of the network results is parsed
+// from both the hex-encoded string and integer representations.
+func TestServiceBits(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		result  string
+		flags   ServiceFlag
+		has     []ServiceFlag
+		hasNot  []ServiceFlag
+		invalid bool
+	}{
+		{
+			name:   "hex string",
+			result: `{"services":"000000000000040d"}`,
+			flags:  SFNodeNetwork | SFNodeBloom | SFNodeWitness | SFNodeNetworkLimited,
+			has:    []ServiceFlag{SFNodeNetwork, SFNodeWitness, SFNodeNetworkLimited},
+			hasNot: []ServiceFlag{SFNodeGetUTXO, SFNodeCompactFilters},
+		},
+		{
+			name:   "integer",
+			result: `{"services":1097}`,
+			flags:  SFNodeNetwork | SFNodeWitness | SFNodeCompactFilters | SFNodeNetworkLimited,
+			has:    []ServiceFlag{SFNodeCompactFilters, SFNodeNetworkLimited},
+			hasNot: []ServiceFlag{SFNodeBloom},
+		},
+		{
+			name:   "empty",
+			result: `{"services":""}`,
+			flags:  0,
+			hasNot: []ServiceFlag{SFNodeNetwork},
+		},
+		{
+			name:    "invalid",
+			result:  `{"services":"xyz"}`,
+			hasNot:  []ServiceFlag{SFNodeNetwork},
+			invalid: true,
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		var peer GetPeerInfoResult
+		if err := json.Unmarshal([]byte(test.result), &peer); err != nil {
+			t.Errorf("Test #%d (%s) unexpected error: %v", i,
+				test.name, err)
+			continue
+		}
+		flags, err := ServiceBits(peer.Services).Flags()
+		if test.invalid {
+			if err == nil {
+				t.Errorf("Test #%d (%s) expected error", i,
+					test.name)
+			}
+		} else if err != nil || flags != test.flags {
+			t.Errorf("Test #%d (%s) unexpected flags - got %x (%v), "+
+				"want %x", i, test.name, uint64(flags), err,
+				uint64(test.flags))
+		}
+		for _, flag := range test.has {
+			if !peer.HasService(flag) {
+				t.Errorf("Test #%d (%s) expected service %x", i,
+					test.name, uint64(flag))
+			}
+		}
+		for _, flag := range test.hasNot {
+			if peer.HasService(flag) {
+				t.Errorf("Test #%d (%s) unexpected service %x", i,
+					test.name, uint64(flag))
+			}
+		}
+	}
+}
+
+// TestGetNetworkInfoResultCompat ensures the services and warnings of the
+// getnetworkinfo result keep their string fields regardless of the
+// representation used by the server.
+func TestGetNetworkInfoResultCompat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		result        string
+		localServices string
+		warnings      string
+		warningList   []string
+	}{
+		{
+			name:          "string forms",
+			result:        `{"localservices":"0000000000000409","warnings":"clock out of sync"}`,
+			localServices: "0000000000000409",
+			warnings:      "clock out of sync",
+			warningList:   []string{"clock out of sync"},
+		},
+		{
+			name:          "integer services and array warnings",
+			result:        `{"localservices":1033,"warnings":["a","b"]}`,
+			localServices: "0000000000000409",
+			warnings:      "a; b",
+			warningList:   []string{"a", "b"},
+		},
+		{
+			name:   "no warnings",
+			result: `{"localservices":"","warnings":""}`,
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		var info GetNetworkInfoResult
+		if err := json.Unmarshal([]byte(test.result), &info); err != nil {
+			t.Errorf("Test #%d (%s) unexpected error: %v", i,
+				test.name, err)
+			continue
+		}
+		if info.LocalServices != test.localServices {
+			t.Errorf("Test #%d (%s) unexpected local services - got "+
+				"%q, want %q", i, test.name, info.LocalServices,
+				test.localServices)
+		}
+		if info.Warnings != test.warnings {
+			t.Errorf("Test #%d (%s) unexpected warnings - got %q, "+
+				"want %q", i, test.name, info.Warnings,
+				test.warnings)
+		}
+		if list := info.WarningList(); !reflect.DeepEqual(list, test.warningList) {
+			t.Errorf("Test #%d (%s) unexpected warning list - got %q, "+
+				"want %q", i, test.name, list, test.warningList)
+		}
+	}
+}
+
+// TestGetNetworkInfoResult ensures a getnetworkinfo result as returned by
+// Bitcoin Core 0.17, including the nested networks and local addresses, is
+// unmarshalled as expected.
+func TestGetNetworkInfoResult(t *testing.T) {
+	t.Parallel()
+
+	marshalled := `{
+		"version": 170000,
+		"subversion": "/Satoshi:0.17.0/",
+		"protocolversion": 70015,
+		"localservices": "000000000000040d",
+		"localrelay": true,
+		"timeoffset": -1,
+		"networkactive": true,
+		"connections": 8,
+		"networks": [
+			{"name": "ipv4", "limited": false, "reachable": true, "proxy": "", "proxy_randomize_credentials": false},
+			{"name": "ipv6", "limited": false, "reachable": true, "proxy": "", "proxy_randomize_credentials": false},
+			{"name": "onion", "limited": true, "reachable": false, "proxy": "127.0.0.1:9050", "proxy_randomize_credentials": true}
+		],
+		"relayfee": 0.00001000,
+		"incrementalfee": 0.00001000,
+		"localaddresses": [
+			{"address": "203.0.113.7", "port": 8333, "score": 4}
+		],
+		"warnings": ""
+	}`
+
+	var result GetNetworkInfoResult
+	if err := json.Unmarshal([]byte(marshalled), &result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := GetNetworkInfoResult{
+		Version:         170000,
+		SubVersion:      "/Satoshi:0.17.0/",
+		ProtocolVersion: 70015,
+		LocalServices:   "000000000000040d",
+		LocalRelay:      true,
+		TimeOffset:      -1,
+		NetworkActive:   true,
+		Connections:     8,
+		Networks: []NetworksResult{
+			{Name: "ipv4", Reachable: true},
+			{Name: "ipv6", Reachable: true},
+			{
+				Name:                      "onion",
+				Limited:                   true,
+				Proxy:                     "127.0.0.1:9050",
+				ProxyRandomizeCredentials: true,
+			},
+		},
+		RelayFee:       0.00001,
+		IncrementalFee: 0.00001,
+		LocalAddresses: []LocalAddressesResult{
+			{Address: "203.0.113.7", Port: 8333, Score: 4},
+		},
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("unexpected result - got %+v, want %+v", result, want)
+	}
+	if !result.HasService(SFNodeWitness) {
+		t.Errorf("witness service not reported")
+	}
+}
+
+// TestGetBlockChainInfoResult ensures a getblockchaininfo result as returned by
+// a full node, including the nested BIP0009 soft-fork descriptions, is
+// unmarshalled as expected.
+func TestGetBlockChainInfoResult(t *testing.T) {
+	t.Parallel()
+
+	marshalled := `{
+		"chain": "test",
+		"blocks": 1325000,
+		"headers": 1325010,
+		"bestblockhash": "000000000000003a1b2b8e0c3a0f1d5b7c7b6c2c345f1b0f2e7a2c1d3e4f5a6b",
+		"difficulty": 1913056.45,
+		"mediantime": 1528000000,
+		"verificationprogress": 0.99999,
+		"chainwork": "0000000000000000000000000000000000000000000000a3f8c2e1d7b6a5c4d3",
+		"pruned": false,
+		"softforks": [
+			{"id": "bip34", "version": 2, "reject": {"status": true}},
+			{"id": "bip66", "version": 3, "reject": {"status": true}}
+		],
+		"bip9_softforks": {
+			"csv": {
+				"status": "active",
+				"startTime": 1456790400,
+				"timeout": 1493596800,
+				"since": 770112
+			},
+			"segwit": {
+				"status": "started",
+				"bit": 1,
+				"startTime": 1462060800,
+				"timeout": 1493596800,
+				"since": 834624,
+				"statistics": {
+					"period": 2016,
+					"threshold": 1512,
+					"elapsed": 1000,
+					"count": 900,
+					"possible": true
+				}
+			}
+		},
+		"warnings": ""
+	}`
+
+	var result GetBlockChainInfoResult
+	if err := json.Unmarshal([]byte(marshalled), &result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := GetBlockChainInfoResult{
+		Chain:                "test",
+		Blocks:               1325000,
+		Headers:              1325010,
+		BestBlockHash:        "000000000000003a1b2b8e0c3a0f1d5b7c7b6c2c345f1b0f2e7a2c1d3e4f5a6b",
+		Difficulty:           1913056.45,
+		MedianTime:           1528000000,
+		VerificationProgress: 0.99999,
+		ChainWork:            "0000000000000000000000000000000000000000000000a3f8c2e1d7b6a5c4d3",
+		SoftForks: []*SoftForkDescription{
+			{ID: "bip34", Version: 2},
+			{ID: "bip66", Version: 3},
+		},
+		Bip9SoftForks: map[string]*Bip9SoftForkDescription{
+			"csv": {
+				Status:    "active",
+				StartTime: 1456790400,
+				Timeout:   1493596800,
+				Since:     770112,
+			},
+			"segwit": {
+				Status:    "started",
+				Bit:       1,
+				StartTime: 1462060800,
+				Timeout:   1493596800,
+				Since:     834624,
+				Statistics: &Bip9SoftForkStatistics{
+					Period:    2016,
+					Threshold: 1512,
+					Elapsed:   1000,
+					Count:     900,
+					Possible:  true,
+				},
+			},
+		},
+	}
+	want.SoftForks[0].Reject.Status = true
+	want.SoftForks[1].Reject.Status = true
+
+	if !reflect.DeepEqual(result, want) {
+		gotJSON, _ := json.Marshal(result)
+		wantJSON, _ := json.Marshal(want)
+		t.Errorf("unexpected result - got %s, want %s", gotJSON, wantJSON)
+	}
+}
+
+// TestGetMempoolEntryResult ensures getmempoolentry results reporting the fees
+// as flat fields, nested under fees, or both, all unmarshal to the same flat
+// fee fields.
+func TestGetMempoolEntryResult(t *testing.T) {
+	t.Parallel()
+
+	fees := &GetMempoolEntryFeesResult{
+		Base:       0.0000452,
+		Modified:   0.0001452,
+		Ancestor:   0.0001356,
+		Descendant: 0.0000904,
+	}
+	tests := []struct {
+		name   string
+		result string
+		fees   *GetMempoolEntryFeesResult
+	}{
+		{
+			name: "flat fees",
+			result: `{"size":225,"fee":0.0000452,"modifiedfee":0.0001452,` +
+				`"descendantcount":2,"descendantfees":9040,` +
+				`"ancestorcount":3,"ancestorfees":13560,"depends":[]}`,
+		},
+		{
+			name: "flat and nested fees",
+			result: `{"size":225,"fee":0.0000452,"modifiedfee":0.0001452,` +
+				`"descendantcount":2,"descendantfees":9040,` +
+				`"ancestorcount":3,"ancestorfees":13560,"depends":[],` +
+				`"fees":{"base":0.0000452,"modified":0.0001452,` +
+				`"ancestor":0.0001356,"descendant":0.0000904}}`,
+			fees: fees,
+		},
+		{
+			name: "nested fees",
+			result: `{"size":225,"descendantcount":2,"ancestorcount":3,` +
+				`"depends":[],"fees":{"base":0.0000452,` +
+				`"modified":0.0001452,"ancestor":0.0001356,` +
+				`"descendant":0.0000904}}`,
+			fees: fees,
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		var entry GetMempoolEntryResult
+		if err := json.Unmarshal([]byte(test.result), &entry); err != nil {
+			t.Errorf("Test #%d (%s) unexpected error: %v", i,
+				test.name, err)
+			continue
+		}
+		want := GetMempoolEntryResult{
+			Size:            225,
+			Fee:             0.0000452,
+			ModifiedFee:     0.0001452,
+			DescendantCount: 2,
+			DescendantFees:  9040,
+			AncestorCount:   3,
+			AncestorFees:    13560,
+			Depends:         []string{},
+			Fees:            test.fees,
+		}
+		if !reflect.DeepEqual(entry, want) {
+			t.Errorf("Test #%d (%s) unexpected entry - got %+v, "+
+				"want %+v", i, test.name, entry, want)
+		}
+	}
+}
@@ -8,28 +8,39 @@ package sebtcjson
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 )
 
+// timeType is the reflect type of time.Time, which assignField converts UNIX
+// timestamps to.
+var timeType = reflect.TypeOf(time.Time{})
+
 // makeParams creates a slice of interface values for the given struct.
+//
+// Trailing optional parameters which are nil are omitted.  A nil optional
+// parameter which is followed by one that is set is sent as null so the later
+// parameter keeps its position and the server uses the default for the nil
+// one.
 func makeParams(rt reflect.Type, rv reflect.Value) []interface{} {
 	numFields := rt.NumField()
 	params := make([]interface{}, 0, numFields)
+	numParams := 0
 	for i := 0; i < numFields; i++ {
 		rtf := rt.Field(i)
 		rvf := rv.Field(i)
-		if rtf.Type.Kind() == reflect.Ptr {
-			if rvf.IsNil() {
-				break
-			}
-			rvf.Elem()
+		if rtf.Type.Kind() == reflect.Ptr && rvf.IsNil() {
+			params = append(params, nil)
+			continue
 		}
 		params = append(params, rvf.Interface())
+		numParams = len(params)
 	}
 
-	return params
+	return params[:numParams]
 }
 
 // MarshalCmd marshals the passed command to a JSON-RPC request byte slice that
@@ -37,6 +48,49 @@ func makeParams(rt reflect.Type, rv reflect.Value) []interface{} {
 // must be a registered type.  All commands provided by this package are
 // registered by default.
 func MarshalCmd(id interface{}, cmd interface{}) ([]byte, error) {
+	rawCmd, err := newCmdRequest(id, cmd)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(rawCmd)
+}
+
+// MarshalCmds marshals the passed commands to a JSON-RPC 2.0 batch request byte
+// slice that is suitable for transmission to an RPC server, using the id at the
+// same position for each command.  The commands must be of registered types,
+// or be *Request values which are included as they are except for the version.
+// Since batches are a JSON-RPC 2.0 feature, every request of the batch is
+// marked as a JSON-RPC 2.0 request.
+func MarshalCmds(ids []interface{}, cmds []interface{}) ([]byte, error) {
+	if len(ids) != len(cmds) {
+		str := fmt.Sprintf("got %d ids for %d commands", len(ids),
+			len(cmds))
+		return nil, makeError(ErrInvalidType, str)
+	}
+
+	batch := make([]*Request, 0, len(cmds))
+	for i, cmd := range cmds {
+		var rawCmd *Request
+		if req, ok := cmd.(*Request); ok && req != nil {
+			reqCopy := *req
+			reqCopy.ID = ids[i]
+			rawCmd = &reqCopy
+		} else {
+			var err error
+			rawCmd, err = newCmdRequest(ids[i], cmd)
+			if err != nil {
+				return nil, err
+			}
+		}
+		rawCmd.Jsonrpc = "2.0"
+		batch = append(batch, rawCmd)
+	}
+	return json.Marshal(batch)
+}
+
+// newCmdRequest returns the JSON-RPC request for the passed command, which must
+// be of a registered type.
+func newCmdRequest(id interface{}, cmd interface{}) (*Request, error) {
 	// Look up the cmd type and error out if not registered.
 	rt := reflect.TypeOf(cmd)
 	registerLock.RLock()
@@ -59,12 +113,8 @@ func MarshalCmd(id interface{}, cmd interface{}) ([]byte, error) {
 	// them if they are non-nil.
 	params := makeParams(rt.Elem(), rv.Elem())
 
-	// Generate and marshal the final JSON-RPC request.
-	rawCmd, err := NewRequest(id, method, params)
-	if err != nil {
-		return nil, err
-	}
-	return json.Marshal(rawCmd)
+	// Generate the final JSON-RPC request.
+	return NewRequest(id, method, params)
 }
 
 // checkNumParams ensures the supplied number of params is at least the minimum
@@ -191,6 +241,11 @@ func typesMaybeCompatible(dest reflect.Type, src reflect.Type) bool {
 		return true
 	}
 
+	// Numbers can potentially be converted to times as UNIX timestamps.
+	if dest == timeType && isNumeric(srcKind) {
+		return true
+	}
+
 	if srcKind == reflect.String {
 		// Strings can potentially be converted to numeric types.
 		if isNumeric(destKind) {
@@ -229,11 +284,59 @@ func baseType(arg reflect.Type) (reflect.Type, int) {
 	return arg, numIndirects
 }
 
+// assignTime assigns the UNIX timestamp, in seconds, held by the provided
+// source value to the destination time.  The source may be an integer, a float
+// without fractional part, or a string holding a decimal integer.
+func assignTime(paramNum int, fieldName string, dest reflect.Value, src reflect.Value) error {
+	var secs int64
+	switch src.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+
+		secs = src.Int()
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64:
+
+		srcUint := src.Uint()
+		if srcUint > math.MaxInt64 {
+			str := fmt.Sprintf("parameter #%d '%s' overflows "+
+				"destination type %v", paramNum, fieldName,
+				timeType)
+			return makeError(ErrInvalidType, str)
+		}
+		secs = int64(srcUint)
+
+	case reflect.Float32, reflect.Float64:
+		srcFloat := src.Float()
+		if srcFloat != math.Trunc(srcFloat) ||
+			srcFloat < math.MinInt64 || srcFloat >= math.MaxInt64 {
+
+			str := fmt.Sprintf("parameter #%d '%s' must be a "+
+				"whole number of seconds", paramNum, fieldName)
+			return makeError(ErrInvalidType, str)
+		}
+		secs = int64(srcFloat)
+
+	case reflect.String:
+		var err error
+		secs, err = strconv.ParseInt(src.String(), 10, 64)
+		if err != nil {
+			str := fmt.Sprintf("parameter #%d '%s' must parse to "+
+				"a %v", paramNum, fieldName, timeType)
+			return makeError(ErrInvalidType, str)
+		}
+	}
+
+	dest.Set(reflect.ValueOf(time.Unix(secs, 0)))
+	return nil
+}
+
 // assignField is the main workhorse for the NewCmd function which handles
 // assigning the provided source value to the destination field.  It supports
-// direct type assignments, indirection, conversion of numeric types, and
-// unmarshaling of strings into arrays, slices, structs, and maps via
-// json.Unmarshal.
+// direct type assignments, indirection, conversion of numeric types,
+// conversion of UNIX timestamps into times, and unmarshaling of strings into
+// arrays, slices, structs, and maps via json.Unmarshal.
 func assignField(paramNum int, fieldName string, dest reflect.Value, src reflect.Value) error {
 	// Just error now when the types have no chance of being compatible.
 	destBaseType, destIndirects := baseType(dest.Type())
@@ -288,6 +391,12 @@ func assignField(paramNum int, fieldName string, dest reflect.Value, src reflect
 		src = src.Elem()
 	}
 
+	// Times are assigned from UNIX timestamps rather than converted
+	// according to their kind.
+	if destBaseType == timeType {
+		return assignTime(paramNum, fieldName, dest, src)
+	}
+
 	// Perform supported type conversions.
 	switch src.Kind() {
 	// Source value is a signed integer of various magnitude.
@@ -509,6 +618,9 @@ func assignField(paramNum int, fieldName string, dest reflect.Value, src reflect
 //   - Conversion from string to arrays, slices, structs, and maps by treating
 //     the string as marshalled JSON and calling json.Unmarshal into the
 //     destination field
+//   - Conversion from any size integer or float without fractional part, and
+//     from string holding a decimal integer, to time.Time by treating the value
+//     as a UNIX timestamp in seconds
 func NewCmd(method string, args ...interface{}) (interface{}, error) {
 	// Look up details about the provided method.  Any methods that aren't
 	// registered are an error.
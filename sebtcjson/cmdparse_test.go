@@ -10,6 +10,7 @@ import (
 	"math"
 	"reflect"
 	"testing"
+	"time"
 )
 
 // TestAssignField tests the assignField function handles supported combinations
@@ -161,6 +162,39 @@ func TestAssignField(t *testing.T) {
 			src:      `{"1Address":1.5}`,
 			expected: map[string]float64{"1Address": 1.5},
 		},
+		{
+			name:     "convertible types - int64 -> time",
+			dest:     time.Time{},
+			src:      int64(1538000000),
+			expected: time.Unix(1538000000, 0),
+		},
+		{
+			name:     "convertible types - uint32 -> time",
+			dest:     time.Time{},
+			src:      uint32(1538000000),
+			expected: time.Unix(1538000000, 0),
+		},
+		{
+			name:     "convertible types - float64 -> time",
+			dest:     time.Time{},
+			src:      float64(1538000000),
+			expected: time.Unix(1538000000, 0),
+		},
+		{
+			name:     "convertible types - string -> time",
+			dest:     time.Time{},
+			src:      "1538000000",
+			expected: time.Unix(1538000000, 0),
+		},
+		{
+			name: "convertible types - int64 -> time pointer",
+			dest: func() interface{} {
+				t := time.Time{}
+				return &t
+			}(),
+			src:      int64(-1),
+			expected: time.Unix(-1, 0),
+		},
 	}
 
 	t.Logf("Running %d tests", len(tests))
@@ -324,6 +358,30 @@ func TestAssignFieldErrors(t *testing.T) {
 			src:  "foo",
 			err:  Error{ErrorCode: ErrInvalidType},
 		},
+		{
+			name: "invalid string -> time",
+			dest: time.Time{},
+			src:  "foo",
+			err:  Error{ErrorCode: ErrInvalidType},
+		},
+		{
+			name: "fractional float -> time",
+			dest: time.Time{},
+			src:  float64(1538000000.5),
+			err:  Error{ErrorCode: ErrInvalidType},
+		},
+		{
+			name: "overflow uint64 -> time",
+			dest: time.Time{},
+			src:  uint64(1 << 63),
+			err:  Error{ErrorCode: ErrInvalidType},
+		},
+		{
+			name: "general incompatible bool -> time",
+			dest: time.Time{},
+			src:  true,
+			err:  Error{ErrorCode: ErrInvalidType},
+		},
 	}
 
 	t.Logf("Running %d tests", len(tests))
@@ -448,6 +506,66 @@ func TestMarshalCmdErrors(t *testing.T) {
 	}
 }
 
+// TestMarshalCmds tests the MarshalCmds function marshals registered commands
+// and raw requests into a JSON-RPC 2.0 batch and rejects invalid input.
+func TestMarshalCmds(t *testing.T) {
+	t.Parallel()
+
+	raw := &Request{
+		Jsonrpc: "1.0",
+		Method:  "getrawcustom",
+		Params:  []json.RawMessage{json.RawMessage(`"abc"`)},
+		ID:      99,
+	}
+	marshalled, err := MarshalCmds(
+		[]interface{}{1, 2, "three"},
+		[]interface{}{NewGetBlockCountCmd(), NewGetBlockHashCmd(10), raw},
+	)
+	if err != nil {
+		t.Fatalf("MarshalCmds: unexpected error: %v", err)
+	}
+	want := `[{"jsonrpc":"2.0","method":"getblockcount","params":[],"id":1},` +
+		`{"jsonrpc":"2.0","method":"getblockhash","params":[10],"id":2},` +
+		`{"jsonrpc":"2.0","method":"getrawcustom","params":["abc"],"id":"three"}]`
+	if string(marshalled) != want {
+		t.Errorf("MarshalCmds: unexpected result - got %s, want %s",
+			marshalled, want)
+	}
+	if raw.Jsonrpc != "1.0" || raw.ID != 99 {
+		t.Errorf("MarshalCmds: raw request was modified: %+v", raw)
+	}
+
+	tests := []struct {
+		name string
+		ids  []interface{}
+		cmds []interface{}
+		err  Error
+	}{
+		{
+			name: "mismatched ids",
+			ids:  []interface{}{1},
+			cmds: []interface{}{NewGetBlockCountCmd(), NewGetBlockCountCmd()},
+			err:  Error{ErrorCode: ErrInvalidType},
+		},
+		{
+			name: "unregistered type",
+			ids:  []interface{}{1},
+			cmds: []interface{}{(*int)(nil)},
+			err:  Error{ErrorCode: ErrUnregisteredMethod},
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		_, err := MarshalCmds(test.ids, test.cmds)
+		jerr, ok := err.(Error)
+		if !ok || jerr.ErrorCode != test.err.ErrorCode {
+			t.Errorf("Test #%d (%s) wrong error - got %v, want %v",
+				i, test.name, err, test.err.ErrorCode)
+		}
+	}
+}
+
 // TestUnmarshalCmdErrors  tests the error paths of the UnmarshalCmd function.
 func TestUnmarshalCmdErrors(t *testing.T) {
 	t.Parallel()
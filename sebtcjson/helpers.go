@@ -5,6 +5,11 @@
 
 package sebtcjson
 
+import (
+	"fmt"
+	"math"
+)
+
 // Bool is a helper routine that allocates a new bool value to store v and
 // returns a pointer to it.  This is useful when assigning optional parameters.
 func Bool(v bool) *bool {
@@ -76,3 +81,43 @@ func String(v string) *string {
 	*p = v
 	return p
 }
+
+// FormatAmount returns the canonical decimal representation of the passed
+// amount of satoshi in BTC, which always has exactly eight decimal places.
+func FormatAmount(satoshi int64) string {
+	sign := ""
+	abs := uint64(satoshi)
+	if satoshi < 0 {
+		sign = "-"
+		abs = -abs
+	}
+	return fmt.Sprintf("%s%d.%08d", sign, abs/1e8, abs%1e8)
+}
+
+// Amount is an amount in BTC of a command parameter.  It is marshalled in the
+// canonical format of FormatAmount, such as 0.00000001, rather than as the
+// shortest representation of the float64, such as 1e-8.
+type Amount float64
+
+// MarshalJSON provides a custom Marshal method for Amount.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	satoshi := math.Round(float64(a) * 1e8)
+	if math.IsNaN(satoshi) || math.Abs(satoshi) >= math.MaxInt64 {
+		str := fmt.Sprintf("amount %v is out of range", float64(a))
+		return nil, makeError(ErrInvalidType, str)
+	}
+	return []byte(FormatAmount(int64(satoshi))), nil
+}
+
+// amountMap returns the passed amounts in BTC keyed by address as amounts
+// which are marshalled in the canonical format.
+func amountMap(amounts map[string]float64) map[string]Amount {
+	if amounts == nil {
+		return nil
+	}
+	converted := make(map[string]Amount, len(amounts))
+	for addr, amount := range amounts {
+		converted[addr] = Amount(amount)
+	}
+	return converted
+}
@@ -6,6 +6,8 @@
 package sebtcjson
 
 import (
+	"encoding/json"
+	"math"
 	"reflect"
 	"testing"
 )
@@ -112,3 +114,99 @@ func TestHelpers(t *testing.T) {
 		}
 	}
 }
+
+// TestFormatAmount ensures amounts are formatted with exactly eight decimal
+// places and that amount parameters are marshalled in that format.
+func TestFormatAmount(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		satoshi   int64
+		btc       float64
+		formatted string
+	}{
+		{
+			name:      "zero",
+			satoshi:   0,
+			btc:       0,
+			formatted: "0.00000000",
+		},
+		{
+			name:      "one satoshi",
+			satoshi:   1,
+			btc:       0.00000001,
+			formatted: "0.00000001",
+		},
+		{
+			name:      "0.1 BTC",
+			satoshi:   10000000,
+			btc:       0.1,
+			formatted: "0.10000000",
+		},
+		{
+			name:      "0.3 BTC",
+			satoshi:   30000000,
+			btc:       0.1 + 0.2,
+			formatted: "0.30000000",
+		},
+		{
+			name:      "max money less one satoshi",
+			satoshi:   2099999999999999,
+			btc:       20999999.99999999,
+			formatted: "20999999.99999999",
+		},
+		{
+			name:      "negative",
+			satoshi:   -12345678,
+			btc:       -0.12345678,
+			formatted: "-0.12345678",
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		formatted := FormatAmount(test.satoshi)
+		if formatted != test.formatted {
+			t.Errorf("Test #%d (%s) unexpected format - got %s, "+
+				"want %s", i, test.name, formatted, test.formatted)
+			continue
+		}
+
+		cmd := NewSendToAddressCmd("1Address", test.btc, nil, nil)
+		marshalled, err := MarshalCmd(1, cmd)
+		if err != nil {
+			t.Errorf("Test #%d (%s) unexpected error: %v", i,
+				test.name, err)
+			continue
+		}
+		want := `{"jsonrpc":"1.0","method":"sendtoaddress","params":` +
+			`["1Address",` + test.formatted + `],"id":1}`
+		if string(marshalled) != want {
+			t.Errorf("Test #%d (%s) unexpected marshalled command - "+
+				"got %s, want %s", i, test.name, marshalled, want)
+		}
+
+		cmd2 := NewSendManyCmd("", map[string]float64{"1Address": test.btc},
+			nil, nil)
+		marshalled, err = MarshalCmd(1, cmd2)
+		if err != nil {
+			t.Errorf("Test #%d (%s) unexpected error: %v", i,
+				test.name, err)
+			continue
+		}
+		want = `{"jsonrpc":"1.0","method":"sendmany","params":` +
+			`["",{"1Address":` + test.formatted + `}],"id":1}`
+		if string(marshalled) != want {
+			t.Errorf("Test #%d (%s) unexpected marshalled command - "+
+				"got %s, want %s", i, test.name, marshalled, want)
+		}
+	}
+
+	// Amounts which do not fit into satoshi can not be marshalled.
+	for _, btc := range []float64{math.NaN(), math.Inf(1), 1e12} {
+		if _, err := json.Marshal(Amount(btc)); err == nil {
+			t.Errorf("Marshal %v: expected error", btc)
+		}
+	}
+}
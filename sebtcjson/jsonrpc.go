@@ -17,10 +17,34 @@ import (
 type RPCErrorCode int
 
 // RPCError represents an error that is used as a part of a JSON-RPC Response
-// object.
+// object.  Data holds the structured details a JSON-RPC 2.0 server may attach
+// to the error, such as the reason a transaction was rejected, and is left
+// undecoded so callers can unmarshal it into a type of their choice.  It is
+// nil when the server sent none.
 type RPCError struct {
-	Code    RPCErrorCode `json:"code,omitempty"`
-	Message string       `json:"message,omitempty"`
+	Code    RPCErrorCode    `json:"code,omitempty"`
+	Message string          `json:"message,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// UnmarshalJSON provides a custom Unmarshal method for RPCError.  Besides
+// error objects, it accepts the plain error strings some JSON-RPC 1.0 servers
+// respond with, which become the message of an error without a code.
+func (e *RPCError) UnmarshalJSON(data []byte) error {
+	var message string
+	if err := json.Unmarshal(data, &message); err == nil {
+		*e = RPCError{Message: message}
+		return nil
+	}
+
+	// The alias has no methods, which keeps this method from recursing.
+	type rpcError RPCError
+	var obj rpcError
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	*e = RPCError(obj)
+	return nil
 }
 
 // Guarantee RPCError satisifies the builtin error interface.
@@ -136,6 +160,27 @@ func NewResponse(id interface{}, marshalledResult []byte, rpcErr *RPCError) (*Re
 	}, nil
 }
 
+// UnmarshalResponses unmarshals the passed JSON-RPC 2.0 batch response into the
+// responses it holds.  The responses are returned in the order the server sent
+// them, which need not be the order of the requests, so they should be matched
+// to the requests by id.
+//
+// Servers answer a batch they reject as a whole with a single error response
+// instead of an array.  Its error is returned as an *RPCError.
+func UnmarshalResponses(data []byte) ([]Response, error) {
+	var responses []Response
+	err := json.Unmarshal(data, &responses)
+	if err == nil {
+		return responses, nil
+	}
+
+	var resp Response
+	if json.Unmarshal(data, &resp) == nil && resp.Error != nil {
+		return nil, resp.Error
+	}
+	return nil, err
+}
+
 // MarshalResponse marshals the passed id, result, and RPCError to a JSON-RPC
 // response byte slice that is suitable for transmission to a JSON-RPC client.
 func MarshalResponse(id interface{}, result interface{}, rpcErr *RPCError) ([]byte, error) {
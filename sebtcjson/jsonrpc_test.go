@@ -158,3 +158,93 @@ func TestRPCError(t *testing.T) {
 		}
 	}
 }
+
+// TestRPCErrorUnmarshal ensures error objects keep their data field and that
+// plain error strings are accepted.
+func TestRPCErrorUnmarshal(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		in   string
+		want RPCError
+	}{
+		{
+			name: "object",
+			in:   `{"code":-32601,"message":"Method not found"}`,
+			want: RPCError{Code: -32601, Message: "Method not found"},
+		},
+		{
+			name: "object with data",
+			in:   `{"code":-26,"message":"rejected","data":{"reason":"min relay fee not met","code":66}}`,
+			want: RPCError{
+				Code:    -26,
+				Message: "rejected",
+				Data:    json.RawMessage(`{"reason":"min relay fee not met","code":66}`),
+			},
+		},
+		{
+			name: "string",
+			in:   `"insufficient funds"`,
+			want: RPCError{Message: "insufficient funds"},
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		var rpcErr RPCError
+		if err := json.Unmarshal([]byte(test.in), &rpcErr); err != nil {
+			t.Errorf("Test #%d (%s) unexpected error: %v", i,
+				test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(rpcErr, test.want) {
+			t.Errorf("Test #%d (%s) unexpected error - got %+v, "+
+				"want %+v", i, test.name, rpcErr, test.want)
+		}
+	}
+
+	var rpcErr RPCError
+	if err := json.Unmarshal([]byte(`42`), &rpcErr); err == nil {
+		t.Errorf("expected error for number")
+	}
+}
+
+// TestUnmarshalResponses ensures batch responses are returned in the order the
+// server sent them and that a batch rejected as a whole returns its error.
+func TestUnmarshalResponses(t *testing.T) {
+	t.Parallel()
+
+	responses, err := UnmarshalResponses([]byte(`[` +
+		`{"jsonrpc":"2.0","error":{"code":-8,"message":"Block height out of range"},"id":2},` +
+		`{"jsonrpc":"2.0","result":100,"id":1}]`))
+	if err != nil {
+		t.Fatalf("UnmarshalResponses: unexpected error: %v", err)
+	}
+	if len(responses) != 2 {
+		t.Fatalf("UnmarshalResponses: got %d responses, want 2",
+			len(responses))
+	}
+	if responses[0].ID == nil || *responses[0].ID != float64(2) ||
+		responses[0].Error == nil || responses[0].Error.Code != -8 ||
+		responses[0].Result != nil {
+
+		t.Errorf("unexpected first response %+v", responses[0])
+	}
+	if responses[1].ID == nil || *responses[1].ID != float64(1) ||
+		responses[1].Error != nil || string(responses[1].Result) != "100" {
+
+		t.Errorf("unexpected second response %+v", responses[1])
+	}
+
+	_, err = UnmarshalResponses([]byte(`{"result":null,` +
+		`"error":{"code":-32700,"message":"Parse error"},"id":null}`))
+	if rpcErr, ok := err.(*RPCError); !ok || rpcErr.Code != -32700 {
+		t.Errorf("UnmarshalResponses: unexpected error for rejected "+
+			"batch %v", err)
+	}
+
+	if _, err := UnmarshalResponses([]byte(`not json`)); err == nil {
+		t.Errorf("UnmarshalResponses: expected error for invalid JSON")
+	}
+}
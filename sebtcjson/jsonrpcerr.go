@@ -31,15 +31,18 @@ var (
 
 // General application defined JSON errors.
 const (
-	ErrRPCMisc                RPCErrorCode = -1
-	ErrRPCForbiddenBySafeMode RPCErrorCode = -2
-	ErrRPCType                RPCErrorCode = -3
-	ErrRPCInvalidAddressOrKey RPCErrorCode = -5
-	ErrRPCOutOfMemory         RPCErrorCode = -7
-	ErrRPCInvalidParameter    RPCErrorCode = -8
-	ErrRPCDatabase            RPCErrorCode = -20
-	ErrRPCDeserialization     RPCErrorCode = -22
-	ErrRPCVerify              RPCErrorCode = -25
+	ErrRPCMisc                 RPCErrorCode = -1
+	ErrRPCForbiddenBySafeMode  RPCErrorCode = -2
+	ErrRPCType                 RPCErrorCode = -3
+	ErrRPCInvalidAddressOrKey  RPCErrorCode = -5
+	ErrRPCOutOfMemory          RPCErrorCode = -7
+	ErrRPCInvalidParameter     RPCErrorCode = -8
+	ErrRPCDatabase             RPCErrorCode = -20
+	ErrRPCDeserialization      RPCErrorCode = -22
+	ErrRPCVerify               RPCErrorCode = -25
+	ErrRPCVerifyRejected       RPCErrorCode = -26
+	ErrRPCVerifyAlreadyInChain RPCErrorCode = -27
+	ErrRPCInWarmup             RPCErrorCode = -28
 )
 
 // Peer-to-peer client errors.
@@ -60,6 +63,7 @@ const (
 	ErrRPCWalletWrongEncState       RPCErrorCode = -15
 	ErrRPCWalletEncryptionFailed    RPCErrorCode = -16
 	ErrRPCWalletAlreadyUnlocked     RPCErrorCode = -17
+	ErrRPCWalletNotFound            RPCErrorCode = -18
 )
 
 // Specific Errors related to commands.  These are the ones a user of the RPC
@@ -34,6 +34,12 @@ const (
 	// This means when it is marshalled, the ID must be nil.
 	UFNotification
 
+	// UFNonIdempotent indicates that repeating the command repeats its
+	// effect, such as sending coins a second time.  Such commands must not
+	// be sent again when it is unknown whether the server received them,
+	// such as after a timeout.
+	UFNonIdempotent
+
 	// highestUsageFlagBit is the maximum usage flag bit and is used in the
 	// stringer and tests to ensure all of the above constants have been
 	// tested.
@@ -45,6 +51,7 @@ var usageFlagStrings = map[UsageFlag]string{
 	UFWalletOnly:    "UFWalletOnly",
 	UFWebsocketOnly: "UFWebsocketOnly",
 	UFNotification:  "UFNotification",
+	UFNonIdempotent: "UFNonIdempotent",
 }
 
 // String returns the UsageFlag in human-readable form.
@@ -134,6 +141,9 @@ func isAcceptableKind(kind reflect.Kind) bool {
 // using this function, however it is also exported so callers can easily
 // register custom types.
 //
+// ErrDuplicateMethod is returned when a command is already registered for the
+// method.  UnregisterCmd removes a registered command again.
+//
 // The type format is very strict since it needs to be able to automatically
 // marshal to and from JSON-RPC 1.0.  The following enumerates the requirements:
 //
@@ -267,6 +277,28 @@ func RegisterCmd(method string, cmd interface{}, flags UsageFlag) error {
 	return nil
 }
 
+// UnregisterCmd removes the command registered for the passed method, after
+// which the method may be registered again.  It is mainly intended to isolate
+// tests which register custom commands with RegisterCmd.  ErrUnregisteredMethod
+// is returned when no command is registered for the method.
+func UnregisterCmd(method string) error {
+	registerLock.Lock()
+	defer registerLock.Unlock()
+
+	rtp, ok := methodToConcreteType[method]
+	if !ok {
+		str := fmt.Sprintf("%q is not registered", method)
+		return makeError(ErrUnregisteredMethod, str)
+	}
+
+	delete(methodToConcreteType, method)
+	delete(methodToInfo, method)
+	if concreteTypeToMethod[rtp] == method {
+		delete(concreteTypeToMethod, rtp)
+	}
+	return nil
+}
+
 // MustRegisterCmd performs the same function as RegisterCmd except it panics
 // if there is an error.  This should only be called from package init
 // functions.
@@ -23,6 +23,7 @@ func TestUsageFlagStringer(t *testing.T) {
 		{UFWalletOnly, "UFWalletOnly"},
 		{UFWebsocketOnly, "UFWebsocketOnly"},
 		{UFNotification, "UFNotification"},
+		{UFNonIdempotent, "UFNonIdempotent"},
 		{UFWalletOnly | UFWebsocketOnly,
 			"UFWalletOnly|UFWebsocketOnly"},
 		{UFWalletOnly | UFWebsocketOnly | (1 << 31),
@@ -260,3 +261,53 @@ func TestRegisteredCmdMethods(t *testing.T) {
 		t.Fatal("RegisteredCmdMethods: methods are not sorted")
 	}
 }
+
+// TestUnregisterCmd ensures an unregistered command can no longer be created
+// or marshalled and that its method may be registered again.
+func TestUnregisterCmd(t *testing.T) {
+	t.Parallel()
+
+	type unregisterTestCmd struct {
+		Value string
+	}
+	method := "unregistertestcmd"
+	if err := RegisterCmd(method, (*unregisterTestCmd)(nil), 0); err != nil {
+		t.Fatalf("RegisterCmd: unexpected error: %v", err)
+	}
+	if _, err := NewCmd(method, "value"); err != nil {
+		t.Fatalf("NewCmd: unexpected error: %v", err)
+	}
+	err := RegisterCmd(method, (*unregisterTestCmd)(nil), 0)
+	if jerr, ok := err.(Error); !ok || jerr.ErrorCode != ErrDuplicateMethod {
+		t.Fatalf("RegisterCmd: unexpected error - got %v, want %v",
+			err, ErrDuplicateMethod)
+	}
+
+	if err := UnregisterCmd(method); err != nil {
+		t.Fatalf("UnregisterCmd: unexpected error: %v", err)
+	}
+	_, err = NewCmd(method, "value")
+	if jerr, ok := err.(Error); !ok || jerr.ErrorCode != ErrUnregisteredMethod {
+		t.Errorf("NewCmd: unexpected error - got %v, want %v", err,
+			ErrUnregisteredMethod)
+	}
+	_, err = MarshalCmd(1, &unregisterTestCmd{Value: "value"})
+	if jerr, ok := err.(Error); !ok || jerr.ErrorCode != ErrUnregisteredMethod {
+		t.Errorf("MarshalCmd: unexpected error - got %v, want %v", err,
+			ErrUnregisteredMethod)
+	}
+	err = UnregisterCmd(method)
+	if jerr, ok := err.(Error); !ok || jerr.ErrorCode != ErrUnregisteredMethod {
+		t.Errorf("UnregisterCmd: unexpected error - got %v, want %v",
+			err, ErrUnregisteredMethod)
+	}
+
+	// The method is free to be registered again.
+	if err := RegisterCmd(method, (*unregisterTestCmd)(nil), 0); err != nil {
+		t.Fatalf("RegisterCmd after UnregisterCmd: unexpected error: %v",
+			err)
+	}
+	if err := UnregisterCmd(method); err != nil {
+		t.Fatalf("UnregisterCmd: unexpected error: %v", err)
+	}
+}
@@ -5,6 +5,11 @@
 
 package sebtcjson
 
+import (
+	"encoding/hex"
+	"encoding/json"
+)
+
 const (
 	// UnsetEstimeMode identifies the UNSET estimation strategy used by estimatesmartfee
 	UnsetEstimeMode EstimateMode = "UNSET"
@@ -16,14 +21,40 @@ const (
 	EconomicalEstimeMode EstimateMode = "ECONOMICAL"
 )
 
+// EstimateSmartFeeMode is the estimation strategy used by estimatesmartfee.  It
+// is the same type as EstimateMode.
+type EstimateSmartFeeMode = EstimateMode
+
+const (
+	// EstimateModeUnset selects the default estimation strategy of the
+	// server.
+	EstimateModeUnset = UnsetEstimeMode
+
+	// EstimateModeConservative selects an estimate which is more likely to
+	// be sufficient for the desired target, but less responsive to
+	// short-term drops in the prevailing fee market.
+	EstimateModeConservative = ConservativeEstimeMode
+
+	// EstimateModeEconomical selects an estimate which is more responsive
+	// to short-term drops in the prevailing fee market.
+	EstimateModeEconomical = EconomicalEstimeMode
+)
+
+// EstimateSmartFeeCmd defines the estimatesmartfee JSON-RPC command.  The
+// estimation mode is omitted when it is nil, in which case the server uses its
+// default mode.
 type EstimateSmartFeeCmd struct {
-	ConfTarget   uint32       `json:"conf_target"`
-	EstimateMode EstimateMode `json:"estimate_mode,omitempty"`
+	ConfTarget   int64
+	EstimateMode *EstimateSmartFeeMode
 }
 
 // NewEstimateSmartFeeCmd returns a new instance which can be used to issue a
 // estimatesmartfee JSON-RPC command.
-func NewEstimateSmartFeeCmd(confTarget uint32, estimateMode EstimateMode) *EstimateSmartFeeCmd {
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value, which also keeps the
+// command compatible with servers which do not accept an estimation mode.
+func NewEstimateSmartFeeCmd(confTarget int64, estimateMode *EstimateSmartFeeMode) *EstimateSmartFeeCmd {
 	return &EstimateSmartFeeCmd{
 		ConfTarget:   confTarget,
 		EstimateMode: estimateMode,
@@ -169,6 +200,19 @@ func NewGetAddressesByAccountCmd(account string) *GetAddressesByAccountCmd {
 	}
 }
 
+// GetAddressesByLabelCmd defines the getaddressesbylabel JSON-RPC command.
+type GetAddressesByLabelCmd struct {
+	Label string
+}
+
+// NewGetAddressesByLabelCmd returns a new instance which can be used to issue a
+// getaddressesbylabel JSON-RPC command.
+func NewGetAddressesByLabelCmd(label string) *GetAddressesByLabelCmd {
+	return &GetAddressesByLabelCmd{
+		Label: label,
+	}
+}
+
 // GetBalanceCmd defines the getbalance JSON-RPC command.
 type GetBalanceCmd struct {
 	Account *string
@@ -187,13 +231,26 @@ func NewGetBalanceCmd(account *string, minConf *int) *GetBalanceCmd {
 	}
 }
 
-// GetNewAddressCmd defines the getnewaddress JSON-RPC command.
+// GetBalancesCmd defines the getbalances JSON-RPC command.
+type GetBalancesCmd struct{}
+
+// NewGetBalancesCmd returns a new instance which can be used to issue a
+// getbalances JSON-RPC command.
+func NewGetBalancesCmd() *GetBalancesCmd {
+	return &GetBalancesCmd{}
+}
+
+// GetNewAddressCmd defines the getnewaddress JSON-RPC command.  AddressType
+// selects the type of the address, such as "legacy", "p2sh-segwit" or
+// "bech32", on servers which support it.
 type GetNewAddressCmd struct {
-	Account *string
+	Account     *string
+	AddressType *string
 }
 
 // NewGetNewAddressCmd returns a new instance which can be used to issue a
-// getnewaddress JSON-RPC command.
+// getnewaddress JSON-RPC command.  The address type may be set on the returned
+// command.
 //
 // The parameters which are pointers indicate they are optional.  Passing nil
 // for optional parameters will use the default value.
@@ -204,6 +261,8 @@ func NewGetNewAddressCmd(account *string) *GetNewAddressCmd {
 }
 
 // GetRawChangeAddressCmd defines the getrawchangeaddress JSON-RPC command.
+// Servers based on Bitcoin Core do not support accounts and interpret the
+// parameter as the address type instead.
 type GetRawChangeAddressCmd struct {
 	Account *string
 }
@@ -282,6 +341,160 @@ func NewGetWalletInfoCmd() *GetWalletInfoCmd {
 	return &GetWalletInfoCmd{}
 }
 
+// ImportDescriptorsRequest describes a descriptor to import with the
+// importdescriptors JSON-RPC command.  The timestamp is either the UNIX time
+// the wallet is rescanned from or the string "now" to skip the rescan.  The
+// range is either the end of the range or a [begin, end] pair and only applies
+// to ranged descriptors.
+type ImportDescriptorsRequest struct {
+	Descriptor string      `json:"desc"`
+	Active     *bool       `json:"active,omitempty"`
+	Range      interface{} `json:"range,omitempty"`
+	NextIndex  *int        `json:"next_index,omitempty"`
+	Timestamp  interface{} `json:"timestamp"`
+	Internal   *bool       `json:"internal,omitempty"`
+	Label      *string     `json:"label,omitempty"`
+}
+
+// ImportDescriptorsCmd defines the importdescriptors JSON-RPC command.
+type ImportDescriptorsCmd struct {
+	Requests []ImportDescriptorsRequest
+}
+
+// NewImportDescriptorsCmd returns a new instance which can be used to issue an
+// importdescriptors JSON-RPC command.
+func NewImportDescriptorsCmd(requests []ImportDescriptorsRequest) *ImportDescriptorsCmd {
+	return &ImportDescriptorsCmd{
+		Requests: requests,
+	}
+}
+
+// ImportMultiScriptPubKey identifies the output script of an importmulti
+// request, either by the hex-encoded script or by the address it pays to.  The
+// script is marshalled as a string and the address as an object with the
+// address field.  Exactly one of them must be set.
+type ImportMultiScriptPubKey struct {
+	Script  string
+	Address string
+}
+
+// importMultiAddressJSON is the object form of an ImportMultiScriptPubKey.
+type importMultiAddressJSON struct {
+	Address string `json:"address"`
+}
+
+// MarshalJSON provides a custom Marshal method for ImportMultiScriptPubKey.
+func (s ImportMultiScriptPubKey) MarshalJSON() ([]byte, error) {
+	if (s.Script == "") == (s.Address == "") {
+		str := "the scriptPubKey field must have either a script or " +
+			"an address"
+		return nil, makeError(ErrInvalidType, str)
+	}
+	if s.Address != "" {
+		return json.Marshal(importMultiAddressJSON{Address: s.Address})
+	}
+	if _, err := hex.DecodeString(s.Script); err != nil {
+		str := "the scriptPubKey field must be a hex-encoded script"
+		return nil, makeError(ErrInvalidType, str)
+	}
+	return json.Marshal(s.Script)
+}
+
+// UnmarshalJSON provides a custom Unmarshal method for ImportMultiScriptPubKey.
+func (s *ImportMultiScriptPubKey) UnmarshalJSON(data []byte) error {
+	var script string
+	if err := json.Unmarshal(data, &script); err == nil {
+		if _, err := hex.DecodeString(script); err != nil || script == "" {
+			str := "the scriptPubKey field must be a hex-encoded script"
+			return makeError(ErrInvalidType, str)
+		}
+		s.Script, s.Address = script, ""
+		return nil
+	}
+
+	var obj importMultiAddressJSON
+	if err := json.Unmarshal(data, &obj); err != nil || obj.Address == "" {
+		str := "the scriptPubKey field must be a script or an object " +
+			"with the address field"
+		return makeError(ErrInvalidType, str)
+	}
+	s.Script, s.Address = "", obj.Address
+	return nil
+}
+
+// TimestampOrNow represents the time the wallet is rescanned from for an
+// importmulti request.  It is marshalled as the UNIX time in Value, or as the
+// string "now" to skip the rescan when Now is set.
+type TimestampOrNow struct {
+	Value int64
+	Now   bool
+}
+
+// MarshalJSON provides a custom Marshal method for TimestampOrNow.
+func (t TimestampOrNow) MarshalJSON() ([]byte, error) {
+	if t.Now {
+		return json.Marshal("now")
+	}
+	if t.Value < 0 {
+		str := "the timestamp field must not be negative"
+		return nil, makeError(ErrInvalidType, str)
+	}
+	return json.Marshal(t.Value)
+}
+
+// UnmarshalJSON provides a custom Unmarshal method for TimestampOrNow.
+func (t *TimestampOrNow) UnmarshalJSON(data []byte) error {
+	var value int64
+	if err := json.Unmarshal(data, &value); err == nil && value >= 0 {
+		t.Value, t.Now = value, false
+		return nil
+	}
+
+	var now string
+	if err := json.Unmarshal(data, &now); err != nil || now != "now" {
+		str := "the timestamp field must be a UNIX time or \"now\""
+		return makeError(ErrInvalidType, str)
+	}
+	t.Value, t.Now = 0, true
+	return nil
+}
+
+// ImportMultiRequest describes an address or script to import with the
+// importmulti JSON-RPC command.
+type ImportMultiRequest struct {
+	ScriptPubKey ImportMultiScriptPubKey `json:"scriptPubKey"`
+	Timestamp    TimestampOrNow          `json:"timestamp"`
+	RedeemScript *string                 `json:"redeemscript,omitempty"`
+	PubKeys      []string                `json:"pubkeys,omitempty"`
+	Keys         []string                `json:"keys,omitempty"`
+	Internal     *bool                   `json:"internal,omitempty"`
+	WatchOnly    *bool                   `json:"watchonly,omitempty"`
+	Label        *string                 `json:"label,omitempty"`
+}
+
+// ImportMultiOptions models the options of the importmulti JSON-RPC command.
+type ImportMultiOptions struct {
+	Rescan *bool `json:"rescan,omitempty"`
+}
+
+// ImportMultiCmd defines the importmulti JSON-RPC command.
+type ImportMultiCmd struct {
+	Requests []ImportMultiRequest
+	Options  *ImportMultiOptions
+}
+
+// NewImportMultiCmd returns a new instance which can be used to issue an
+// importmulti JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewImportMultiCmd(requests []ImportMultiRequest, options *ImportMultiOptions) *ImportMultiCmd {
+	return &ImportMultiCmd{
+		Requests: requests,
+		Options:  options,
+	}
+}
+
 // ImportPrivKeyCmd defines the importprivkey JSON-RPC command.
 type ImportPrivKeyCmd struct {
 	PrivKey string
@@ -343,6 +556,22 @@ func NewListAddressGroupingsCmd() *ListAddressGroupingsCmd {
 	return &ListAddressGroupingsCmd{}
 }
 
+// ListDescriptorsCmd defines the listdescriptors JSON-RPC command.
+type ListDescriptorsCmd struct {
+	Private *bool `jsonrpcdefault:"false"`
+}
+
+// NewListDescriptorsCmd returns a new instance which can be used to issue a
+// listdescriptors JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewListDescriptorsCmd(private *bool) *ListDescriptorsCmd {
+	return &ListDescriptorsCmd{
+		Private: private,
+	}
+}
+
 // ListLockUnspentCmd defines the listlockunspent JSON-RPC command.
 type ListLockUnspentCmd struct{}
 
@@ -392,6 +621,26 @@ func NewListReceivedByAddressCmd(minConf *int, includeEmpty, includeWatchOnly *b
 	}
 }
 
+// ListReceivedByLabelCmd defines the listreceivedbylabel JSON-RPC command.
+type ListReceivedByLabelCmd struct {
+	MinConf          *int  `jsonrpcdefault:"1"`
+	IncludeEmpty     *bool `jsonrpcdefault:"false"`
+	IncludeWatchOnly *bool `jsonrpcdefault:"false"`
+}
+
+// NewListReceivedByLabelCmd returns a new instance which can be used to issue
+// a listreceivedbylabel JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewListReceivedByLabelCmd(minConf *int, includeEmpty, includeWatchOnly *bool) *ListReceivedByLabelCmd {
+	return &ListReceivedByLabelCmd{
+		MinConf:          minConf,
+		IncludeEmpty:     includeEmpty,
+		IncludeWatchOnly: includeWatchOnly,
+	}
+}
+
 // ListSinceBlockCmd defines the listsinceblock JSON-RPC command.
 type ListSinceBlockCmd struct {
 	BlockHash           *string
@@ -485,8 +734,8 @@ func NewLockUnspentCmd(unlock bool, transactions []TransactionInput) *LockUnspen
 type MoveCmd struct {
 	FromAccount string
 	ToAccount   string
-	Amount      float64 // In BTC
-	MinConf     *int    `jsonrpcdefault:"1"`
+	Amount      Amount // In BTC
+	MinConf     *int   `jsonrpcdefault:"1"`
 	Comment     *string
 }
 
@@ -499,7 +748,7 @@ func NewMoveCmd(fromAccount, toAccount string, amount float64, minConf *int, com
 	return &MoveCmd{
 		FromAccount: fromAccount,
 		ToAccount:   toAccount,
-		Amount:      amount,
+		Amount:      Amount(amount),
 		MinConf:     minConf,
 		Comment:     comment,
 	}
@@ -509,8 +758,8 @@ func NewMoveCmd(fromAccount, toAccount string, amount float64, minConf *int, com
 type SendFromCmd struct {
 	FromAccount string
 	ToAddress   string
-	Amount      float64 // In BTC
-	MinConf     *int    `jsonrpcdefault:"1"`
+	Amount      Amount // In BTC
+	MinConf     *int   `jsonrpcdefault:"1"`
 	Comment     *string
 	CommentTo   *string
 }
@@ -524,7 +773,7 @@ func NewSendFromCmd(fromAccount, toAddress string, amount float64, minConf *int,
 	return &SendFromCmd{
 		FromAccount: fromAccount,
 		ToAddress:   toAddress,
-		Amount:      amount,
+		Amount:      Amount(amount),
 		MinConf:     minConf,
 		Comment:     comment,
 		CommentTo:   commentTo,
@@ -534,8 +783,8 @@ func NewSendFromCmd(fromAccount, toAddress string, amount float64, minConf *int,
 // SendManyCmd defines the sendmany JSON-RPC command.
 type SendManyCmd struct {
 	FromAccount string
-	Amounts     map[string]float64 `jsonrpcusage:"{\"address\":amount,...}"` // In BTC
-	MinConf     *int               `jsonrpcdefault:"1"`
+	Amounts     map[string]Amount `jsonrpcusage:"{\"address\":amount,...}"` // In BTC
+	MinConf     *int              `jsonrpcdefault:"1"`
 	Comment     *string
 }
 
@@ -547,18 +796,28 @@ type SendManyCmd struct {
 func NewSendManyCmd(fromAccount string, amounts map[string]float64, minConf *int, comment *string) *SendManyCmd {
 	return &SendManyCmd{
 		FromAccount: fromAccount,
-		Amounts:     amounts,
+		Amounts:     amountMap(amounts),
 		MinConf:     minConf,
 		Comment:     comment,
 	}
 }
 
 // SendToAddressCmd defines the sendtoaddress JSON-RPC command.
+//
+// SubtractFeeFromAmount, Replaceable, ConfTarget and EstimateMode require
+// Bitcoin Core 0.16 or later, and AvoidReuse requires 0.19 or later.  They are
+// only sent when set, so requests which leave them nil remain compatible with
+// older nodes and btcwallet.
 type SendToAddressCmd struct {
-	Address   string
-	Amount    float64
-	Comment   *string
-	CommentTo *string
+	Address               string
+	Amount                Amount
+	Comment               *string
+	CommentTo             *string
+	SubtractFeeFromAmount *bool
+	Replaceable           *bool
+	ConfTarget            *int
+	EstimateMode          *string
+	AvoidReuse            *bool
 }
 
 // NewSendToAddressCmd returns a new instance which can be used to issue a
@@ -569,7 +828,7 @@ type SendToAddressCmd struct {
 func NewSendToAddressCmd(address string, amount float64, comment, commentTo *string) *SendToAddressCmd {
 	return &SendToAddressCmd{
 		Address:   address,
-		Amount:    amount,
+		Amount:    Amount(amount),
 		Comment:   comment,
 		CommentTo: commentTo,
 	}
@@ -590,16 +849,35 @@ func NewSetAccountCmd(address, account string) *SetAccountCmd {
 	}
 }
 
+// SetHdSeedCmd defines the sethdseed JSON-RPC command.
+type SetHdSeedCmd struct {
+	NewKeyPool *bool `jsonrpcdefault:"true"`
+	Seed       *string
+}
+
+// NewSetHdSeedCmd returns a new instance which can be used to issue a
+// sethdseed JSON-RPC command.  The seed, when provided, is a private key in
+// wallet import format (WIF).
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewSetHdSeedCmd(newKeyPool *bool, seed *string) *SetHdSeedCmd {
+	return &SetHdSeedCmd{
+		NewKeyPool: newKeyPool,
+		Seed:       seed,
+	}
+}
+
 // SetTxFeeCmd defines the settxfee JSON-RPC command.
 type SetTxFeeCmd struct {
-	Amount float64 // In BTC
+	Amount Amount // In BTC
 }
 
 // NewSetTxFeeCmd returns a new instance which can be used to issue a settxfee
 // JSON-RPC command.
 func NewSetTxFeeCmd(amount float64) *SetTxFeeCmd {
 	return &SetTxFeeCmd{
-		Amount: amount,
+		Amount: Amount(amount),
 	}
 }
 
@@ -649,6 +927,69 @@ func NewSignRawTransactionCmd(hexEncodedTx string, inputs *[]RawTxInput, privKey
 	}
 }
 
+// SignRawTransactionWithWalletCmd defines the signrawtransactionwithwallet
+// JSON-RPC command.
+type SignRawTransactionWithWalletCmd struct {
+	RawTx       string
+	PrevTxs     *[]RawTxWitnessInput
+	SigHashType *string `jsonrpcdefault:"\"ALL\""`
+}
+
+// NewSignRawTransactionWithWalletCmd returns a new instance which can be used
+// to issue a signrawtransactionwithwallet JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewSignRawTransactionWithWalletCmd(hexEncodedTx string, prevTxs *[]RawTxWitnessInput, sigHashType *string) *SignRawTransactionWithWalletCmd {
+	return &SignRawTransactionWithWalletCmd{
+		RawTx:       hexEncodedTx,
+		PrevTxs:     prevTxs,
+		SigHashType: sigHashType,
+	}
+}
+
+// WalletCreateFundedPsbtOpts models the options of the walletcreatefundedpsbt
+// JSON-RPC command.  The fee rate is in BTC/kB.
+type WalletCreateFundedPsbtOpts struct {
+	ChangeAddress          *string       `json:"changeAddress,omitempty"`
+	ChangePosition         *int64        `json:"changePosition,omitempty"`
+	ChangeType             *string       `json:"change_type,omitempty"`
+	IncludeWatching        *bool         `json:"includeWatching,omitempty"`
+	LockUnspents           *bool         `json:"lockUnspents,omitempty"`
+	FeeRate                *float64      `json:"feeRate,omitempty"`
+	SubtractFeeFromOutputs []int64       `json:"subtractFeeFromOutputs,omitempty"`
+	Replaceable            *bool         `json:"replaceable,omitempty"`
+	ConfTarget             *int64        `json:"conf_target,omitempty"`
+	EstimateMode           *EstimateMode `json:"estimate_mode,omitempty"`
+}
+
+// WalletCreateFundedPsbtCmd defines the walletcreatefundedpsbt JSON-RPC
+// command.
+type WalletCreateFundedPsbtCmd struct {
+	Inputs      []TransactionInput
+	Outputs     map[string]Amount `jsonrpcusage:"{\"address\":amount,...}"` // In BTC
+	LockTime    *uint32
+	Options     *WalletCreateFundedPsbtOpts
+	Bip32Derivs *bool
+}
+
+// NewWalletCreateFundedPsbtCmd returns a new instance which can be used to
+// issue a walletcreatefundedpsbt JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.  Outputs are in BTC.
+func NewWalletCreateFundedPsbtCmd(inputs []TransactionInput, outputs map[string]float64,
+	lockTime *uint32, options *WalletCreateFundedPsbtOpts, bip32Derivs *bool) *WalletCreateFundedPsbtCmd {
+
+	return &WalletCreateFundedPsbtCmd{
+		Inputs:      inputs,
+		Outputs:     amountMap(outputs),
+		LockTime:    lockTime,
+		Options:     options,
+		Bip32Derivs: bip32Derivs,
+	}
+}
+
 // WalletLockCmd defines the walletlock JSON-RPC command.
 type WalletLockCmd struct{}
 
@@ -688,6 +1029,28 @@ func NewWalletPassphraseChangeCmd(oldPassphrase, newPassphrase string) *WalletPa
 	}
 }
 
+// WalletProcessPsbtCmd defines the walletprocesspsbt JSON-RPC command.
+type WalletProcessPsbtCmd struct {
+	Psbt        string
+	Sign        *bool   `jsonrpcdefault:"true"`
+	SighashType *string `jsonrpcdefault:"\"ALL\""`
+	Bip32Derivs *bool
+}
+
+// NewWalletProcessPsbtCmd returns a new instance which can be used to issue a
+// walletprocesspsbt JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewWalletProcessPsbtCmd(psbt string, sign *bool, sighashType *string, bip32Derivs *bool) *WalletProcessPsbtCmd {
+	return &WalletProcessPsbtCmd{
+		Psbt:        psbt,
+		Sign:        sign,
+		SighashType: sighashType,
+		Bip32Derivs: bip32Derivs,
+	}
+}
+
 func init() {
 	// The commands in this file are only usable with a wallet server.
 	flags := UFWalletOnly
@@ -696,42 +1059,52 @@ func init() {
 	MustRegisterCmd("addwitnessaddress", (*AddWitnessAddressCmd)(nil), flags)
 	MustRegisterCmd("createmultisig", (*CreateMultisigCmd)(nil), flags)
 	MustRegisterCmd("dumpprivkey", (*DumpPrivKeyCmd)(nil), flags)
-	MustRegisterCmd("encryptwallet", (*EncryptWalletCmd)(nil), flags)
+	MustRegisterCmd("encryptwallet", (*EncryptWalletCmd)(nil), flags|UFNonIdempotent)
 	MustRegisterCmd("estimatefee", (*EstimateFeeCmd)(nil), flags)
 	MustRegisterCmd("estimatesmartfee", (*EstimateSmartFeeCmd)(nil), flags)
 	MustRegisterCmd("estimatepriority", (*EstimatePriorityCmd)(nil), flags)
 	MustRegisterCmd("getaccount", (*GetAccountCmd)(nil), flags)
 	MustRegisterCmd("getaccountaddress", (*GetAccountAddressCmd)(nil), flags)
 	MustRegisterCmd("getaddressesbyaccount", (*GetAddressesByAccountCmd)(nil), flags)
+	MustRegisterCmd("getaddressesbylabel", (*GetAddressesByLabelCmd)(nil), flags)
 	MustRegisterCmd("getbalance", (*GetBalanceCmd)(nil), flags)
-	MustRegisterCmd("getnewaddress", (*GetNewAddressCmd)(nil), flags)
-	MustRegisterCmd("getrawchangeaddress", (*GetRawChangeAddressCmd)(nil), flags)
+	MustRegisterCmd("getbalances", (*GetBalancesCmd)(nil), flags)
+	MustRegisterCmd("getnewaddress", (*GetNewAddressCmd)(nil), flags|UFNonIdempotent)
+	MustRegisterCmd("getrawchangeaddress", (*GetRawChangeAddressCmd)(nil), flags|UFNonIdempotent)
 	MustRegisterCmd("getreceivedbyaccount", (*GetReceivedByAccountCmd)(nil), flags)
 	MustRegisterCmd("getreceivedbyaddress", (*GetReceivedByAddressCmd)(nil), flags)
 	MustRegisterCmd("gettransaction", (*GetTransactionCmd)(nil), flags)
 	MustRegisterCmd("getwalletinfo", (*GetWalletInfoCmd)(nil), flags)
+	MustRegisterCmd("importdescriptors", (*ImportDescriptorsCmd)(nil), flags)
+	MustRegisterCmd("importmulti", (*ImportMultiCmd)(nil), flags)
 	MustRegisterCmd("importprivkey", (*ImportPrivKeyCmd)(nil), flags)
 	MustRegisterCmd("keypoolrefill", (*KeyPoolRefillCmd)(nil), flags)
 	MustRegisterCmd("listaccounts", (*ListAccountsCmd)(nil), flags)
 	MustRegisterCmd("listaddressgroupings", (*ListAddressGroupingsCmd)(nil), flags)
+	MustRegisterCmd("listdescriptors", (*ListDescriptorsCmd)(nil), flags)
 	MustRegisterCmd("listlockunspent", (*ListLockUnspentCmd)(nil), flags)
 	MustRegisterCmd("listreceivedbyaccount", (*ListReceivedByAccountCmd)(nil), flags)
 	MustRegisterCmd("listreceivedbyaddress", (*ListReceivedByAddressCmd)(nil), flags)
+	MustRegisterCmd("listreceivedbylabel", (*ListReceivedByLabelCmd)(nil), flags)
 	MustRegisterCmd("listsinceblock", (*ListSinceBlockCmd)(nil), flags)
 	MustRegisterCmd("listtransactions", (*ListTransactionsCmd)(nil), flags)
 	MustRegisterCmd("listunspent", (*ListUnspentCmd)(nil), flags)
 	MustRegisterCmd("lockunspent", (*LockUnspentCmd)(nil), flags)
-	MustRegisterCmd("move", (*MoveCmd)(nil), flags)
-	MustRegisterCmd("sendfrom", (*SendFromCmd)(nil), flags)
-	MustRegisterCmd("sendmany", (*SendManyCmd)(nil), flags)
-	MustRegisterCmd("sendtoaddress", (*SendToAddressCmd)(nil), flags)
+	MustRegisterCmd("move", (*MoveCmd)(nil), flags|UFNonIdempotent)
+	MustRegisterCmd("sendfrom", (*SendFromCmd)(nil), flags|UFNonIdempotent)
+	MustRegisterCmd("sendmany", (*SendManyCmd)(nil), flags|UFNonIdempotent)
+	MustRegisterCmd("sendtoaddress", (*SendToAddressCmd)(nil), flags|UFNonIdempotent)
 	MustRegisterCmd("setaccount", (*SetAccountCmd)(nil), flags)
+	MustRegisterCmd("sethdseed", (*SetHdSeedCmd)(nil), flags|UFNonIdempotent)
 	MustRegisterCmd("settxfee", (*SetTxFeeCmd)(nil), flags)
 	MustRegisterCmd("signmessage", (*SignMessageCmd)(nil), flags)
 	MustRegisterCmd("signrawtransaction", (*SignRawTransactionCmd)(nil), flags)
+	MustRegisterCmd("signrawtransactionwithwallet", (*SignRawTransactionWithWalletCmd)(nil), flags)
+	MustRegisterCmd("walletcreatefundedpsbt", (*WalletCreateFundedPsbtCmd)(nil), flags)
 	MustRegisterCmd("walletlock", (*WalletLockCmd)(nil), flags)
 	MustRegisterCmd("walletpassphrase", (*WalletPassphraseCmd)(nil), flags)
 	MustRegisterCmd("walletpassphrasechange", (*WalletPassphraseChangeCmd)(nil), flags)
+	MustRegisterCmd("walletprocesspsbt", (*WalletProcessPsbtCmd)(nil), flags)
 	MustRegisterCmd("rescanblockchain", (*RescanBlockChainCmd)(nil), flags)
 
 	MustRegisterCmd("omni_getbalance", (*OmniGetbalanceCmd)(nil), flags)
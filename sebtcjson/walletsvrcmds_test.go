@@ -8,6 +8,7 @@ package sebtcjson
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
@@ -140,6 +141,37 @@ func TestWalletSvrCmds(t *testing.T) {
 				NumBlocks: 6,
 			},
 		},
+		{
+			name: "estimatesmartfee",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("estimatesmartfee", 6)
+			},
+			staticCmd: func() interface{} {
+				return NewEstimateSmartFeeCmd(6, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"estimatesmartfee","params":[6],"id":1}`,
+			unmarshalled: &EstimateSmartFeeCmd{
+				ConfTarget: 6,
+			},
+		},
+		{
+			name: "estimatesmartfee optional",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("estimatesmartfee", 6, EstimateModeEconomical)
+			},
+			staticCmd: func() interface{} {
+				mode := EstimateModeEconomical
+				return NewEstimateSmartFeeCmd(6, &mode)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"estimatesmartfee","params":[6,"ECONOMICAL"],"id":1}`,
+			unmarshalled: &EstimateSmartFeeCmd{
+				ConfTarget: 6,
+				EstimateMode: func() *EstimateSmartFeeMode {
+					mode := EstimateModeEconomical
+					return &mode
+				}(),
+			},
+		},
 		{
 			name: "getaccount",
 			newCmd: func() (interface{}, error) {
@@ -179,6 +211,19 @@ func TestWalletSvrCmds(t *testing.T) {
 				Account: "acct",
 			},
 		},
+		{
+			name: "getaddressesbylabel",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("getaddressesbylabel", "label")
+			},
+			staticCmd: func() interface{} {
+				return NewGetAddressesByLabelCmd("label")
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getaddressesbylabel","params":["label"],"id":1}`,
+			unmarshalled: &GetAddressesByLabelCmd{
+				Label: "label",
+			},
+		},
 		{
 			name: "getbalance",
 			newCmd: func() (interface{}, error) {
@@ -221,6 +266,17 @@ func TestWalletSvrCmds(t *testing.T) {
 				MinConf: Int(6),
 			},
 		},
+		{
+			name: "getbalances",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("getbalances")
+			},
+			staticCmd: func() interface{} {
+				return NewGetBalancesCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getbalances","params":[],"id":1}`,
+			unmarshalled: &GetBalancesCmd{},
+		},
 		{
 			name: "getnewaddress",
 			newCmd: func() (interface{}, error) {
@@ -247,6 +303,22 @@ func TestWalletSvrCmds(t *testing.T) {
 				Account: String("acct"),
 			},
 		},
+		{
+			name: "getnewaddress address type",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("getnewaddress", "acct", "bech32")
+			},
+			staticCmd: func() interface{} {
+				cmd := NewGetNewAddressCmd(String("acct"))
+				cmd.AddressType = String("bech32")
+				return cmd
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getnewaddress","params":["acct","bech32"],"id":1}`,
+			unmarshalled: &GetNewAddressCmd{
+				Account:     String("acct"),
+				AddressType: String("bech32"),
+			},
+		},
 		{
 			name: "getrawchangeaddress",
 			newCmd: func() (interface{}, error) {
@@ -368,6 +440,104 @@ func TestWalletSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"getwalletinfo","params":[],"id":1}`,
 			unmarshalled: &GetWalletInfoCmd{},
 		},
+		{
+			name: "importdescriptors",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("importdescriptors", `[{"desc":"raw(deadbeef)#89f8spxm","active":false,"timestamp":"now"},{"desc":"addr(mkmZxiEcEd8ZqjQWVZuC6so5dFMKEFpN2j)#02wpgw69","range":[0,99],"timestamp":0,"label":"watch"}]`)
+			},
+			staticCmd: func() interface{} {
+				return NewImportDescriptorsCmd([]ImportDescriptorsRequest{
+					{
+						Descriptor: "raw(deadbeef)#89f8spxm",
+						Active:     Bool(false),
+						Timestamp:  "now",
+					},
+					{
+						Descriptor: "addr(mkmZxiEcEd8ZqjQWVZuC6so5dFMKEFpN2j)#02wpgw69",
+						Range:      []int{0, 99},
+						Timestamp:  0,
+						Label:      String("watch"),
+					},
+				})
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"importdescriptors","params":[[{"desc":"raw(deadbeef)#89f8spxm","active":false,"timestamp":"now"},{"desc":"addr(mkmZxiEcEd8ZqjQWVZuC6so5dFMKEFpN2j)#02wpgw69","range":[0,99],"timestamp":0,"label":"watch"}]],"id":1}`,
+			unmarshalled: &ImportDescriptorsCmd{
+				Requests: []ImportDescriptorsRequest{
+					{
+						Descriptor: "raw(deadbeef)#89f8spxm",
+						Active:     Bool(false),
+						Timestamp:  "now",
+					},
+					{
+						Descriptor: "addr(mkmZxiEcEd8ZqjQWVZuC6so5dFMKEFpN2j)#02wpgw69",
+						Range:      []interface{}{float64(0), float64(99)},
+						Timestamp:  float64(0),
+						Label:      String("watch"),
+					},
+				},
+			},
+		},
+		{
+			name: "importmulti",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("importmulti", `[{"scriptPubKey":{"address":"1Address"},"timestamp":"now","watchonly":true,"label":"watch"},{"scriptPubKey":"a914","timestamp":1455191480,"redeemscript":"51","pubkeys":["02ab"],"keys":["cKey"],"internal":true}]`)
+			},
+			staticCmd: func() interface{} {
+				return NewImportMultiCmd([]ImportMultiRequest{
+					{
+						ScriptPubKey: ImportMultiScriptPubKey{Address: "1Address"},
+						Timestamp:    TimestampOrNow{Now: true},
+						WatchOnly:    Bool(true),
+						Label:        String("watch"),
+					},
+					{
+						ScriptPubKey: ImportMultiScriptPubKey{Script: "a914"},
+						Timestamp:    TimestampOrNow{Value: 1455191480},
+						RedeemScript: String("51"),
+						PubKeys:      []string{"02ab"},
+						Keys:         []string{"cKey"},
+						Internal:     Bool(true),
+					},
+				}, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"importmulti","params":[[{"scriptPubKey":{"address":"1Address"},"timestamp":"now","watchonly":true,"label":"watch"},{"scriptPubKey":"a914","timestamp":1455191480,"redeemscript":"51","pubkeys":["02ab"],"keys":["cKey"],"internal":true}]],"id":1}`,
+			unmarshalled: &ImportMultiCmd{
+				Requests: []ImportMultiRequest{
+					{
+						ScriptPubKey: ImportMultiScriptPubKey{Address: "1Address"},
+						Timestamp:    TimestampOrNow{Now: true},
+						WatchOnly:    Bool(true),
+						Label:        String("watch"),
+					},
+					{
+						ScriptPubKey: ImportMultiScriptPubKey{Script: "a914"},
+						Timestamp:    TimestampOrNow{Value: 1455191480},
+						RedeemScript: String("51"),
+						PubKeys:      []string{"02ab"},
+						Keys:         []string{"cKey"},
+						Internal:     Bool(true),
+					},
+				},
+			},
+		},
+		{
+			name: "importmulti options",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("importmulti", `[{"scriptPubKey":"a914","timestamp":0}]`, `{"rescan":false}`)
+			},
+			staticCmd: func() interface{} {
+				return NewImportMultiCmd([]ImportMultiRequest{
+					{ScriptPubKey: ImportMultiScriptPubKey{Script: "a914"}},
+				}, &ImportMultiOptions{Rescan: Bool(false)})
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"importmulti","params":[[{"scriptPubKey":"a914","timestamp":0}],{"rescan":false}],"id":1}`,
+			unmarshalled: &ImportMultiCmd{
+				Requests: []ImportMultiRequest{
+					{ScriptPubKey: ImportMultiScriptPubKey{Script: "a914"}},
+				},
+				Options: &ImportMultiOptions{Rescan: Bool(false)},
+			},
+		},
 		{
 			name: "importprivkey",
 			newCmd: func() (interface{}, error) {
@@ -476,6 +646,32 @@ func TestWalletSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"listaddressgroupings","params":[],"id":1}`,
 			unmarshalled: &ListAddressGroupingsCmd{},
 		},
+		{
+			name: "listdescriptors",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("listdescriptors")
+			},
+			staticCmd: func() interface{} {
+				return NewListDescriptorsCmd(nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"listdescriptors","params":[],"id":1}`,
+			unmarshalled: &ListDescriptorsCmd{
+				Private: Bool(false),
+			},
+		},
+		{
+			name: "listdescriptors optional",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("listdescriptors", true)
+			},
+			staticCmd: func() interface{} {
+				return NewListDescriptorsCmd(Bool(true))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"listdescriptors","params":[true],"id":1}`,
+			unmarshalled: &ListDescriptorsCmd{
+				Private: Bool(true),
+			},
+		},
 		{
 			name: "listlockunspent",
 			newCmd: func() (interface{}, error) {
@@ -607,6 +803,36 @@ func TestWalletSvrCmds(t *testing.T) {
 				IncludeWatchOnly: Bool(false),
 			},
 		},
+		{
+			name: "listreceivedbylabel",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("listreceivedbylabel")
+			},
+			staticCmd: func() interface{} {
+				return NewListReceivedByLabelCmd(nil, nil, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"listreceivedbylabel","params":[],"id":1}`,
+			unmarshalled: &ListReceivedByLabelCmd{
+				MinConf:          Int(1),
+				IncludeEmpty:     Bool(false),
+				IncludeWatchOnly: Bool(false),
+			},
+		},
+		{
+			name: "listreceivedbylabel optional3",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("listreceivedbylabel", 0, true, true)
+			},
+			staticCmd: func() interface{} {
+				return NewListReceivedByLabelCmd(Int(0), Bool(true), Bool(true))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"listreceivedbylabel","params":[0,true,true],"id":1}`,
+			unmarshalled: &ListReceivedByLabelCmd{
+				MinConf:          Int(0),
+				IncludeEmpty:     Bool(true),
+				IncludeWatchOnly: Bool(true),
+			},
+		},
 		{
 			name: "listsinceblock",
 			newCmd: func() (interface{}, error) {
@@ -837,7 +1063,7 @@ func TestWalletSvrCmds(t *testing.T) {
 			staticCmd: func() interface{} {
 				return NewMoveCmd("from", "to", 0.5, nil, nil)
 			},
-			marshalled: `{"jsonrpc":"1.0","method":"move","params":["from","to",0.5],"id":1}`,
+			marshalled: `{"jsonrpc":"1.0","method":"move","params":["from","to",0.50000000],"id":1}`,
 			unmarshalled: &MoveCmd{
 				FromAccount: "from",
 				ToAccount:   "to",
@@ -854,7 +1080,7 @@ func TestWalletSvrCmds(t *testing.T) {
 			staticCmd: func() interface{} {
 				return NewMoveCmd("from", "to", 0.5, Int(6), nil)
 			},
-			marshalled: `{"jsonrpc":"1.0","method":"move","params":["from","to",0.5,6],"id":1}`,
+			marshalled: `{"jsonrpc":"1.0","method":"move","params":["from","to",0.50000000,6],"id":1}`,
 			unmarshalled: &MoveCmd{
 				FromAccount: "from",
 				ToAccount:   "to",
@@ -871,7 +1097,7 @@ func TestWalletSvrCmds(t *testing.T) {
 			staticCmd: func() interface{} {
 				return NewMoveCmd("from", "to", 0.5, Int(6), String("comment"))
 			},
-			marshalled: `{"jsonrpc":"1.0","method":"move","params":["from","to",0.5,6,"comment"],"id":1}`,
+			marshalled: `{"jsonrpc":"1.0","method":"move","params":["from","to",0.50000000,6,"comment"],"id":1}`,
 			unmarshalled: &MoveCmd{
 				FromAccount: "from",
 				ToAccount:   "to",
@@ -888,7 +1114,7 @@ func TestWalletSvrCmds(t *testing.T) {
 			staticCmd: func() interface{} {
 				return NewSendFromCmd("from", "1Address", 0.5, nil, nil, nil)
 			},
-			marshalled: `{"jsonrpc":"1.0","method":"sendfrom","params":["from","1Address",0.5],"id":1}`,
+			marshalled: `{"jsonrpc":"1.0","method":"sendfrom","params":["from","1Address",0.50000000],"id":1}`,
 			unmarshalled: &SendFromCmd{
 				FromAccount: "from",
 				ToAddress:   "1Address",
@@ -906,7 +1132,7 @@ func TestWalletSvrCmds(t *testing.T) {
 			staticCmd: func() interface{} {
 				return NewSendFromCmd("from", "1Address", 0.5, Int(6), nil, nil)
 			},
-			marshalled: `{"jsonrpc":"1.0","method":"sendfrom","params":["from","1Address",0.5,6],"id":1}`,
+			marshalled: `{"jsonrpc":"1.0","method":"sendfrom","params":["from","1Address",0.50000000,6],"id":1}`,
 			unmarshalled: &SendFromCmd{
 				FromAccount: "from",
 				ToAddress:   "1Address",
@@ -925,7 +1151,7 @@ func TestWalletSvrCmds(t *testing.T) {
 				return NewSendFromCmd("from", "1Address", 0.5, Int(6),
 					String("comment"), nil)
 			},
-			marshalled: `{"jsonrpc":"1.0","method":"sendfrom","params":["from","1Address",0.5,6,"comment"],"id":1}`,
+			marshalled: `{"jsonrpc":"1.0","method":"sendfrom","params":["from","1Address",0.50000000,6,"comment"],"id":1}`,
 			unmarshalled: &SendFromCmd{
 				FromAccount: "from",
 				ToAddress:   "1Address",
@@ -944,7 +1170,7 @@ func TestWalletSvrCmds(t *testing.T) {
 				return NewSendFromCmd("from", "1Address", 0.5, Int(6),
 					String("comment"), String("commentto"))
 			},
-			marshalled: `{"jsonrpc":"1.0","method":"sendfrom","params":["from","1Address",0.5,6,"comment","commentto"],"id":1}`,
+			marshalled: `{"jsonrpc":"1.0","method":"sendfrom","params":["from","1Address",0.50000000,6,"comment","commentto"],"id":1}`,
 			unmarshalled: &SendFromCmd{
 				FromAccount: "from",
 				ToAddress:   "1Address",
@@ -963,10 +1189,10 @@ func TestWalletSvrCmds(t *testing.T) {
 				amounts := map[string]float64{"1Address": 0.5}
 				return NewSendManyCmd("from", amounts, nil, nil)
 			},
-			marshalled: `{"jsonrpc":"1.0","method":"sendmany","params":["from",{"1Address":0.5}],"id":1}`,
+			marshalled: `{"jsonrpc":"1.0","method":"sendmany","params":["from",{"1Address":0.50000000}],"id":1}`,
 			unmarshalled: &SendManyCmd{
 				FromAccount: "from",
-				Amounts:     map[string]float64{"1Address": 0.5},
+				Amounts:     map[string]Amount{"1Address": 0.5},
 				MinConf:     Int(1),
 				Comment:     nil,
 			},
@@ -980,10 +1206,10 @@ func TestWalletSvrCmds(t *testing.T) {
 				amounts := map[string]float64{"1Address": 0.5}
 				return NewSendManyCmd("from", amounts, Int(6), nil)
 			},
-			marshalled: `{"jsonrpc":"1.0","method":"sendmany","params":["from",{"1Address":0.5},6],"id":1}`,
+			marshalled: `{"jsonrpc":"1.0","method":"sendmany","params":["from",{"1Address":0.50000000},6],"id":1}`,
 			unmarshalled: &SendManyCmd{
 				FromAccount: "from",
-				Amounts:     map[string]float64{"1Address": 0.5},
+				Amounts:     map[string]Amount{"1Address": 0.5},
 				MinConf:     Int(6),
 				Comment:     nil,
 			},
@@ -997,10 +1223,10 @@ func TestWalletSvrCmds(t *testing.T) {
 				amounts := map[string]float64{"1Address": 0.5}
 				return NewSendManyCmd("from", amounts, Int(6), String("comment"))
 			},
-			marshalled: `{"jsonrpc":"1.0","method":"sendmany","params":["from",{"1Address":0.5},6,"comment"],"id":1}`,
+			marshalled: `{"jsonrpc":"1.0","method":"sendmany","params":["from",{"1Address":0.50000000},6,"comment"],"id":1}`,
 			unmarshalled: &SendManyCmd{
 				FromAccount: "from",
-				Amounts:     map[string]float64{"1Address": 0.5},
+				Amounts:     map[string]Amount{"1Address": 0.5},
 				MinConf:     Int(6),
 				Comment:     String("comment"),
 			},
@@ -1013,7 +1239,7 @@ func TestWalletSvrCmds(t *testing.T) {
 			staticCmd: func() interface{} {
 				return NewSendToAddressCmd("1Address", 0.5, nil, nil)
 			},
-			marshalled: `{"jsonrpc":"1.0","method":"sendtoaddress","params":["1Address",0.5],"id":1}`,
+			marshalled: `{"jsonrpc":"1.0","method":"sendtoaddress","params":["1Address",0.50000000],"id":1}`,
 			unmarshalled: &SendToAddressCmd{
 				Address:   "1Address",
 				Amount:    0.5,
@@ -1030,7 +1256,7 @@ func TestWalletSvrCmds(t *testing.T) {
 				return NewSendToAddressCmd("1Address", 0.5, String("comment"),
 					String("commentto"))
 			},
-			marshalled: `{"jsonrpc":"1.0","method":"sendtoaddress","params":["1Address",0.5,"comment","commentto"],"id":1}`,
+			marshalled: `{"jsonrpc":"1.0","method":"sendtoaddress","params":["1Address",0.50000000,"comment","commentto"],"id":1}`,
 			unmarshalled: &SendToAddressCmd{
 				Address:   "1Address",
 				Amount:    0.5,
@@ -1038,6 +1264,54 @@ func TestWalletSvrCmds(t *testing.T) {
 				CommentTo: String("commentto"),
 			},
 		},
+		{
+			name: "sendtoaddress optional2",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("sendtoaddress", "1Address", 0.5, "comment",
+					"commentto", true, true, 6, "ECONOMICAL", true)
+			},
+			staticCmd: func() interface{} {
+				cmd := NewSendToAddressCmd("1Address", 0.5, String("comment"),
+					String("commentto"))
+				cmd.SubtractFeeFromAmount = Bool(true)
+				cmd.Replaceable = Bool(true)
+				cmd.ConfTarget = Int(6)
+				cmd.EstimateMode = String("ECONOMICAL")
+				cmd.AvoidReuse = Bool(true)
+				return cmd
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"sendtoaddress","params":["1Address",0.50000000,"comment","commentto",true,true,6,"ECONOMICAL",true],"id":1}`,
+			unmarshalled: &SendToAddressCmd{
+				Address:               "1Address",
+				Amount:                0.5,
+				Comment:               String("comment"),
+				CommentTo:             String("commentto"),
+				SubtractFeeFromAmount: Bool(true),
+				Replaceable:           Bool(true),
+				ConfTarget:            Int(6),
+				EstimateMode:          String("ECONOMICAL"),
+				AvoidReuse:            Bool(true),
+			},
+		},
+		{
+			name: "sendtoaddress avoidreuse only",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("sendtoaddress", "1Address", 0.5,
+					(*string)(nil), (*string)(nil), (*bool)(nil),
+					(*bool)(nil), (*int)(nil), (*string)(nil), false)
+			},
+			staticCmd: func() interface{} {
+				cmd := NewSendToAddressCmd("1Address", 0.5, nil, nil)
+				cmd.AvoidReuse = Bool(false)
+				return cmd
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"sendtoaddress","params":["1Address",0.50000000,null,null,null,null,null,null,false],"id":1}`,
+			unmarshalled: &SendToAddressCmd{
+				Address:    "1Address",
+				Amount:     0.5,
+				AvoidReuse: Bool(false),
+			},
+		},
 		{
 			name: "setaccount",
 			newCmd: func() (interface{}, error) {
@@ -1052,6 +1326,34 @@ func TestWalletSvrCmds(t *testing.T) {
 				Account: "acct",
 			},
 		},
+		{
+			name: "sethdseed",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("sethdseed")
+			},
+			staticCmd: func() interface{} {
+				return NewSetHdSeedCmd(nil, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"sethdseed","params":[],"id":1}`,
+			unmarshalled: &SetHdSeedCmd{
+				NewKeyPool: Bool(true),
+				Seed:       nil,
+			},
+		},
+		{
+			name: "sethdseed optional",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("sethdseed", false, "cVWIF")
+			},
+			staticCmd: func() interface{} {
+				return NewSetHdSeedCmd(Bool(false), String("cVWIF"))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"sethdseed","params":[false,"cVWIF"],"id":1}`,
+			unmarshalled: &SetHdSeedCmd{
+				NewKeyPool: Bool(false),
+				Seed:       String("cVWIF"),
+			},
+		},
 		{
 			name: "settxfee",
 			newCmd: func() (interface{}, error) {
@@ -1060,7 +1362,7 @@ func TestWalletSvrCmds(t *testing.T) {
 			staticCmd: func() interface{} {
 				return NewSetTxFeeCmd(0.0001)
 			},
-			marshalled: `{"jsonrpc":"1.0","method":"settxfee","params":[0.0001],"id":1}`,
+			marshalled: `{"jsonrpc":"1.0","method":"settxfee","params":[0.00010000],"id":1}`,
 			unmarshalled: &SetTxFeeCmd{
 				Amount: 0.0001,
 			},
@@ -1164,6 +1466,103 @@ func TestWalletSvrCmds(t *testing.T) {
 				Flags:    String("ALL"),
 			},
 		},
+		{
+			name: "signrawtransactionwithwallet",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("signrawtransactionwithwallet", "001122")
+			},
+			staticCmd: func() interface{} {
+				return NewSignRawTransactionWithWalletCmd("001122", nil, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"signrawtransactionwithwallet","params":["001122"],"id":1}`,
+			unmarshalled: &SignRawTransactionWithWalletCmd{
+				RawTx:       "001122",
+				PrevTxs:     nil,
+				SigHashType: String("ALL"),
+			},
+		},
+		{
+			name: "signrawtransactionwithwallet optional",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("signrawtransactionwithwallet", "001122",
+					`[{"txid":"123","vout":1,"scriptPubKey":"00","amount":0.5}]`,
+					"ALL|ANYONECANPAY")
+			},
+			staticCmd: func() interface{} {
+				prevTxs := []RawTxWitnessInput{
+					{
+						Txid:         "123",
+						Vout:         1,
+						ScriptPubKey: "00",
+						Amount:       Float64(0.5),
+					},
+				}
+				return NewSignRawTransactionWithWalletCmd("001122", &prevTxs,
+					String("ALL|ANYONECANPAY"))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"signrawtransactionwithwallet","params":["001122",[{"txid":"123","vout":1,"scriptPubKey":"00","amount":0.5}],"ALL|ANYONECANPAY"],"id":1}`,
+			unmarshalled: &SignRawTransactionWithWalletCmd{
+				RawTx: "001122",
+				PrevTxs: &[]RawTxWitnessInput{
+					{
+						Txid:         "123",
+						Vout:         1,
+						ScriptPubKey: "00",
+						Amount:       Float64(0.5),
+					},
+				},
+				SigHashType: String("ALL|ANYONECANPAY"),
+			},
+		},
+		{
+			name: "walletcreatefundedpsbt",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("walletcreatefundedpsbt", `[{"txid":"123","vout":1}]`, `{"456":0.0123}`)
+			},
+			staticCmd: func() interface{} {
+				txInputs := []TransactionInput{
+					{Txid: "123", Vout: 1},
+				}
+				outputs := map[string]float64{"456": .0123}
+				return NewWalletCreateFundedPsbtCmd(txInputs, outputs, nil, nil, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"walletcreatefundedpsbt","params":[[{"txid":"123","vout":1}],{"456":0.01230000}],"id":1}`,
+			unmarshalled: &WalletCreateFundedPsbtCmd{
+				Inputs:  []TransactionInput{{Txid: "123", Vout: 1}},
+				Outputs: map[string]Amount{"456": .0123},
+			},
+		},
+		{
+			name: "walletcreatefundedpsbt optional",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("walletcreatefundedpsbt", `[]`, `{"456":0.0123}`, 12312333,
+					`{"changeAddress":"789","feeRate":0.0002,"subtractFeeFromOutputs":[0],"replaceable":true}`, false)
+			},
+			staticCmd: func() interface{} {
+				outputs := map[string]float64{"456": .0123}
+				options := &WalletCreateFundedPsbtOpts{
+					ChangeAddress:          String("789"),
+					FeeRate:                Float64(0.0002),
+					SubtractFeeFromOutputs: []int64{0},
+					Replaceable:            Bool(true),
+				}
+				return NewWalletCreateFundedPsbtCmd([]TransactionInput{}, outputs,
+					Uint32(12312333), options, Bool(false))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"walletcreatefundedpsbt","params":[[],{"456":0.01230000},12312333,{"changeAddress":"789","feeRate":0.0002,"subtractFeeFromOutputs":[0],"replaceable":true},false],"id":1}`,
+			unmarshalled: &WalletCreateFundedPsbtCmd{
+				Inputs:   []TransactionInput{},
+				Outputs:  map[string]Amount{"456": .0123},
+				LockTime: Uint32(12312333),
+				Options: &WalletCreateFundedPsbtOpts{
+					ChangeAddress:          String("789"),
+					FeeRate:                Float64(0.0002),
+					SubtractFeeFromOutputs: []int64{0},
+					Replaceable:            Bool(true),
+				},
+				Bip32Derivs: Bool(false),
+			},
+		},
 		{
 			name: "walletlock",
 			newCmd: func() (interface{}, error) {
@@ -1203,6 +1602,38 @@ func TestWalletSvrCmds(t *testing.T) {
 				NewPassphrase: "new",
 			},
 		},
+		{
+			name: "walletprocesspsbt",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("walletprocesspsbt", "cHNidP8=")
+			},
+			staticCmd: func() interface{} {
+				return NewWalletProcessPsbtCmd("cHNidP8=", nil, nil, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"walletprocesspsbt","params":["cHNidP8="],"id":1}`,
+			unmarshalled: &WalletProcessPsbtCmd{
+				Psbt:        "cHNidP8=",
+				Sign:        Bool(true),
+				SighashType: String("ALL"),
+			},
+		},
+		{
+			name: "walletprocesspsbt optional",
+			newCmd: func() (interface{}, error) {
+				return NewCmd("walletprocesspsbt", "cHNidP8=", false, "NONE", true)
+			},
+			staticCmd: func() interface{} {
+				return NewWalletProcessPsbtCmd("cHNidP8=", Bool(false),
+					String("NONE"), Bool(true))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"walletprocesspsbt","params":["cHNidP8=",false,"NONE",true],"id":1}`,
+			unmarshalled: &WalletProcessPsbtCmd{
+				Psbt:        "cHNidP8=",
+				Sign:        Bool(false),
+				SighashType: String("NONE"),
+				Bip32Derivs: Bool(true),
+			},
+		},
 	}
 
 	t.Logf("Running %d tests", len(tests))
@@ -1271,3 +1702,97 @@ func TestWalletSvrCmds(t *testing.T) {
 		}
 	}
 }
+
+// TestWalletSvrCmdErrors ensures any errors that occur in the command during
+// custom marshal and unmarshal are as expected.
+func TestWalletSvrCmdErrors(t *testing.T) {
+	t.Parallel()
+
+	unmarshalTests := []struct {
+		name       string
+		result     interface{}
+		marshalled string
+	}{
+		{
+			name:       "importmulti timestamp other than now",
+			result:     &TimestampOrNow{},
+			marshalled: `"later"`,
+		},
+		{
+			name:       "importmulti negative timestamp",
+			result:     &TimestampOrNow{},
+			marshalled: `-1`,
+		},
+		{
+			name:       "importmulti fractional timestamp",
+			result:     &TimestampOrNow{},
+			marshalled: `1.5`,
+		},
+		{
+			name:       "importmulti script which is not hex",
+			result:     &ImportMultiScriptPubKey{},
+			marshalled: `"zz"`,
+		},
+		{
+			name:       "importmulti object without address",
+			result:     &ImportMultiScriptPubKey{},
+			marshalled: `{}`,
+		},
+		{
+			name:       "importmulti script of invalid type",
+			result:     &ImportMultiScriptPubKey{},
+			marshalled: `5`,
+		},
+	}
+
+	t.Logf("Running %d unmarshal tests", len(unmarshalTests))
+	for i, test := range unmarshalTests {
+		err := json.Unmarshal([]byte(test.marshalled), test.result)
+		if jerr, ok := err.(Error); !ok || jerr.ErrorCode != ErrInvalidType {
+			t.Errorf("Unmarshal test #%d (%s) wrong error - got %T "+
+				"(%[2]v), want ErrInvalidType", i, test.name, err)
+		}
+	}
+
+	marshalTests := []struct {
+		name string
+		req  ImportMultiRequest
+	}{
+		{
+			name: "importmulti script and address",
+			req: ImportMultiRequest{
+				ScriptPubKey: ImportMultiScriptPubKey{
+					Script:  "a914",
+					Address: "1Address",
+				},
+			},
+		},
+		{
+			name: "importmulti without script",
+			req:  ImportMultiRequest{},
+		},
+		{
+			name: "importmulti script which is not hex",
+			req: ImportMultiRequest{
+				ScriptPubKey: ImportMultiScriptPubKey{Script: "zz"},
+			},
+		},
+		{
+			name: "importmulti negative timestamp",
+			req: ImportMultiRequest{
+				ScriptPubKey: ImportMultiScriptPubKey{Script: "a914"},
+				Timestamp:    TimestampOrNow{Value: -1},
+			},
+		},
+	}
+
+	t.Logf("Running %d marshal tests", len(marshalTests))
+	for i, test := range marshalTests {
+		_, err := json.Marshal(test.req)
+		var jerr Error
+		if !errors.As(err, &jerr) || jerr.ErrorCode != ErrInvalidType {
+			t.Errorf("Marshal test #%d (%s) wrong error - got %T "+
+				"(%[2]v), want ErrInvalidType", i, test.name, err)
+		}
+	}
+}
@@ -20,6 +20,29 @@ type GetTransactionDetailsResult struct {
 	Vout              uint32   `json:"vout"`
 }
 
+// GetAddressesByLabelResult models the data of each address returned by the
+// getaddressesbylabel command, which is keyed by address.
+type GetAddressesByLabelResult struct {
+	Purpose string `json:"purpose"`
+}
+
+// GetBalancesResultEntry models the balances of one ownership category of the
+// getbalances command.  The used balance is only present for wallets with the
+// avoid_reuse flag set.
+type GetBalancesResultEntry struct {
+	Trusted          float64  `json:"trusted"`
+	UntrustedPending float64  `json:"untrusted_pending"`
+	Immature         float64  `json:"immature"`
+	Used             *float64 `json:"used,omitempty"`
+}
+
+// GetBalancesResult models the data from the getbalances command.  The watch
+// only balances are only present for wallets with watch only addresses.
+type GetBalancesResult struct {
+	Mine      GetBalancesResultEntry  `json:"mine"`
+	WatchOnly *GetBalancesResultEntry `json:"watchonly,omitempty"`
+}
+
 // GetTransactionResult models the data from the gettransaction command.
 type GetTransactionResult struct {
 	Amount          float64                       `json:"amount"`
@@ -101,10 +124,48 @@ type ListReceivedByAddressResult struct {
 	InvolvesWatchonly bool     `json:"involvesWatchonly,omitempty"`
 }
 
+// ImportMultiResult models the data of each request returned by the
+// importmulti and importdescriptors commands.
+type ImportMultiResult struct {
+	Success  bool      `json:"success"`
+	Warnings []string  `json:"warnings,omitempty"`
+	Error    *RPCError `json:"error,omitempty"`
+}
+
+// ListDescriptorsResultDescriptor models a descriptor entry of the
+// listdescriptors command.  The range and next fields are only present for
+// ranged descriptors.
+type ListDescriptorsResultDescriptor struct {
+	Desc      string  `json:"desc"`
+	Timestamp int64   `json:"timestamp"`
+	Active    bool    `json:"active"`
+	Internal  *bool   `json:"internal,omitempty"`
+	Range     *[2]int `json:"range,omitempty"`
+	Next      *int    `json:"next,omitempty"`
+}
+
+// ListDescriptorsResult models the data from the listdescriptors command.
+type ListDescriptorsResult struct {
+	WalletName  string                            `json:"wallet_name"`
+	Descriptors []ListDescriptorsResultDescriptor `json:"descriptors"`
+}
+
+// ListReceivedByLabelResult models the data from the listreceivedbylabel
+// command.
+type ListReceivedByLabelResult struct {
+	Label             string  `json:"label"`
+	Amount            float64 `json:"amount"`
+	Confirmations     uint64  `json:"confirmations"`
+	InvolvesWatchonly bool    `json:"involvesWatchonly,omitempty"`
+}
+
 // ListSinceBlockResult models the data from the listsinceblock command.
 type ListSinceBlockResult struct {
 	Transactions []ListTransactionsResult `json:"transactions"`
 	LastBlock    string                   `json:"lastblock"`
+
+	// Removed is only reported by Bitcoin Core 0.16 and later.
+	Removed []ListTransactionsResult `json:"removed,omitempty"`
 }
 
 // ListUnspentResult models a successful response from the listunspent request.
@@ -138,6 +199,31 @@ type SignRawTransactionResult struct {
 	Errors   []SignRawTransactionError `json:"errors,omitempty"`
 }
 
+// SignRawTransactionWithKeyResult models the data from the
+// signrawtransactionwithkey command, which is the same as that of the
+// signrawtransaction command.
+type SignRawTransactionWithKeyResult = SignRawTransactionResult
+
+// SignRawTransactionWithWalletResult models the data from the
+// signrawtransactionwithwallet command, which is the same as that of the
+// signrawtransaction command.
+type SignRawTransactionWithWalletResult = SignRawTransactionResult
+
+// WalletCreateFundedPsbtResult models the data from the
+// walletcreatefundedpsbt command.  The fee is in BTC and the change position is
+// -1 when no change output was added.
+type WalletCreateFundedPsbtResult struct {
+	Psbt      string  `json:"psbt"`
+	Fee       float64 `json:"fee"`
+	ChangePos int64   `json:"changepos"`
+}
+
+// WalletProcessPsbtResult models the data from the walletprocesspsbt command.
+type WalletProcessPsbtResult struct {
+	Psbt     string `json:"psbt"`
+	Complete bool   `json:"complete"`
+}
+
 // ValidateAddressWalletResult models the data returned by the wallet server
 // validateaddress command.
 type ValidateAddressWalletResult struct {
@@ -153,6 +239,13 @@ type ValidateAddressWalletResult struct {
 	Hex          string   `json:"hex,omitempty"`
 	Script       string   `json:"script,omitempty"`
 	SigsRequired int32    `json:"sigsrequired,omitempty"`
+
+	// The following fields are returned by servers based on Bitcoin Core.
+	ScriptPubKey   string `json:"scriptPubKey,omitempty"`
+	IsWitness      bool   `json:"iswitness,omitempty"`
+	WitnessVersion *int32 `json:"witness_version,omitempty"`
+	WitnessProgram string `json:"witness_program,omitempty"`
+	Error          string `json:"error,omitempty"`
 }
 
 // GetBestBlockResult models the data from the getbestblock command.
@@ -163,7 +256,7 @@ type GetBestBlockResult struct {
 
 // EstimateSmartFeeResult models the data returned from the estimatesmartfee command.
 type EstimateSmartFeeResult struct {
-		FeeRate *float64  `json:"feerate,omitempty"`
-		Errors  *[]string `json:"errors,omitempty"`
-		Blocks  int       `json:"blocks"`
+		FeeRate *float64 `json:"feerate,omitempty"`
+		Errors  []string `json:"errors,omitempty"`
+		Blocks  int64    `json:"blocks"`
 	}
\ No newline at end of file
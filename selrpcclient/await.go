@@ -0,0 +1,55 @@
+// Copyright (c) 2018 The box developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+//go:build go1.18
+// +build go1.18
+
+package selrpcclient
+
+import (
+	"sync"
+)
+
+// Receiver is implemented by every future returned by the Async methods of the
+// client.  T is the type of the result delivered by the future.
+type Receiver[T any] interface {
+	Receive() (T, error)
+}
+
+// Await waits for the response promised by the passed future and returns its
+// result.  It is equivalent to calling Receive on the future, but allows code
+// which is generic over the result type to wait on any future.
+//
+// Go 1.21 and later infer T from the future, as in:
+//
+//	count, err := Await(client.GetBlockCountAsync())
+//
+// while older versions need it to be given explicitly.
+func Await[T any](future Receiver[T]) (T, error) {
+	return future.Receive()
+}
+
+// Gather waits for the responses promised by all of the passed futures
+// concurrently and returns their results and errors.  Both slices have one entry
+// per future, in the same order, so the result at an index is only meaningful
+// when the error at that index is nil.
+//
+// Like Receive, each future may only be waited on once.  As with Await, Go
+// versions before 1.21 need T to be given explicitly.
+func Gather[T any, F Receiver[T]](futures []F) ([]T, []error) {
+	results := make([]T, len(futures))
+	errs := make([]error, len(futures))
+
+	var wg sync.WaitGroup
+	wg.Add(len(futures))
+	for i := range futures {
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = futures[i].Receive()
+		}(i)
+	}
+	wg.Wait()
+
+	return results, errs
+}
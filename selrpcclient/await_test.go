@@ -0,0 +1,69 @@
+// Copyright (c) 2018 The box developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+//go:build go1.18
+// +build go1.18
+
+package selrpcclient
+
+import (
+	"encoding/json"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/zzpu/lib-bitcore/sebtcjson"
+	"testing"
+)
+
+// TestAwaitGather ensures Await and Gather deliver the results and errors of
+// the passed futures in order.
+func TestAwaitGather(t *testing.T) {
+	t.Parallel()
+
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		switch method {
+		case "getblockcount":
+			return 42, nil
+		case "getblockhash":
+			var height int64
+			json.Unmarshal(params[0], &height)
+			if height == 2 {
+				return nil, sebtcjson.NewRPCError(sebtcjson.ErrRPCOutOfRange,
+					"Block height out of range")
+			}
+			return chainhash.Hash{byte(height)}.String(), nil
+		}
+		return nil, sebtcjson.ErrRPCMethodNotFound
+	})
+	defer closeTestClient(client, server)
+
+	count, err := Await[int64](client.GetBlockCountAsync())
+	if err != nil || count != 42 {
+		t.Errorf("Await: unexpected result - got %d, %v, want 42", count,
+			err)
+	}
+
+	futures := make([]FutureGetBlockHashResult, 4)
+	for i := range futures {
+		futures[i] = client.GetBlockHashAsync(int64(i))
+	}
+	hashes, errs := Gather[*chainhash.Hash](futures)
+	if len(hashes) != len(futures) || len(errs) != len(futures) {
+		t.Fatalf("Gather: got %d results and %d errors, want %d",
+			len(hashes), len(errs), len(futures))
+	}
+	for i := range futures {
+		if i == 2 {
+			if errs[i] == nil {
+				t.Errorf("Test #%d expected an error", i)
+			}
+			continue
+		}
+		if errs[i] != nil {
+			t.Errorf("Test #%d unexpected error: %v", i, errs[i])
+			continue
+		}
+		if *hashes[i] != (chainhash.Hash{byte(i)}) {
+			t.Errorf("Test #%d unexpected hash - got %v", i, hashes[i])
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2018 The box developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package selrpcclient
+
+import (
+	"container/list"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultBlockCacheSize is the number of verbose blocks kept by the
+	// client when ConnConfig.BlockCacheSize is not set.
+	defaultBlockCacheSize = 100
+)
+
+// blockCacheEntry houses a cached value along with the key it is stored under
+// and the time it was added to the cache.
+type blockCacheEntry struct {
+	hash    chainhash.Hash
+	value   interface{}
+	addedAt time.Time
+}
+
+// blockCache is a size bounded least recently used cache keyed by block hash.
+// Block contents never change for a given hash, so entries are only evicted
+// once the cache is full or when a caller supplied ttl has elapsed.
+//
+// The cache is safe for concurrent access.
+type blockCache struct {
+	mtx     sync.Mutex
+	limit   int
+	entries map[chainhash.Hash]*list.Element
+	lru     *list.List
+}
+
+// newBlockCache returns a new block cache that holds at most limit entries.
+// A limit of zero or less selects defaultBlockCacheSize.
+func newBlockCache(limit int) *blockCache {
+	if limit <= 0 {
+		limit = defaultBlockCacheSize
+	}
+	return &blockCache{
+		limit:   limit,
+		entries: make(map[chainhash.Hash]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// Get returns the value stored for the passed hash and whether or not it was
+// found.  Entries older than ttl are treated as missing and removed.  A ttl of
+// zero means entries never expire.
+func (c *blockCache) Get(hash *chainhash.Hash, ttl time.Duration) (interface{}, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	elem, ok := c.entries[*hash]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*blockCacheEntry)
+	if ttl > 0 && time.Since(entry.addedAt) > ttl {
+		c.lru.Remove(elem)
+		delete(c.entries, *hash)
+		return nil, false
+	}
+	c.lru.MoveToFront(elem)
+	return entry.value, true
+}
+
+// Add stores the passed value under the passed hash, evicting the least
+// recently used entry when the cache is full.
+func (c *blockCache) Add(hash *chainhash.Hash, value interface{}) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if elem, ok := c.entries[*hash]; ok {
+		entry := elem.Value.(*blockCacheEntry)
+		entry.value = value
+		entry.addedAt = time.Now()
+		c.lru.MoveToFront(elem)
+		return
+	}
+
+	if c.lru.Len() >= c.limit {
+		oldest := c.lru.Back()
+		delete(c.entries, oldest.Value.(*blockCacheEntry).hash)
+		c.lru.Remove(oldest)
+	}
+
+	entry := &blockCacheEntry{hash: *hash, value: value, addedAt: time.Now()}
+	c.entries[*hash] = c.lru.PushFront(entry)
+}
+
+// Remove deletes the entry stored for the passed hash, if any.
+func (c *blockCache) Remove(hash *chainhash.Hash) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if elem, ok := c.entries[*hash]; ok {
+		c.lru.Remove(elem)
+		delete(c.entries, *hash)
+	}
+}
+
+// Len returns the number of entries currently held by the cache.
+func (c *blockCache) Len() int {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.lru.Len()
+}
@@ -0,0 +1,277 @@
+// Copyright (c) 2018 The box developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package selrpcclient
+
+import (
+	"errors"
+	"github.com/ltcsuite/ltcutil"
+	"github.com/zzpu/lib-bitcore/sebtcjson"
+	"sort"
+	"strings"
+)
+
+// ErrInsufficientFunds is returned by SelectCoins when the spendable outputs
+// do not cover the requested target.
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+// CoinSelectStrategy identifies the algorithm used by SelectCoins to choose
+// which unspent outputs to spend.
+type CoinSelectStrategy int
+
+const (
+	// CoinSelectLargestFirst selects the largest outputs first until the
+	// target is covered.  It minimizes the number of inputs.
+	CoinSelectLargestFirst CoinSelectStrategy = iota
+
+	// CoinSelectBranchAndBound searches for the set of outputs whose total
+	// exceeds the target by the smallest amount, ideally matching it
+	// exactly so no change output is needed.  The search is bounded by
+	// maxBranchAndBoundTries, after which the best set found so far is
+	// used.
+	CoinSelectBranchAndBound
+)
+
+// The estimated virtual sizes, in bytes, of inputs spending the standard single
+// signature script types.  Taproot inputs are rounded up from 57.5 bytes.
+const (
+	p2pkhInputVSize      = 148
+	p2shP2wpkhInputVSize = 91
+	p2wpkhInputVSize     = 68
+	p2trInputVSize       = 58
+)
+
+// maxBranchAndBoundTries is the maximum number of nodes visited by the branch
+// and bound search before it settles for the best selection found so far.
+const maxBranchAndBoundTries = 100000
+
+// String returns the CoinSelectStrategy in human-readable form.
+func (s CoinSelectStrategy) String() string {
+	switch s {
+	case CoinSelectLargestFirst:
+		return "largest-first"
+	case CoinSelectBranchAndBound:
+		return "branch-and-bound"
+	}
+	return "unknown"
+}
+
+// coinSelectCandidate houses an unspent output along with its amount.
+type coinSelectCandidate struct {
+	utxo   sebtcjson.ListUnspentResult
+	amount ltcutil.Amount
+}
+
+// SelectCoins chooses which of the passed unspent outputs, as returned by
+// ListUnspent, to spend in order to cover the target amount using the passed
+// strategy.  Outputs which are not spendable are ignored.  The target should
+// include the estimated fee of the transaction being funded.
+//
+// The selected outputs are returned along with their total.
+// ErrInsufficientFunds is returned when the spendable outputs can not cover
+// the target.
+//
+// See SelectCoinsWithFee to account for the fee of each selected input.
+func SelectCoins(utxos []sebtcjson.ListUnspentResult, target ltcutil.Amount,
+	strategy CoinSelectStrategy) ([]sebtcjson.ListUnspentResult, ltcutil.Amount, error) {
+
+	candidates := make([]coinSelectCandidate, 0, len(utxos))
+	for _, utxo := range utxos {
+		if !utxo.Spendable {
+			continue
+		}
+		amount, err := ltcutil.NewAmount(utxo.Amount)
+		if err != nil {
+			return nil, 0, err
+		}
+		candidates = append(candidates, coinSelectCandidate{utxo, amount})
+	}
+
+	selected, err := selectCandidates(candidates, target, strategy)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]sebtcjson.ListUnspentResult, 0, len(selected))
+	var total ltcutil.Amount
+	for _, c := range selected {
+		result = append(result, c.utxo)
+		total += c.amount
+	}
+	return result, total, nil
+}
+
+// SelectCoinsWithFee is like SelectCoins, but also pays for the inputs it
+// selects.  Each output only contributes its amount less the fee of spending
+// it at the passed fee rate, in satoshi per virtual byte, so outputs which cost
+// more to spend than they are worth are never selected.  The target should
+// include the fee of the rest of the transaction, such as its outputs.
+//
+// The selected outputs are returned along with the change left over once the
+// target and the fee of the inputs are paid.  The size of each input is
+// estimated from the type of the script of the output, assuming outputs paying
+// to a script hash wrap a pay-to-witness-pubkey-hash script.
+func SelectCoinsWithFee(utxos []sebtcjson.ListUnspentResult, target ltcutil.Amount,
+	feeRate int64, strategy CoinSelectStrategy) ([]sebtcjson.ListUnspentResult, ltcutil.Amount, error) {
+
+	if feeRate < 0 {
+		return nil, 0, errors.New("fee rate must not be negative")
+	}
+
+	candidates := make([]coinSelectCandidate, 0, len(utxos))
+	for _, utxo := range utxos {
+		if !utxo.Spendable {
+			continue
+		}
+		amount, err := ltcutil.NewAmount(utxo.Amount)
+		if err != nil {
+			return nil, 0, err
+		}
+		fee := ltcutil.Amount(estimateInputVSize(utxo.ScriptPubKey) * feeRate)
+		candidates = append(candidates, coinSelectCandidate{utxo, amount - fee})
+	}
+
+	selected, err := selectCandidates(candidates, target, strategy)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]sebtcjson.ListUnspentResult, 0, len(selected))
+	var total ltcutil.Amount
+	for _, c := range selected {
+		result = append(result, c.utxo)
+		total += c.amount
+	}
+	return result, total - target, nil
+}
+
+// selectCandidates chooses which of the passed candidates to spend in order to
+// cover the target using the passed strategy.  Candidates which do not have a
+// positive amount are ignored.
+func selectCandidates(candidates []coinSelectCandidate, target ltcutil.Amount,
+	strategy CoinSelectStrategy) ([]coinSelectCandidate, error) {
+
+	if target <= 0 {
+		return nil, errors.New("target amount must be positive")
+	}
+
+	usable := candidates[:0]
+	var available ltcutil.Amount
+	for _, c := range candidates {
+		if c.amount <= 0 {
+			continue
+		}
+		usable = append(usable, c)
+		available += c.amount
+	}
+	if available < target {
+		return nil, ErrInsufficientFunds
+	}
+
+	// Both strategies consider the largest outputs first.
+	sort.SliceStable(usable, func(i, j int) bool {
+		return usable[i].amount > usable[j].amount
+	})
+
+	switch strategy {
+	case CoinSelectLargestFirst:
+		return selectLargestFirst(usable, target), nil
+	case CoinSelectBranchAndBound:
+		return selectBranchAndBound(usable, target), nil
+	}
+	return nil, errors.New("unknown coin selection strategy " +
+		strategy.String())
+}
+
+// estimateInputVSize returns the estimated virtual size, in bytes, of an input
+// spending an output with the passed hex-encoded script.  Scripts of unknown
+// types are assumed to be spent like pay-to-pubkey-hash scripts, which are the
+// largest of the standard single signature types.
+func estimateInputVSize(scriptPubKey string) int64 {
+	switch {
+	// OP_0 <20 byte hash>
+	case len(scriptPubKey) == 44 && strings.HasPrefix(scriptPubKey, "0014"):
+		return p2wpkhInputVSize
+
+	// OP_1 <32 byte key>
+	case len(scriptPubKey) == 68 && strings.HasPrefix(scriptPubKey, "5120"):
+		return p2trInputVSize
+
+	// OP_HASH160 <20 byte hash> OP_EQUAL
+	case len(scriptPubKey) == 46 && strings.HasPrefix(scriptPubKey, "a914") &&
+		strings.HasSuffix(scriptPubKey, "87"):
+		return p2shP2wpkhInputVSize
+	}
+	return p2pkhInputVSize
+}
+
+// selectLargestFirst returns the shortest prefix of the passed candidates,
+// which must be sorted by descending amount, that covers the target.
+func selectLargestFirst(candidates []coinSelectCandidate, target ltcutil.Amount) []coinSelectCandidate {
+	var total ltcutil.Amount
+	for i, c := range candidates {
+		total += c.amount
+		if total >= target {
+			return candidates[:i+1]
+		}
+	}
+	return candidates
+}
+
+// selectBranchAndBound performs a depth-first search over the passed
+// candidates, which must be sorted by descending amount, for the selection
+// which covers the target with the least excess.  The candidates must cover the
+// target.
+func selectBranchAndBound(candidates []coinSelectCandidate, target ltcutil.Amount) []coinSelectCandidate {
+	// Start from the largest-first selection so there is always a
+	// solution to improve upon.
+	best := selectLargestFirst(candidates, target)
+	var bestTotal ltcutil.Amount
+	for _, c := range best {
+		bestTotal += c.amount
+	}
+
+	// remaining[i] is the total of the candidates from index i onwards.
+	remaining := make([]ltcutil.Amount, len(candidates)+1)
+	for i := len(candidates) - 1; i >= 0; i-- {
+		remaining[i] = remaining[i+1] + candidates[i].amount
+	}
+
+	included := make([]bool, len(candidates))
+	tries := 0
+	var search func(i int, total ltcutil.Amount)
+	search = func(i int, total ltcutil.Amount) {
+		tries++
+		if tries > maxBranchAndBoundTries || bestTotal == target {
+			return
+		}
+
+		// Bound the search by selections which can no longer improve
+		// on the best one or can no longer reach the target.
+		if total >= bestTotal || total+remaining[i] < target {
+			return
+		}
+		if total >= target {
+			bestTotal = total
+			best = nil
+			for j, inc := range included[:i] {
+				if inc {
+					best = append(best, candidates[j])
+				}
+			}
+			return
+		}
+		if i == len(candidates) {
+			return
+		}
+
+		included[i] = true
+		search(i+1, total+candidates[i].amount)
+		included[i] = false
+		search(i+1, total)
+	}
+	search(0, 0)
+
+	return best
+}
@@ -0,0 +1,255 @@
+// Copyright (c) 2018 The box developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package selrpcclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/zzpu/lib-bitcore/sebtcjson"
+	"reflect"
+	"testing"
+)
+
+// TestDescriptorChecksum ensures descriptor checksums are computed as specified
+// by BIP 380 and that existing checksums are validated.
+func TestDescriptorChecksum(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		desc string
+		want string
+		err  bool
+	}{
+		{
+			name: "raw",
+			desc: "raw(deadbeef)",
+			want: "raw(deadbeef)#89f8spxm",
+		},
+		{
+			name: "addr",
+			desc: "addr(mkmZxiEcEd8ZqjQWVZuC6so5dFMKEFpN2j)",
+			want: "addr(mkmZxiEcEd8ZqjQWVZuC6so5dFMKEFpN2j)#02wpgw69",
+		},
+		{
+			name: "ranged with origin",
+			desc: "pkh([d34db33f/44'/0'/0']xpub6ERApfZwUNrhLCkDtcHTcxd75RbzS1ed54G1LkBUHQVHQKqhMkhgbmJbZRkrgZw4koxb5JaHWkY4ALHY2grBGRjaDMzQLcgJvLJuZZvRcEL/1/*)",
+			want: "pkh([d34db33f/44'/0'/0']xpub6ERApfZwUNrhLCkDtcHTcxd75RbzS1ed54G1LkBUHQVHQKqhMkhgbmJbZRkrgZw4koxb5JaHWkY4ALHY2grBGRjaDMzQLcgJvLJuZZvRcEL/1/*)#ml40v0wf",
+		},
+		{
+			name: "valid checksum",
+			desc: "raw(deadbeef)#89f8spxm",
+			want: "raw(deadbeef)#89f8spxm",
+		},
+		{
+			name: "invalid checksum",
+			desc: "raw(deadbeef)#89f8spxn",
+			err:  true,
+		},
+		{
+			name: "empty checksum",
+			desc: "raw(deadbeef)#",
+			err:  true,
+		},
+		{
+			name: "invalid character",
+			desc: "raw(deadbeef)\n",
+			err:  true,
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		got, err := AddDescriptorChecksum(test.desc)
+		if (err != nil) != test.err {
+			t.Errorf("Test #%d (%s) unexpected error: %v", i,
+				test.name, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("Test #%d (%s) unexpected descriptor - got %s, "+
+				"want %s", i, test.name, got, test.want)
+		}
+	}
+}
+
+// TestImportWatchDescriptors ensures the importdescriptors requests built for
+// watch-only descriptors carry checksums and the timestamp matching the rescan
+// flag, and that invalid descriptors are rejected before any request is sent.
+func TestImportWatchDescriptors(t *testing.T) {
+	t.Parallel()
+
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		if method != "importdescriptors" {
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		}
+		var requests []sebtcjson.ImportDescriptorsRequest
+		json.Unmarshal(params[0], &requests)
+		results := make([]sebtcjson.ImportMultiResult, len(requests))
+		for i := range results {
+			results[i].Success = true
+		}
+		return results, nil
+	})
+	defer closeTestClient(client, server)
+
+	descs := []string{
+		"raw(deadbeef)",
+		"addr(mkmZxiEcEd8ZqjQWVZuC6so5dFMKEFpN2j)#02wpgw69",
+	}
+	tests := []struct {
+		rescan bool
+		params string
+	}{
+		{
+			rescan: false,
+			params: `[{"desc":"raw(deadbeef)#89f8spxm","active":false,"timestamp":"now"},` +
+				`{"desc":"addr(mkmZxiEcEd8ZqjQWVZuC6so5dFMKEFpN2j)#02wpgw69","active":false,"timestamp":"now"}]`,
+		},
+		{
+			rescan: true,
+			params: `[{"desc":"raw(deadbeef)#89f8spxm","active":false,"timestamp":0},` +
+				`{"desc":"addr(mkmZxiEcEd8ZqjQWVZuC6so5dFMKEFpN2j)#02wpgw69","active":false,"timestamp":0}]`,
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		results, err := client.ImportWatchDescriptors(descs, test.rescan)
+		if err != nil {
+			t.Errorf("Test #%d unexpected error: %v", i, err)
+			continue
+		}
+		want := []sebtcjson.ImportMultiResult{{Success: true}, {Success: true}}
+		if !reflect.DeepEqual(results, want) {
+			t.Errorf("Test #%d unexpected results - got %+v", i, results)
+		}
+		params := server.lastRequest("importdescriptors").Params
+		if len(params) != 1 || string(params[0]) != test.params {
+			t.Errorf("Test #%d unexpected params - got %s, want [%s]",
+				i, params, test.params)
+		}
+	}
+
+	_, err := client.ImportWatchDescriptors([]string{"raw(deadbeef)#89f8spxn"},
+		false)
+	if err == nil {
+		t.Errorf("ImportWatchDescriptors: expected error for invalid " +
+			"checksum")
+	}
+	if got := server.calls("importdescriptors"); got != len(tests) {
+		t.Errorf("ImportWatchDescriptors: got %d importdescriptors "+
+			"calls, want %d", got, len(tests))
+	}
+}
+
+// TestGetDescriptorInfo ensures the analysis of a descriptor is requested and
+// decoded.
+func TestGetDescriptorInfo(t *testing.T) {
+	t.Parallel()
+
+	want := &sebtcjson.GetDescriptorInfoResult{
+		Descriptor: "raw(deadbeef)#89f8spxm",
+		Checksum:   "89f8spxm",
+		IsSolvable: true,
+	}
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		if method != "getdescriptorinfo" {
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		}
+		return want, nil
+	})
+	defer closeTestClient(client, server)
+
+	info, err := client.GetDescriptorInfo("raw(deadbeef)")
+	if err != nil {
+		t.Fatalf("GetDescriptorInfo: unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(info, want) {
+		t.Errorf("GetDescriptorInfo: unexpected result - got %+v, want %+v",
+			info, want)
+	}
+	params := marshalParams(t, server.lastRequest("getdescriptorinfo"))
+	if want := `["raw(deadbeef)"]`; params != want {
+		t.Errorf("unexpected params - got %s, want %s", params, want)
+	}
+}
+
+// TestDeriveAddresses ensures the range of a descriptor is marshalled in the
+// form the server expects and that the addresses derived from ranged and
+// non-ranged descriptors are decoded.
+func TestDeriveAddresses(t *testing.T) {
+	t.Parallel()
+
+	// The server derives one address for each index of the range, and a
+	// single one when no range is passed.
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		if method != "deriveaddresses" {
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		}
+		if len(params) < 2 {
+			return []string{"mkmZxiEcEd8ZqjQWVZuC6so5dFMKEFpN2j"}, nil
+		}
+		var rangeVals sebtcjson.DescriptorRange
+		if err := json.Unmarshal(params[1], &rangeVals); err != nil {
+			return nil, sebtcjson.NewRPCError(sebtcjson.ErrRPCInvalidParameter,
+				"invalid range")
+		}
+		var addrs []string
+		for i := rangeVals.Begin; i <= rangeVals.End; i++ {
+			addrs = append(addrs, fmt.Sprintf("addr%d", i))
+		}
+		return addrs, nil
+	})
+	defer closeTestClient(client, server)
+
+	tests := []struct {
+		name      string
+		desc      string
+		rangeVals *sebtcjson.DescriptorRange
+		params    string
+		want      []string
+	}{
+		{
+			name:   "not ranged",
+			desc:   "addr(mkmZxiEcEd8ZqjQWVZuC6so5dFMKEFpN2j)#02wpgw69",
+			params: `["addr(mkmZxiEcEd8ZqjQWVZuC6so5dFMKEFpN2j)#02wpgw69"]`,
+			want:   []string{"mkmZxiEcEd8ZqjQWVZuC6so5dFMKEFpN2j"},
+		},
+		{
+			name:      "end only",
+			desc:      "wpkh(tpub/0/*)",
+			rangeVals: &sebtcjson.DescriptorRange{End: 2},
+			params:    `["wpkh(tpub/0/*)",2]`,
+			want:      []string{"addr0", "addr1", "addr2"},
+		},
+		{
+			name:      "begin and end",
+			desc:      "wpkh(tpub/0/*)",
+			rangeVals: &sebtcjson.DescriptorRange{Begin: 4, End: 5},
+			params:    `["wpkh(tpub/0/*)",[4,5]]`,
+			want:      []string{"addr4", "addr5"},
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		addrs, err := client.DeriveAddresses(test.desc, test.rangeVals)
+		if err != nil {
+			t.Errorf("Test #%d (%s) unexpected error: %v", i,
+				test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(addrs, test.want) {
+			t.Errorf("Test #%d (%s) unexpected addresses - got %v, "+
+				"want %v", i, test.name, addrs, test.want)
+		}
+		params := marshalParams(t, server.lastRequest("deriveaddresses"))
+		if params != test.params {
+			t.Errorf("Test #%d (%s) unexpected params - got %s, "+
+				"want %s", i, test.name, params, test.params)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2018 The box developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package selrpcclient
+
+import (
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"sync"
+)
+
+// MempoolDiff returns the transaction hashes of the current memory pool
+// snapshot which are not part of the previous one, and those of the previous
+// snapshot which are not part of the current one.  Both are returned in the
+// order they appear in their snapshot, and hashes listed more than once are
+// only reported once.
+func MempoolDiff(prev, curr []*chainhash.Hash) (added, removed []*chainhash.Hash) {
+	prevSet := make(map[chainhash.Hash]struct{}, len(prev))
+	for _, txid := range prev {
+		prevSet[*txid] = struct{}{}
+	}
+	currSet := make(map[chainhash.Hash]struct{}, len(curr))
+	for _, txid := range curr {
+		if _, ok := currSet[*txid]; ok {
+			continue
+		}
+		currSet[*txid] = struct{}{}
+		if _, ok := prevSet[*txid]; !ok {
+			added = append(added, txid)
+		}
+	}
+	for _, txid := range prev {
+		if _, ok := currSet[*txid]; ok {
+			continue
+		}
+		if _, ok := prevSet[*txid]; !ok {
+			continue
+		}
+		// Remove the hash so duplicates are only reported once.
+		delete(prevSet, *txid)
+		removed = append(removed, txid)
+	}
+	return added, removed
+}
+
+// MempoolTracker keeps the most recent memory pool snapshot of a server and
+// reports the transactions which were added and removed since the previous
+// snapshot each time it is updated.  Unlike WatchMempool, it does not poll on
+// its own, so the caller decides when snapshots are taken.
+//
+// The tracker is safe for concurrent access.
+type MempoolTracker struct {
+	client *Client
+
+	mtx  sync.Mutex
+	last []*chainhash.Hash
+}
+
+// NewMempoolTracker returns a tracker which takes memory pool snapshots with
+// the passed client.  It starts out with an empty snapshot, so the first
+// update reports every transaction in the memory pool as added.
+func NewMempoolTracker(client *Client) *MempoolTracker {
+	return &MempoolTracker{client: client}
+}
+
+// Update fetches the current memory pool of the server with getrawmempool,
+// replaces the retained snapshot with it and returns the differences to the
+// previous snapshot as computed by MempoolDiff.  The retained snapshot is left
+// in place when the server can not be queried.
+func (t *MempoolTracker) Update() (added, removed []*chainhash.Hash, err error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	txids, err := t.client.GetRawMempool()
+	if err != nil {
+		return nil, nil, err
+	}
+	added, removed = MempoolDiff(t.last, txids)
+	t.last = txids
+	return added, removed, nil
+}
+
+// Snapshot returns the transaction hashes of the memory pool as of the last
+// successful update.
+func (t *MempoolTracker) Snapshot() []*chainhash.Hash {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	snapshot := make([]*chainhash.Hash, len(t.last))
+	copy(snapshot, t.last)
+	return snapshot
+}
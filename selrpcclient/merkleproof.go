@@ -0,0 +1,160 @@
+// Copyright (c) 2018 The box developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package selrpcclient
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// VerifyMerkleProofLocal verifies the passed hex-encoded proof, as returned by
+// gettxoutproof, without trusting the server to do so.  The merkle root is
+// recomputed from the partial merkle tree of the proof and checked against both
+// the header of the proof and the expected merkle root, which should be taken
+// from a block header obtained independently.  The hashes of the transactions
+// the proof matches are returned in the order they appear in the block.
+//
+// Proofs which exploit the duplicate hash malleability of the merkle tree
+// (CVE-2012-2459) are rejected.
+func VerifyMerkleProofLocal(proofHex string, expectedMerkleRoot *chainhash.Hash) ([]*chainhash.Hash, error) {
+	if expectedMerkleRoot == nil {
+		return nil, errors.New("expected merkle root must not be nil")
+	}
+
+	serialized, err := hex.DecodeString(proofHex)
+	if err != nil {
+		return nil, err
+	}
+	var proof wire.MsgMerkleBlock
+	err = proof.BtcDecode(bytes.NewReader(serialized), wire.ProtocolVersion,
+		wire.BaseEncoding)
+	if err != nil {
+		return nil, err
+	}
+
+	root, matches, err := extractMerkleMatches(&proof)
+	if err != nil {
+		return nil, err
+	}
+	if root != proof.Header.MerkleRoot {
+		return nil, fmt.Errorf("merkle root %v of proof does not match "+
+			"root %v of its header", root, proof.Header.MerkleRoot)
+	}
+	if root != *expectedMerkleRoot {
+		return nil, fmt.Errorf("merkle root %v of proof does not match "+
+			"expected root %v", root, expectedMerkleRoot)
+	}
+	return matches, nil
+}
+
+// partialMerkleTree walks the partial merkle tree of a merkle block as
+// specified by BIP 0037.
+type partialMerkleTree struct {
+	numTx      uint32
+	hashes     []*chainhash.Hash
+	flags      []byte
+	hashesUsed int
+	bitsUsed   int
+	matches    []*chainhash.Hash
+}
+
+// width returns the number of nodes of the tree at the passed height, where
+// the leaves are at height zero.
+func (t *partialMerkleTree) width(height uint) uint32 {
+	return (t.numTx + (1 << height) - 1) >> height
+}
+
+// traverse returns the hash of the node at the passed height and position,
+// consuming the flag bits and hashes of its subtree and recording the matched
+// transactions.
+func (t *partialMerkleTree) traverse(height uint, pos uint32) (chainhash.Hash, error) {
+	if t.bitsUsed >= len(t.flags)*8 {
+		return chainhash.Hash{}, errors.New("merkle proof has too few " +
+			"flag bits")
+	}
+	parentOfMatch := t.flags[t.bitsUsed/8]&(1<<uint(t.bitsUsed%8)) != 0
+	t.bitsUsed++
+
+	// The hash is given for leaves and for subtrees without matches.
+	if height == 0 || !parentOfMatch {
+		if t.hashesUsed >= len(t.hashes) {
+			return chainhash.Hash{}, errors.New("merkle proof has " +
+				"too few hashes")
+		}
+		hash := t.hashes[t.hashesUsed]
+		t.hashesUsed++
+		if height == 0 && parentOfMatch {
+			t.matches = append(t.matches, hash)
+		}
+		return *hash, nil
+	}
+
+	left, err := t.traverse(height-1, pos*2)
+	if err != nil {
+		return chainhash.Hash{}, err
+	}
+	right := left
+	if pos*2+1 < t.width(height-1) {
+		right, err = t.traverse(height-1, pos*2+1)
+		if err != nil {
+			return chainhash.Hash{}, err
+		}
+
+		// Identical siblings allow a proof for a block with duplicated
+		// transactions to pass as one for the real block.
+		if right == left {
+			return chainhash.Hash{}, errors.New("merkle proof " +
+				"contains duplicate sibling hashes (CVE-2012-2459)")
+		}
+	}
+
+	var buf [chainhash.HashSize * 2]byte
+	copy(buf[:chainhash.HashSize], left[:])
+	copy(buf[chainhash.HashSize:], right[:])
+	return chainhash.DoubleHashH(buf[:]), nil
+}
+
+// extractMerkleMatches returns the merkle root computed from the partial
+// merkle tree of the passed merkle block along with the hashes of the matched
+// transactions.  The tree must use all of the hashes and flag bits of the
+// block.
+func extractMerkleMatches(proof *wire.MsgMerkleBlock) (chainhash.Hash, []*chainhash.Hash, error) {
+	if proof.Transactions == 0 {
+		return chainhash.Hash{}, nil, errors.New("merkle proof has no " +
+			"transactions")
+	}
+	if uint32(len(proof.Hashes)) > proof.Transactions {
+		return chainhash.Hash{}, nil, errors.New("merkle proof has more " +
+			"hashes than transactions")
+	}
+
+	t := &partialMerkleTree{
+		numTx:  proof.Transactions,
+		hashes: proof.Hashes,
+		flags:  proof.Flags,
+	}
+	var height uint
+	for t.width(height) > 1 {
+		height++
+	}
+
+	root, err := t.traverse(height, 0)
+	if err != nil {
+		return chainhash.Hash{}, nil, err
+	}
+	if t.hashesUsed != len(t.hashes) {
+		return chainhash.Hash{}, nil, errors.New("merkle proof has " +
+			"unused hashes")
+	}
+	if (t.bitsUsed+7)/8 != len(t.flags) {
+		return chainhash.Hash{}, nil, errors.New("merkle proof has " +
+			"unused flag bits")
+	}
+	return root, t.matches, nil
+}
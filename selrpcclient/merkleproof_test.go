@@ -0,0 +1,147 @@
+// Copyright (c) 2018 The box developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package selrpcclient
+
+import (
+	"bytes"
+	"encoding/hex"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/wire"
+	"testing"
+)
+
+// TestVerifyMerkleProofLocal ensures the transactions matched by valid proofs
+// are returned and that proofs with mismatched roots, leftover data, or
+// duplicated siblings are rejected.
+func TestVerifyMerkleProofLocal(t *testing.T) {
+	t.Parallel()
+
+	hashPair := func(left, right chainhash.Hash) chainhash.Hash {
+		return chainhash.DoubleHashH(append(left[:len(left):len(left)],
+			right[:]...))
+	}
+	encode := func(root chainhash.Hash, numTx uint32, flags []byte,
+		hashes ...chainhash.Hash) string {
+
+		proof := wire.MsgMerkleBlock{
+			Header:       wire.BlockHeader{MerkleRoot: root},
+			Transactions: numTx,
+			Flags:        flags,
+		}
+		for i := range hashes {
+			proof.Hashes = append(proof.Hashes, &hashes[i])
+		}
+		var buf bytes.Buffer
+		err := proof.BtcEncode(&buf, wire.ProtocolVersion,
+			wire.BaseEncoding)
+		if err != nil {
+			t.Fatalf("BtcEncode: unexpected error: %v", err)
+		}
+		return hex.EncodeToString(buf.Bytes())
+	}
+
+	// A block of three transactions, where the last one is paired with
+	// itself.
+	tx0, tx1, tx2 := chainhash.Hash{0x10}, chainhash.Hash{0x11}, chainhash.Hash{0x12}
+	left := hashPair(tx0, tx1)
+	right := hashPair(tx2, tx2)
+	root := hashPair(left, right)
+
+	tests := []struct {
+		name  string
+		proof string
+		root  chainhash.Hash
+		want  []chainhash.Hash
+	}{
+		{
+			// Flags 1, 1, 0, 1, 0 descend to tx1 and its sibling.
+			name:  "match middle transaction",
+			proof: encode(root, 3, []byte{0x0b}, tx0, tx1, right),
+			root:  root,
+			want:  []chainhash.Hash{tx1},
+		},
+		{
+			// Flags 1, 0, 1, 1 descend to the unpaired tx2.
+			name:  "match last transaction",
+			proof: encode(root, 3, []byte{0x0d}, left, tx2),
+			root:  root,
+			want:  []chainhash.Hash{tx2},
+		},
+		{
+			name:  "single transaction block",
+			proof: encode(tx0, 1, []byte{0x01}, tx0),
+			root:  tx0,
+			want:  []chainhash.Hash{tx0},
+		},
+		{
+			name:  "unexpected root",
+			proof: encode(root, 3, []byte{0x0b}, tx0, tx1, right),
+			root:  left,
+		},
+		{
+			name:  "header root mismatch",
+			proof: encode(left, 3, []byte{0x0b}, tx0, tx1, right),
+			root:  root,
+		},
+		{
+			name:  "unused hashes",
+			proof: encode(root, 3, []byte{0x0b}, tx0, tx1, right, tx2),
+			root:  root,
+		},
+		{
+			name:  "unused flag bytes",
+			proof: encode(root, 3, []byte{0x0b, 0x00}, tx0, tx1, right),
+			root:  root,
+		},
+		{
+			// The same block with tx2 duplicated as a fourth
+			// transaction has the same merkle root.  Flags 1, 0, 1,
+			// 1, 1 descend to both copies of tx2.
+			name:  "duplicated transaction (CVE-2012-2459)",
+			proof: encode(root, 4, []byte{0x1d}, left, tx2, tx2),
+			root:  root,
+		},
+		{
+			name:  "no transactions",
+			proof: encode(root, 0, nil),
+			root:  root,
+		},
+		{
+			name:  "invalid hex",
+			proof: "zz",
+			root:  root,
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		root := test.root
+		matches, err := VerifyMerkleProofLocal(test.proof, &root)
+		if test.want == nil {
+			if err == nil {
+				t.Errorf("Test #%d (%s) expected error", i,
+					test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test #%d (%s) unexpected error: %v", i,
+				test.name, err)
+			continue
+		}
+		if len(matches) != len(test.want) {
+			t.Errorf("Test #%d (%s) unexpected matches - got %v, "+
+				"want %v", i, test.name, matches, test.want)
+			continue
+		}
+		for j := range matches {
+			if *matches[j] != test.want[j] {
+				t.Errorf("Test #%d (%s) unexpected match %d - "+
+					"got %v, want %v", i, test.name, j,
+					matches[j], test.want[j])
+			}
+		}
+	}
+}
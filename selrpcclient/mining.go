@@ -6,12 +6,19 @@
 package selrpcclient
 
 import (
+	"bytes"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"github.com/ltcsuite/ltcd/chaincfg"
 	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/txscript"
+	"github.com/ltcsuite/ltcd/wire"
 	"github.com/ltcsuite/ltcutil"
 	"github.com/zzpu/lib-bitcore/sebtcjson"
+	"strconv"
+	"time"
 )
 
 // FutureGenerateResult is a future promise to deliver the result of a
@@ -414,4 +421,274 @@ func (c *Client) SubmitBlock(block *ltcutil.Block, options *sebtcjson.SubmitBloc
 	return c.SubmitBlockAsync(block, options).Receive()
 }
 
-// TODO(davec): Implement GetBlockTemplate
+// FutureGetBlockTemplateResponse is a future promise to deliver the result of a
+// GetBlockTemplateAsync RPC invocation (or an applicable error).
+type FutureGetBlockTemplateResponse chan *response
+
+// Receive waits for the response promised by the future and returns the block
+// template provided by the server.
+func (r FutureGetBlockTemplateResponse) Receive() (*sebtcjson.GetBlockTemplateResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getblocktemplate result object.
+	var result sebtcjson.GetBlockTemplateResult
+	err = json.Unmarshal(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetBlockTemplateAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GetBlockTemplate for the blocking version and more details.
+func (c *Client) GetBlockTemplateAsync(req *sebtcjson.TemplateRequest) FutureGetBlockTemplateResponse {
+	cmd := sebtcjson.NewGetBlockTemplateCmd(req)
+	return c.sendCmd(cmd)
+}
+
+// GetBlockTemplate returns a new block template for mining.  Newer servers
+// require the request to include "segwit" in its rules.
+//
+// See NewTemplateCoinbase to extract the details needed to assemble the
+// coinbase transaction from the returned template.
+func (c *Client) GetBlockTemplate(req *sebtcjson.TemplateRequest) (*sebtcjson.GetBlockTemplateResult, error) {
+	return c.GetBlockTemplateAsync(req).Receive()
+}
+
+// TemplateCoinbase houses the details of a block template which are needed to
+// assemble its coinbase transaction.
+type TemplateCoinbase struct {
+	// Height is the height of the block, which BIP 0034 requires to be
+	// the first item of the coinbase signature script.
+	Height int64
+
+	// Value is the total value available to the coinbase outputs, which
+	// is the block subsidy plus the fees of the template transactions.
+	Value ltcutil.Amount
+
+	// AuxFlags are the bytes the server requests to be included in the
+	// coinbase signature script.  It is nil when none are requested.
+	AuxFlags []byte
+
+	// WitnessCommitment is the public key script of the witness
+	// commitment output required by BIP 0141.  It is nil when the
+	// template does not contain any witness transactions.
+	WitnessCommitment []byte
+}
+
+// NewTemplateCoinbase extracts the details needed to assemble the coinbase
+// transaction of the passed block template.  Templates which provide a
+// coinbase transaction rather than a coinbase value are not supported.
+func NewTemplateCoinbase(template *sebtcjson.GetBlockTemplateResult) (*TemplateCoinbase, error) {
+	if template.CoinbaseValue == nil {
+		return nil, errors.New("block template does not provide a " +
+			"coinbase value")
+	}
+
+	coinbase := &TemplateCoinbase{
+		Height: template.Height,
+		Value:  ltcutil.Amount(*template.CoinbaseValue),
+	}
+
+	if template.CoinbaseAux != nil && template.CoinbaseAux.Flags != "" {
+		flags, err := hex.DecodeString(template.CoinbaseAux.Flags)
+		if err != nil {
+			return nil, err
+		}
+		coinbase.AuxFlags = flags
+	}
+
+	if template.DefaultWitnessCommitment != "" {
+		commitment, err := hex.DecodeString(template.DefaultWitnessCommitment)
+		if err != nil {
+			return nil, err
+		}
+		coinbase.WitnessCommitment = commitment
+	}
+
+	return coinbase, nil
+}
+
+// TxOuts returns the coinbase outputs for the template, paying the full value
+// to the passed public key script followed by the zero value witness commitment
+// output when one is required.
+func (t *TemplateCoinbase) TxOuts(pkScript []byte) []*wire.TxOut {
+	txOuts := []*wire.TxOut{wire.NewTxOut(int64(t.Value), pkScript)}
+	if t.WitnessCommitment != nil {
+		txOuts = append(txOuts, wire.NewTxOut(0, t.WitnessCommitment))
+	}
+	return txOuts
+}
+
+const (
+	// minCoinbaseScriptLen and maxCoinbaseScriptLen are the bounds the
+	// consensus rules place on the length of the coinbase signature script.
+	minCoinbaseScriptLen = 2
+	maxCoinbaseScriptLen = 100
+)
+
+// witnessReservedValue is the witness of the coinbase input of blocks with a
+// witness commitment.  It is all zeros as no commitment structure beyond BIP
+// 0141 is defined.
+var witnessReservedValue = make([]byte, 32)
+
+// AssembleBlock assembles a candidate block from the passed block template
+// which pays the coinbase value to the passed address.  The coinbase signature
+// script starts with the block height as required by BIP 0034, followed by the
+// passed extra nonce and the aux flags requested by the template.  When the
+// template carries a witness commitment, it is added as a coinbase output and
+// the witness reserved value is set on the coinbase input.
+//
+// The template transactions follow the coinbase in the order of the template,
+// which lists every transaction after the ones it depends on.  The header
+// commits to the transactions, but its nonce is zero, so the block has to be
+// solved before it is submitted with SubmitBlock.
+func AssembleBlock(template *sebtcjson.GetBlockTemplateResult,
+	payoutAddr ltcutil.Address, extraNonce []byte) (*wire.MsgBlock, error) {
+
+	coinbase, err := NewTemplateCoinbase(template)
+	if err != nil {
+		return nil, err
+	}
+	pkScript, err := txscript.PayToAddrScript(payoutAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := txscript.NewScriptBuilder().AddInt64(coinbase.Height)
+	if len(extraNonce) > 0 {
+		builder.AddData(extraNonce)
+	}
+	if len(coinbase.AuxFlags) > 0 {
+		builder.AddData(coinbase.AuxFlags)
+	}
+	sigScript, err := builder.Script()
+	if err != nil {
+		return nil, err
+	}
+	if len(sigScript) < minCoinbaseScriptLen {
+		sigScript = append(sigScript, txscript.OP_0)
+	}
+	if len(sigScript) > maxCoinbaseScriptLen {
+		return nil, fmt.Errorf("coinbase signature script is %d bytes, "+
+			"the maximum is %d", len(sigScript), maxCoinbaseScriptLen)
+	}
+
+	coinbaseTx := wire.NewMsgTx(wire.TxVersion)
+	txIn := wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{},
+		wire.MaxPrevOutIndex), sigScript, nil)
+	if coinbase.WitnessCommitment != nil {
+		txIn.Witness = wire.TxWitness{witnessReservedValue}
+	}
+	coinbaseTx.AddTxIn(txIn)
+	for _, txOut := range coinbase.TxOuts(pkScript) {
+		coinbaseTx.AddTxOut(txOut)
+	}
+
+	transactions := []*wire.MsgTx{coinbaseTx}
+	for i, templateTx := range template.Transactions {
+		// Dependencies are 1-based indexes into the transactions of
+		// the template.
+		for _, dep := range templateTx.Depends {
+			if dep < 1 || dep > int64(i) {
+				return nil, fmt.Errorf("template transaction %d "+
+					"depends on transaction %d which does not "+
+					"precede it", i+1, dep)
+			}
+		}
+
+		serialized, err := hex.DecodeString(templateTx.Data)
+		if err != nil {
+			return nil, err
+		}
+		var tx wire.MsgTx
+		if err := tx.Deserialize(bytes.NewReader(serialized)); err != nil {
+			return nil, fmt.Errorf("template transaction %d: %v", i+1,
+				err)
+		}
+		transactions = append(transactions, &tx)
+	}
+
+	prevHash, err := chainhash.NewHashFromStr(template.PreviousHash)
+	if err != nil {
+		return nil, err
+	}
+	bits, err := strconv.ParseUint(template.Bits, 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bits %q: %v", template.Bits, err)
+	}
+
+	txHashes := make([]chainhash.Hash, len(transactions))
+	for i, tx := range transactions {
+		txHashes[i] = tx.TxHash()
+	}
+	root := merkleRoot(txHashes)
+	header := wire.NewBlockHeader(template.Version, prevHash, &root,
+		uint32(bits), 0)
+	header.Timestamp = time.Unix(template.CurTime, 0)
+
+	block := wire.NewMsgBlock(header)
+	for _, tx := range transactions {
+		if err := block.AddTransaction(tx); err != nil {
+			return nil, err
+		}
+	}
+	return block, nil
+}
+
+// merkleRoot returns the root of the merkle tree of the passed transaction
+// hashes, duplicating the last hash of levels with an odd number of them.
+func merkleRoot(hashes []chainhash.Hash) chainhash.Hash {
+	if len(hashes) == 0 {
+		return chainhash.Hash{}
+	}
+
+	level := append([]chainhash.Hash(nil), hashes...)
+	var buf [chainhash.HashSize * 2]byte
+	for len(level) > 1 {
+		if len(level)%2 != 0 {
+			level = append(level, level[len(level)-1])
+		}
+		next := level[:0]
+		for i := 0; i < len(level); i += 2 {
+			copy(buf[:chainhash.HashSize], level[i][:])
+			copy(buf[chainhash.HashSize:], level[i+1][:])
+			next = append(next, chainhash.DoubleHashH(buf[:]))
+		}
+		level = next
+	}
+	return level[0]
+}
+
+// baseSubsidy is the block subsidy paid before the first subsidy reduction.
+const baseSubsidy = 50 * ltcutil.SatoshiPerBitcoin
+
+// BlockSubsidy returns the subsidy a coinbase transaction may claim, excluding
+// fees, for the block at the passed height on the passed network.  The subsidy
+// halves every SubsidyReductionInterval blocks until it reaches zero.
+func BlockSubsidy(height int64, params *chaincfg.Params) ltcutil.Amount {
+	if height < 0 || params.SubsidyReductionInterval <= 0 {
+		return 0
+	}
+	halvings := height / int64(params.SubsidyReductionInterval)
+	if halvings >= 64 {
+		return 0
+	}
+	return ltcutil.Amount(baseSubsidy >> uint(halvings))
+}
+
+// BlockReward returns the subsidy for the block at the passed height on the
+// network set by the ChainParams field of the connection configuration, which
+// defaults to the main network.  Subtracting it from the value of the coinbase
+// outputs of a block leaves the fees it collected.
+//
+// See BlockSubsidy for details.
+func (c *Client) BlockReward(height int64) ltcutil.Amount {
+	return BlockSubsidy(height, c.chainParams())
+}
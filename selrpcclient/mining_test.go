@@ -0,0 +1,207 @@
+// Copyright (c) 2018 The box developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package selrpcclient
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/txscript"
+	"github.com/ltcsuite/ltcd/wire"
+	"github.com/ltcsuite/ltcutil"
+	"github.com/zzpu/lib-bitcore/sebtcjson"
+	"strings"
+	"testing"
+)
+
+// TestTemplateCoinbase ensures the coinbase details are extracted from a block
+// template and turned into the expected coinbase outputs.
+func TestTemplateCoinbase(t *testing.T) {
+	t.Parallel()
+
+	const template = `{"version":536870912,"rules":["csv","segwit"],` +
+		`"previousblockhash":"0000000000000000000b4d0b2e8e7f6a4a1c3d5e6f708192a3b4c5d6e7f80910",` +
+		`"transactions":[{"data":"0100","txid":"aa","hash":"bb","depends":[],"fee":1500,"sigops":4,"weight":561}],` +
+		`"coinbaseaux":{"flags":"0a0b"},"coinbasevalue":625001500,` +
+		`"default_witness_commitment":"6a24aa21a9ed0001",` +
+		`"curtime":1600000000,"bits":"17100000","height":650000}`
+
+	var result sebtcjson.GetBlockTemplateResult
+	if err := json.Unmarshal([]byte(template), &result); err != nil {
+		t.Fatalf("unable to unmarshal template: %v", err)
+	}
+	tx := result.Transactions[0]
+	if tx.Data != "0100" || tx.TxID != "aa" || tx.Hash != "bb" ||
+		tx.Weight != 561 {
+		t.Errorf("unexpected template transaction: %+v", tx)
+	}
+
+	coinbase, err := NewTemplateCoinbase(&result)
+	if err != nil {
+		t.Fatalf("NewTemplateCoinbase: unexpected error: %v", err)
+	}
+	if coinbase.Height != 650000 {
+		t.Errorf("unexpected height - got %d, want %d", coinbase.Height,
+			650000)
+	}
+	if coinbase.Value != ltcutil.Amount(625001500) {
+		t.Errorf("unexpected value - got %v, want %v", coinbase.Value,
+			ltcutil.Amount(625001500))
+	}
+	if !bytes.Equal(coinbase.AuxFlags, []byte{0x0a, 0x0b}) {
+		t.Errorf("unexpected aux flags - got %x", coinbase.AuxFlags)
+	}
+
+	pkScript := []byte{0x51}
+	txOuts := coinbase.TxOuts(pkScript)
+	if len(txOuts) != 2 {
+		t.Fatalf("unexpected number of outputs - got %d, want %d",
+			len(txOuts), 2)
+	}
+	if txOuts[0].Value != 625001500 || !bytes.Equal(txOuts[0].PkScript, pkScript) {
+		t.Errorf("unexpected payout output: %+v", txOuts[0])
+	}
+	commitment := []byte{0x6a, 0x24, 0xaa, 0x21, 0xa9, 0xed, 0x00, 0x01}
+	if txOuts[1].Value != 0 || !bytes.Equal(txOuts[1].PkScript, commitment) {
+		t.Errorf("unexpected witness commitment output: %+v", txOuts[1])
+	}
+
+	// Templates without a coinbase value cannot be used.
+	result.CoinbaseValue = nil
+	if _, err := NewTemplateCoinbase(&result); err == nil {
+		t.Errorf("expected error for template without coinbase value")
+	}
+}
+
+// TestBlockSubsidy ensures the subsidy halves at each reduction interval of the
+// network and eventually reaches zero.
+func TestBlockSubsidy(t *testing.T) {
+	t.Parallel()
+
+	mainInterval := int64(chaincfg.MainNetParams.SubsidyReductionInterval)
+	regtestInterval := int64(chaincfg.RegressionNetParams.SubsidyReductionInterval)
+	tests := []struct {
+		name   string
+		params *chaincfg.Params
+		height int64
+		want   ltcutil.Amount
+	}{
+		{"genesis", &chaincfg.MainNetParams, 0, 5000000000},
+		{"before first halving", &chaincfg.MainNetParams, mainInterval - 1, 5000000000},
+		{"first halving", &chaincfg.MainNetParams, mainInterval, 2500000000},
+		{"fourth halving", &chaincfg.MainNetParams, 4 * mainInterval, 312500000},
+		{"last satoshi", &chaincfg.MainNetParams, 33*mainInterval - 1, 1},
+		{"zero subsidy", &chaincfg.MainNetParams, 33 * mainInterval, 0},
+		{"beyond 64 halvings", &chaincfg.MainNetParams, 64 * mainInterval, 0},
+		{"negative height", &chaincfg.MainNetParams, -1, 0},
+		{"regtest halving", &chaincfg.RegressionNetParams, regtestInterval, 2500000000},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		got := BlockSubsidy(test.height, test.params)
+		if got != test.want {
+			t.Errorf("Test #%d (%s) unexpected subsidy - got %d, want %d",
+				i, test.name, got, test.want)
+		}
+	}
+
+	config := &ConnConfig{ChainParams: &chaincfg.RegressionNetParams}
+	client := &Client{config: config}
+	if got := client.BlockReward(regtestInterval); got != 2500000000 {
+		t.Errorf("BlockReward: got %d, want 2500000000", got)
+	}
+	config.ChainParams = nil
+	if got := client.BlockReward(regtestInterval); got != 5000000000 {
+		t.Errorf("BlockReward: main network default got %d, want "+
+			"5000000000", got)
+	}
+}
+
+// TestAssembleBlock ensures a block assembled from a template has a coinbase
+// paying the template value with the BIP 0034 height and witness commitment,
+// followed by the template transactions, and a header committing to them.
+func TestAssembleBlock(t *testing.T) {
+	t.Parallel()
+
+	spend := wire.NewMsgTx(wire.TxVersion)
+	spend.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{0x01}, 0),
+		nil, wire.TxWitness{{0x01}}))
+	spend.AddTxOut(wire.NewTxOut(1000, []byte{txscript.OP_TRUE}))
+	var buf bytes.Buffer
+	if err := spend.Serialize(&buf); err != nil {
+		t.Fatalf("Serialize: unexpected error: %v", err)
+	}
+
+	prevHash := chainhash.Hash{0x02}
+	template := &sebtcjson.GetBlockTemplateResult{
+		Version:      0x20000000,
+		PreviousHash: prevHash.String(),
+		CurTime:      1600000000,
+		Bits:         "1d00ffff",
+		Height:       500,
+		Transactions: []sebtcjson.GetBlockTemplateResultTx{
+			{Data: hex.EncodeToString(buf.Bytes()), Depends: []int64{}},
+		},
+		CoinbaseValue:            sebtcjson.Int64(5000001000),
+		DefaultWitnessCommitment: "6a24aa21a9ed" + strings.Repeat("00", 32),
+	}
+	payout, err := ltcutil.NewAddressPubKeyHash(make([]byte, 20),
+		&chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("NewAddressPubKeyHash: unexpected error: %v", err)
+	}
+
+	block, err := AssembleBlock(template, payout, []byte{0xaa, 0xbb})
+	if err != nil {
+		t.Fatalf("AssembleBlock: unexpected error: %v", err)
+	}
+	if len(block.Transactions) != 2 {
+		t.Fatalf("got %d transactions, want 2", len(block.Transactions))
+	}
+	if block.Transactions[1].TxHash() != spend.TxHash() {
+		t.Errorf("template transaction not included after the coinbase")
+	}
+
+	coinbase := block.Transactions[0]
+	wantScript := []byte{0x02, 0xf4, 0x01, 0x02, 0xaa, 0xbb}
+	if !bytes.Equal(coinbase.TxIn[0].SignatureScript, wantScript) {
+		t.Errorf("coinbase signature script: got %x, want %x",
+			coinbase.TxIn[0].SignatureScript, wantScript)
+	}
+	if len(coinbase.TxIn[0].Witness) != 1 ||
+		!bytes.Equal(coinbase.TxIn[0].Witness[0], make([]byte, 32)) {
+
+		t.Errorf("coinbase witness is not the witness reserved value")
+	}
+	payScript, _ := txscript.PayToAddrScript(payout)
+	if len(coinbase.TxOut) != 2 || coinbase.TxOut[0].Value != 5000001000 ||
+		!bytes.Equal(coinbase.TxOut[0].PkScript, payScript) {
+
+		t.Errorf("unexpected coinbase outputs: %+v", coinbase.TxOut)
+	}
+
+	var pair [chainhash.HashSize * 2]byte
+	coinbaseHash, spendHash := coinbase.TxHash(), spend.TxHash()
+	copy(pair[:chainhash.HashSize], coinbaseHash[:])
+	copy(pair[chainhash.HashSize:], spendHash[:])
+	header := block.Header
+	if header.MerkleRoot != chainhash.DoubleHashH(pair[:]) {
+		t.Errorf("unexpected merkle root %v", header.MerkleRoot)
+	}
+	if header.Version != 0x20000000 || header.PrevBlock != prevHash ||
+		header.Bits != 0x1d00ffff || header.Timestamp.Unix() != 1600000000 {
+
+		t.Errorf("unexpected header: %+v", header)
+	}
+
+	// Transactions must follow the ones they depend on.
+	template.Transactions[0].Depends = []int64{1}
+	if _, err := AssembleBlock(template, payout, nil); err == nil {
+		t.Errorf("expected error for transaction depending on itself")
+	}
+}
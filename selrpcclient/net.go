@@ -7,7 +7,9 @@ package selrpcclient
 
 import (
 	"encoding/json"
+	"github.com/ltcsuite/ltcd/wire"
 	"github.com/zzpu/lib-bitcore/sebtcjson"
+	"sort"
 )
 
 // AddNodeCommand enumerates the available commands that the AddNode function
@@ -247,6 +249,170 @@ func (c *Client) GetPeerInfo() ([]sebtcjson.GetPeerInfoResult, error) {
 	return c.GetPeerInfoAsync().Receive()
 }
 
+// BestPeers returns up to n of the connected peers which have synced the blocks
+// of the server up to its best block, ordered by ping time with the fastest
+// first.  Peers which have not answered a ping yet are ordered last.
+//
+// The height a peer has synced is taken from synced_blocks as reported by
+// Bitcoin Core, or from currentheight for servers such as btcd which do not
+// report it.
+func (c *Client) BestPeers(n int) ([]sebtcjson.GetPeerInfoResult, error) {
+	peersFuture := c.GetPeerInfoAsync()
+	countFuture := c.GetBlockCountAsync()
+	peers, err := peersFuture.Receive()
+	if err != nil {
+		return nil, err
+	}
+	height, err := countFuture.Receive()
+	if err != nil {
+		return nil, err
+	}
+
+	synced := make([]sebtcjson.GetPeerInfoResult, 0, len(peers))
+	for _, peer := range peers {
+		peerHeight := peer.SyncedBlocks
+		if peerHeight == 0 {
+			peerHeight = peer.CurrentHeight
+		}
+		if int64(peerHeight) == height {
+			synced = append(synced, peer)
+		}
+	}
+
+	sort.SliceStable(synced, func(i, j int) bool {
+		pi, pj := synced[i].PingTime, synced[j].PingTime
+		if pi <= 0 || pj <= 0 {
+			return pj <= 0 && pi > 0
+		}
+		return pi < pj
+	})
+	if n >= 0 && len(synced) > n {
+		synced = synced[:n]
+	}
+	return synced, nil
+}
+
+// FutureGetNetworkInfoResult is a future promise to deliver the result of a
+// GetNetworkInfoAsync RPC invocation (or an applicable error).
+type FutureGetNetworkInfoResult chan *response
+
+// Receive waits for the response promised by the future and returns data about
+// the current network state of the server.
+func (r FutureGetNetworkInfoResult) Receive() (*sebtcjson.GetNetworkInfoResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getnetworkinfo result object.
+	var networkInfo sebtcjson.GetNetworkInfoResult
+	err = json.Unmarshal(res, &networkInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &networkInfo, nil
+}
+
+// GetNetworkInfoAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See GetNetworkInfo for the blocking version and more details.
+func (c *Client) GetNetworkInfoAsync() FutureGetNetworkInfoResult {
+	cmd := sebtcjson.NewGetNetworkInfoCmd()
+	return c.sendCmd(cmd)
+}
+
+// GetNetworkInfo returns data about the current network state of the server.
+func (c *Client) GetNetworkInfo() (*sebtcjson.GetNetworkInfoResult, error) {
+	return c.GetNetworkInfoAsync().Receive()
+}
+
+// FutureGetZmqNotificationsResult is a future promise to deliver the result of
+// a GetZmqNotificationsAsync RPC invocation (or an applicable error).
+type FutureGetZmqNotificationsResult chan *response
+
+// Receive waits for the response promised by the future and returns the active
+// ZMQ publishers of the server.
+func (r FutureGetZmqNotificationsResult) Receive() (sebtcjson.GetZmqNotificationsResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as an array of getzmqnotifications result objects.
+	var notifications sebtcjson.GetZmqNotificationsResult
+	err = json.Unmarshal(res, &notifications)
+	if err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+// GetZmqNotificationsAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See GetZmqNotifications for the blocking version and more details.
+func (c *Client) GetZmqNotificationsAsync() FutureGetZmqNotificationsResult {
+	cmd := sebtcjson.NewGetZmqNotificationsCmd()
+	return c.sendCmd(cmd)
+}
+
+// GetZmqNotifications returns the ZMQ notifications the server publishes along
+// with the address and the high water mark of each, which allows verifying the
+// ZMQ setup before subscribing to the raw block and transaction streams.  The
+// result is empty when the server publishes none.
+//
+// NOTE: This requires Bitcoin Core 0.17 or later built with ZMQ support.
+func (c *Client) GetZmqNotifications() (sebtcjson.GetZmqNotificationsResult, error) {
+	return c.GetZmqNotificationsAsync().Receive()
+}
+
+// NodeWarnings returns the warnings reported by the getnetworkinfo and
+// getblockchaininfo results, such as notices about unknown new rules being
+// activated.  Warnings reported by both are only returned once.
+func (c *Client) NodeWarnings() ([]string, error) {
+	networkFuture := c.GetNetworkInfoAsync()
+	chainFuture := c.GetBlockChainInfoAsync()
+
+	networkInfo, err := networkFuture.Receive()
+	if err != nil {
+		receiveFuture(chainFuture)
+		return nil, err
+	}
+	chainInfo, err := chainFuture.Receive()
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings []string
+	seen := make(map[string]struct{})
+	for _, list := range [][]string{networkInfo.WarningList(), chainInfo.Warnings} {
+		for _, warning := range list {
+			if _, ok := seen[warning]; ok {
+				continue
+			}
+			seen[warning] = struct{}{}
+			warnings = append(warnings, warning)
+		}
+	}
+	return warnings, nil
+}
+
+// ParseServiceFlag parses the services reported by the getpeerinfo and
+// getnetworkinfo results into a wire.ServiceFlag.  Both the hex-encoded string
+// and the integer representations used across server versions are supported.
+func ParseServiceFlag(services string) (wire.ServiceFlag, error) {
+	flags, err := sebtcjson.ServiceBits(services).Flags()
+	if err != nil {
+		return 0, err
+	}
+	return wire.ServiceFlag(flags), nil
+}
+
 // FutureGetNetTotalsResult is a future promise to deliver the result of a
 // GetNetTotalsAsync RPC invocation (or an applicable error).
 type FutureGetNetTotalsResult chan *response
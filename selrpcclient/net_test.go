@@ -0,0 +1,158 @@
+// Copyright (c) 2018 The box developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package selrpcclient
+
+import (
+	"encoding/json"
+	"github.com/zzpu/lib-bitcore/sebtcjson"
+	"reflect"
+	"testing"
+)
+
+// TestNodeWarnings ensures warnings reported as either a string or an array
+// are combined from getnetworkinfo and getblockchaininfo without duplicates.
+func TestNodeWarnings(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		networkInfo string
+		chainInfo   string
+		want        []string
+	}{
+		{
+			name:        "string form",
+			networkInfo: `{"version":170000,"warnings":"unknown new rules activated"}`,
+			chainInfo:   `{"chain":"main","warnings":"unknown new rules activated"}`,
+			want:        []string{"unknown new rules activated"},
+		},
+		{
+			name:        "empty string form",
+			networkInfo: `{"version":170000,"warnings":""}`,
+			chainInfo:   `{"chain":"main","warnings":""}`,
+			want:        nil,
+		},
+		{
+			name:        "array form",
+			networkInfo: `{"version":270000,"warnings":["unknown new rules activated","clock out of sync"]}`,
+			chainInfo:   `{"chain":"main","warnings":["unknown new rules activated"]}`,
+			want:        []string{"unknown new rules activated", "clock out of sync"},
+		},
+		{
+			name:        "mixed forms",
+			networkInfo: `{"version":170000,"warnings":"clock out of sync"}`,
+			chainInfo:   `{"chain":"main","warnings":["unknown new rules activated"]}`,
+			want:        []string{"clock out of sync", "unknown new rules activated"},
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		test := test
+		client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+			switch method {
+			case "getnetworkinfo":
+				return json.RawMessage(test.networkInfo), nil
+			case "getblockchaininfo":
+				return json.RawMessage(test.chainInfo), nil
+			}
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		})
+
+		warnings, err := client.NodeWarnings()
+		closeTestClient(client, server)
+		if err != nil {
+			t.Errorf("Test #%d (%s) unexpected error: %v", i,
+				test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(warnings, test.want) {
+			t.Errorf("Test #%d (%s) unexpected warnings - got %q, "+
+				"want %q", i, test.name, warnings, test.want)
+		}
+	}
+}
+
+// TestBestPeers ensures only peers synced to the best block are returned,
+// ordered by ping time with peers which have not answered a ping last.
+func TestBestPeers(t *testing.T) {
+	t.Parallel()
+
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		switch method {
+		case "getblockcount":
+			return 100, nil
+		case "getpeerinfo":
+			return json.RawMessage(`[
+				{"id":1,"pingtime":0.3,"synced_blocks":100},
+				{"id":2,"synced_blocks":100},
+				{"id":3,"pingtime":0.01,"synced_blocks":99},
+				{"id":4,"pingtime":0.1,"synced_blocks":100},
+				{"id":5,"pingtime":0.2,"currentheight":100}
+			]`), nil
+		}
+		return nil, sebtcjson.ErrRPCMethodNotFound
+	})
+	defer closeTestClient(client, server)
+
+	tests := []struct {
+		n    int
+		want []int32
+	}{
+		{n: 10, want: []int32{4, 5, 1, 2}},
+		{n: 2, want: []int32{4, 5}},
+		{n: 0, want: nil},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		peers, err := client.BestPeers(test.n)
+		if err != nil {
+			t.Errorf("Test #%d unexpected error: %v", i, err)
+			continue
+		}
+		var ids []int32
+		for _, peer := range peers {
+			ids = append(ids, peer.ID)
+		}
+		if !reflect.DeepEqual(ids, test.want) {
+			t.Errorf("Test #%d unexpected peers - got %v, want %v", i,
+				ids, test.want)
+		}
+	}
+}
+
+// TestGetZmqNotifications ensures the active ZMQ publishers of the server are
+// decoded.
+func TestGetZmqNotifications(t *testing.T) {
+	t.Parallel()
+
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		if method != "getzmqnotifications" {
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		}
+		return json.RawMessage(`[
+			{"type":"pubrawblock","address":"tcp://127.0.0.1:28332","hwm":1000},
+			{"type":"pubrawtx","address":"tcp://127.0.0.1:28333","hwm":5000}
+		]`), nil
+	})
+	defer closeTestClient(client, server)
+
+	notifications, err := client.GetZmqNotifications()
+	if err != nil {
+		t.Fatalf("GetZmqNotifications: unexpected error: %v", err)
+	}
+	want := sebtcjson.GetZmqNotificationsResult{
+		{Type: "pubrawblock", Address: "tcp://127.0.0.1:28332", HWM: 1000},
+		{Type: "pubrawtx", Address: "tcp://127.0.0.1:28333", HWM: 5000},
+	}
+	if !reflect.DeepEqual(notifications, want) {
+		t.Errorf("GetZmqNotifications: unexpected result - got %+v, "+
+			"want %+v", notifications, want)
+	}
+	if got := server.calls("getzmqnotifications"); got != 1 {
+		t.Errorf("GetZmqNotifications: got %d calls, want 1", got)
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2018 The box developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package selrpcclient
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/txscript"
+	"github.com/ltcsuite/ltcd/wire"
+	"github.com/zzpu/lib-bitcore/sebtcjson"
+	"reflect"
+	"testing"
+)
+
+// TestUnknownNotification ensures notifications with an unrecognized method are
+// delivered to OnUnknownNotification with their raw parameters, while
+// recognized notifications still reach their own handler.
+func TestUnknownNotification(t *testing.T) {
+	t.Parallel()
+
+	var (
+		gotMethod string
+		gotParams []json.RawMessage
+		locked    []bool
+	)
+	client := &Client{
+		config: &ConnConfig{},
+		ntfnHandlers: &NotificationHandlers{
+			OnWalletLockState: func(l bool) {
+				locked = append(locked, l)
+			},
+			OnUnknownNotification: func(method string, params []json.RawMessage) {
+				gotMethod, gotParams = method, params
+			},
+		},
+	}
+
+	client.handleMessage([]byte(`{"jsonrpc":"1.0","id":null,` +
+		`"method":"walletlockstate","params":["",true]}`))
+	if gotMethod != "" {
+		t.Errorf("recognized notification delivered as unknown: %s",
+			gotMethod)
+	}
+	if !reflect.DeepEqual(locked, []bool{true}) {
+		t.Errorf("wallet lock state handler got %v, want [true]", locked)
+	}
+
+	client.handleMessage([]byte(`{"jsonrpc":"1.0","id":null,` +
+		`"method":"mempoolchanged","params":[{"size":3},"now"]}`))
+	wantParams := []json.RawMessage{
+		json.RawMessage(`{"size":3}`),
+		json.RawMessage(`"now"`),
+	}
+	if gotMethod != "mempoolchanged" {
+		t.Errorf("unknown notification method: got %q, want %q",
+			gotMethod, "mempoolchanged")
+	}
+	if !reflect.DeepEqual(gotParams, wantParams) {
+		t.Errorf("unknown notification params: got %s, want %s",
+			gotParams, wantParams)
+	}
+
+	// Unknown notifications are dropped without a handler.
+	client.ntfnHandlers.OnUnknownNotification = nil
+	client.handleMessage([]byte(`{"jsonrpc":"1.0","id":null,` +
+		`"method":"mempoolchanged","params":[]}`))
+}
+
+// TestRelevantTxAccepted ensures relevanttxaccepted notifications deliver the
+// serialized transaction to OnRelevantTxAccepted and that invalid ones are
+// dropped.
+func TestRelevantTxAccepted(t *testing.T) {
+	t.Parallel()
+
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{0x01}, 0), nil, nil))
+	tx.AddTxOut(wire.NewTxOut(1000, []byte{0x51}))
+	var buf bytes.Buffer
+	if err := tx.Serialize(&buf); err != nil {
+		t.Fatalf("Serialize: unexpected error: %v", err)
+	}
+
+	var got [][]byte
+	client := &Client{
+		config: &ConnConfig{},
+		ntfnHandlers: &NotificationHandlers{
+			OnRelevantTxAccepted: func(transaction []byte) {
+				got = append(got, transaction)
+			},
+		},
+	}
+
+	client.handleMessage([]byte(`{"jsonrpc":"1.0","id":null,` +
+		`"method":"relevanttxaccepted","params":["` +
+		hex.EncodeToString(buf.Bytes()) + `"]}`))
+	client.handleMessage([]byte(`{"jsonrpc":"1.0","id":null,` +
+		`"method":"relevanttxaccepted","params":["zz"]}`))
+	client.handleMessage([]byte(`{"jsonrpc":"1.0","id":null,` +
+		`"method":"relevanttxaccepted","params":[]}`))
+	if !reflect.DeepEqual(got, [][]byte{buf.Bytes()}) {
+		t.Fatalf("unexpected transactions - got %x, want [%x]", got,
+			buf.Bytes())
+	}
+
+	var msgTx wire.MsgTx
+	if err := msgTx.Deserialize(bytes.NewReader(got[0])); err != nil {
+		t.Fatalf("Deserialize: unexpected error: %v", err)
+	}
+	if msgTx.TxHash() != tx.TxHash() {
+		t.Errorf("unexpected transaction - got %v, want %v",
+			msgTx.TxHash(), tx.TxHash())
+	}
+}
+
+// TestLoadTxFilterScripts ensures output scripts are loaded into the filter as
+// the addresses they pay to on the configured network, and that scripts which
+// pay to no address are rejected before any request is sent.
+func TestLoadTxFilterScripts(t *testing.T) {
+	t.Parallel()
+
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		if method != "loadtxfilter" {
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		}
+		return nil, nil
+	})
+	defer closeTestClient(client, server)
+	client.config.ChainParams = &chaincfg.RegressionNetParams
+
+	pkScript, err := txscript.NewScriptBuilder().AddOp(txscript.OP_0).
+		AddData(bytes.Repeat([]byte{0x01}, 20)).Script()
+	if err != nil {
+		t.Fatalf("Script: unexpected error: %v", err)
+	}
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(pkScript,
+		&chaincfg.RegressionNetParams)
+	if err != nil || len(addrs) != 1 {
+		t.Fatalf("ExtractPkScriptAddrs: unexpected result %v, %v",
+			addrs, err)
+	}
+
+	outPoint := wire.NewOutPoint(&chainhash.Hash{0x02}, 1)
+	err = client.LoadTxFilterScripts(true, [][]byte{pkScript},
+		[]wire.OutPoint{*outPoint})
+	if err != nil {
+		t.Fatalf("LoadTxFilterScripts: unexpected error: %v", err)
+	}
+	params := marshalParams(t, server.lastRequest("loadtxfilter"))
+	want := `[true,["` + addrs[0].EncodeAddress() + `"],[{"hash":"` +
+		outPoint.Hash.String() + `","index":1}]]`
+	if params != want {
+		t.Errorf("unexpected params - got %s, want %s", params, want)
+	}
+
+	err = client.LoadTxFilterScripts(false, [][]byte{{txscript.OP_RETURN}},
+		nil)
+	if err == nil {
+		t.Errorf("LoadTxFilterScripts: expected error for script " +
+			"without address")
+	}
+	if calls := server.calls("loadtxfilter"); calls != 1 {
+		t.Errorf("unexpected loadtxfilter calls - got %d, want 1", calls)
+	}
+}
@@ -0,0 +1,324 @@
+// Copyright (c) 2018 The box developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package selrpcclient
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/wire"
+	"github.com/ltcsuite/ltcutil"
+	"github.com/zzpu/lib-bitcore/sebtcjson"
+	"reflect"
+	"testing"
+)
+
+// testPsbt returns the base64 encoding of a PSBT of the passed unsigned
+// transaction.  Every input carries the passed final signature script unless it
+// is nil.
+func testPsbt(t *testing.T, tx *wire.MsgTx, finalScriptSig []byte) string {
+	var buf bytes.Buffer
+	buf.WriteString("psbt\xff")
+
+	// The global map only holds the unsigned transaction.
+	buf.Write([]byte{0x01, 0x00})
+	if err := wire.WriteVarInt(&buf, 0, uint64(tx.SerializeSize())); err != nil {
+		t.Fatalf("WriteVarInt: unexpected error: %v", err)
+	}
+	if err := tx.Serialize(&buf); err != nil {
+		t.Fatalf("Serialize: unexpected error: %v", err)
+	}
+	buf.WriteByte(0x00)
+
+	for range tx.TxIn {
+		if finalScriptSig != nil {
+			buf.Write([]byte{0x01, 0x07})
+			err := wire.WriteVarBytes(&buf, 0, finalScriptSig)
+			if err != nil {
+				t.Fatalf("WriteVarBytes: unexpected error: %v", err)
+			}
+		}
+		buf.WriteByte(0x00)
+	}
+	for range tx.TxOut {
+		buf.WriteByte(0x00)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+// TestPsbtWorkflow ensures a PSBT of one input and two outputs makes it through
+// funding, signing, combining, finalizing, decoding and analysis, with each
+// call passing the PSBT returned by the previous one and the final transaction
+// matching the signed one.
+func TestPsbtWorkflow(t *testing.T) {
+	t.Parallel()
+
+	payee, err := ltcutil.NewAddressPubKeyHash(bytes.Repeat([]byte{0x01}, 20),
+		&chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatalf("NewAddressPubKeyHash: unexpected error: %v", err)
+	}
+	change, err := ltcutil.NewAddressPubKeyHash(bytes.Repeat([]byte{0x02}, 20),
+		&chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatalf("NewAddressPubKeyHash: unexpected error: %v", err)
+	}
+
+	prevOut := wire.NewOutPoint(&chainhash.Hash{0x01}, 0)
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxIn(wire.NewTxIn(prevOut, nil, nil))
+	tx.AddTxOut(wire.NewTxOut(50000000, []byte{0x51}))
+	tx.AddTxOut(wire.NewTxOut(49990000, []byte{0x52}))
+	signed := tx.Copy()
+	signed.TxIn[0].SignatureScript = []byte{0x51}
+	var signedBuf bytes.Buffer
+	if err := signed.Serialize(&signedBuf); err != nil {
+		t.Fatalf("Serialize: unexpected error: %v", err)
+	}
+
+	unsignedPsbt := testPsbt(t, tx, nil)
+	signedPsbt := testPsbt(t, tx, []byte{0x51})
+
+	// psbtParam reports whether the first parameter is the passed PSBT.
+	psbtParam := func(params []json.RawMessage, psbt string) bool {
+		var got string
+		return len(params) > 0 && json.Unmarshal(params[0], &got) == nil &&
+			got == psbt
+	}
+	unexpectedPsbt := sebtcjson.NewRPCError(sebtcjson.ErrRPCDeserialization,
+		"unexpected PSBT")
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		switch method {
+		case "walletcreatefundedpsbt":
+			return json.RawMessage(`{"psbt":"` + unsignedPsbt +
+				`","fee":0.0001,"changepos":1}`), nil
+		case "walletprocesspsbt":
+			if !psbtParam(params, unsignedPsbt) {
+				return nil, unexpectedPsbt
+			}
+			return json.RawMessage(`{"psbt":"` + signedPsbt +
+				`","complete":true}`), nil
+		case "combinepsbt":
+			return signedPsbt, nil
+		case "finalizepsbt":
+			if !psbtParam(params, signedPsbt) {
+				return nil, unexpectedPsbt
+			}
+			return json.RawMessage(`{"hex":"` +
+				hex.EncodeToString(signedBuf.Bytes()) +
+				`","complete":true}`), nil
+		case "decodepsbt":
+			if !psbtParam(params, unsignedPsbt) {
+				return nil, unexpectedPsbt
+			}
+			return json.RawMessage(`{"tx":{"txid":"` + tx.TxHash().String() +
+				`","version":1,"locktime":0,"vin":[{"txid":"` +
+				prevOut.Hash.String() + `","vout":0,"scriptSig":` +
+				`{"asm":"","hex":""},"sequence":4294967295}],"vout":[` +
+				`{"value":0.5,"n":0,"scriptPubKey":{"asm":"1","hex":"51","type":"nonstandard"}},` +
+				`{"value":0.4999,"n":1,"scriptPubKey":{"asm":"2","hex":"52","type":"nonstandard"}}]},` +
+				`"unknown":{},"inputs":[{"witness_utxo":{"amount":1.0,` +
+				`"scriptPubKey":{"asm":"0 0101","hex":"00140101","type":"witness_v0_keyhash"}}}],` +
+				`"outputs":[{},{"bip32_derivs":[{"pubkey":"02ab",` +
+				`"master_fingerprint":"d34db33f","path":"m/84'/1'/0'/1/0"}]}],` +
+				`"fee":0.0001}`), nil
+		case "analyzepsbt":
+			return json.RawMessage(`{"inputs":[{"has_utxo":true,` +
+				`"is_final":false,"next":"signer","missing":` +
+				`{"signatures":["0101"]}}],"estimated_vsize":141,` +
+				`"estimated_feerate":0.00070921,"fee":0.0001,` +
+				`"next":"signer"}`), nil
+		}
+		return nil, sebtcjson.ErrRPCMethodNotFound
+	})
+	defer closeTestClient(client, server)
+
+	inputs := []sebtcjson.TransactionInput{{
+		Txid: prevOut.Hash.String(),
+		Vout: prevOut.Index,
+	}}
+	outputs := map[ltcutil.Address]ltcutil.Amount{payee: 50000000}
+	options := &sebtcjson.WalletCreateFundedPsbtOpts{
+		ChangeAddress: sebtcjson.String(change.EncodeAddress()),
+		FeeRate:       sebtcjson.Float64(0.0002),
+		Replaceable:   sebtcjson.Bool(true),
+	}
+	funded, err := client.WalletCreateFundedPsbt(inputs, outputs, nil,
+		options, nil)
+	if err != nil {
+		t.Fatalf("WalletCreateFundedPsbt: unexpected error: %v", err)
+	}
+	wantFunded := &sebtcjson.WalletCreateFundedPsbtResult{
+		Psbt:      unsignedPsbt,
+		Fee:       0.0001,
+		ChangePos: 1,
+	}
+	if !reflect.DeepEqual(funded, wantFunded) {
+		t.Errorf("WalletCreateFundedPsbt: unexpected result - got %+v, "+
+			"want %+v", funded, wantFunded)
+	}
+	params := marshalParams(t, server.lastRequest("walletcreatefundedpsbt"))
+	wantParams := `[[{"txid":"` + prevOut.Hash.String() + `","vout":0}],` +
+		`{"` + payee.EncodeAddress() + `":0.50000000},null,` +
+		`{"changeAddress":"` + change.EncodeAddress() + `",` +
+		`"feeRate":0.0002,"replaceable":true}]`
+	if params != wantParams {
+		t.Errorf("WalletCreateFundedPsbt: unexpected params - got %s, "+
+			"want %s", params, wantParams)
+	}
+
+	processed, err := client.WalletProcessPsbt(funded.Psbt, nil, "", nil)
+	if err != nil {
+		t.Fatalf("WalletProcessPsbt: unexpected error: %v", err)
+	}
+	if !processed.Complete || processed.Psbt != signedPsbt {
+		t.Errorf("WalletProcessPsbt: unexpected result %+v", processed)
+	}
+
+	combined, err := client.CombinePsbt([]string{funded.Psbt, processed.Psbt})
+	if err != nil {
+		t.Fatalf("CombinePsbt: unexpected error: %v", err)
+	}
+	if combined != signedPsbt {
+		t.Errorf("CombinePsbt: unexpected result %s", combined)
+	}
+	params = marshalParams(t, server.lastRequest("combinepsbt"))
+	if want := `[["` + unsignedPsbt + `","` + signedPsbt + `"]]`; params != want {
+		t.Errorf("CombinePsbt: unexpected params - got %s, want %s",
+			params, want)
+	}
+
+	final, err := client.FinalizePsbt(combined, nil)
+	if err != nil {
+		t.Fatalf("FinalizePsbt: unexpected error: %v", err)
+	}
+	if !final.Complete || final.Psbt != "" {
+		t.Errorf("FinalizePsbt: unexpected result %+v", final)
+	}
+	serializedTx, err := hex.DecodeString(final.Hex)
+	if err != nil {
+		t.Fatalf("DecodeString: unexpected error: %v", err)
+	}
+	var finalTx wire.MsgTx
+	if err := finalTx.Deserialize(bytes.NewReader(serializedTx)); err != nil {
+		t.Fatalf("Deserialize: unexpected error: %v", err)
+	}
+	if finalTx.TxHash() != signed.TxHash() || len(finalTx.TxIn) != 1 ||
+		len(finalTx.TxOut) != 2 {
+
+		t.Errorf("FinalizePsbt: unexpected transaction %v", finalTx.TxHash())
+	}
+
+	decoded, err := client.DecodePsbt(funded.Psbt)
+	if err != nil {
+		t.Fatalf("DecodePsbt: unexpected error: %v", err)
+	}
+	if decoded.Tx.Txid != tx.TxHash().String() || len(decoded.Tx.Vin) != 1 ||
+		len(decoded.Tx.Vout) != 2 {
+
+		t.Errorf("DecodePsbt: unexpected transaction %+v", decoded.Tx)
+	}
+	if len(decoded.Inputs) != 1 || decoded.Inputs[0].WitnessUtxo == nil ||
+		decoded.Inputs[0].WitnessUtxo.Amount != 1 {
+
+		t.Errorf("DecodePsbt: unexpected inputs %+v", decoded.Inputs)
+	}
+	wantOutputs := []sebtcjson.DecodePsbtOutput{
+		{},
+		{Bip32Derivs: []sebtcjson.DecodePsbtBip32Deriv{{
+			PubKey:            "02ab",
+			MasterFingerprint: "d34db33f",
+			Path:              "m/84'/1'/0'/1/0",
+		}}},
+	}
+	if !reflect.DeepEqual(decoded.Outputs, wantOutputs) {
+		t.Errorf("DecodePsbt: unexpected outputs - got %+v, want %+v",
+			decoded.Outputs, wantOutputs)
+	}
+	if decoded.Fee == nil || *decoded.Fee != 0.0001 {
+		t.Errorf("DecodePsbt: unexpected fee %v", decoded.Fee)
+	}
+
+	analysis, err := client.AnalyzePsbt(funded.Psbt)
+	if err != nil {
+		t.Fatalf("AnalyzePsbt: unexpected error: %v", err)
+	}
+	vsize, feeRate, fee := int64(141), 0.00070921, 0.0001
+	wantAnalysis := &sebtcjson.AnalyzePsbtResult{
+		Inputs: []sebtcjson.AnalyzePsbtInput{{
+			HasUtxo: true,
+			Missing: &sebtcjson.AnalyzePsbtInputMissing{
+				Signatures: []string{"0101"},
+			},
+			Next: "signer",
+		}},
+		EstimatedVSize:   &vsize,
+		EstimatedFeeRate: &feeRate,
+		Fee:              &fee,
+		Next:             "signer",
+	}
+	if !reflect.DeepEqual(analysis, wantAnalysis) {
+		t.Errorf("AnalyzePsbt: unexpected result - got %+v, want %+v",
+			analysis, wantAnalysis)
+	}
+}
+
+// TestWalletProcessPsbtParams ensures the optional walletprocesspsbt
+// parameters are only sent when passed.
+func TestWalletProcessPsbtParams(t *testing.T) {
+	t.Parallel()
+
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		if method != "walletprocesspsbt" {
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		}
+		return json.RawMessage(`{"psbt":"cHNidP8=","complete":false}`), nil
+	})
+	defer closeTestClient(client, server)
+
+	tests := []struct {
+		name        string
+		sign        *bool
+		hashType    SigHashType
+		bip32Derivs *bool
+		params      string
+	}{
+		{
+			name:   "defaults",
+			params: `["cHNidP8="]`,
+		},
+		{
+			name:     "hash type",
+			sign:     sebtcjson.Bool(true),
+			hashType: SigHashSingleAnyoneCanPay,
+			params:   `["cHNidP8=",true,"SINGLE|ANYONECANPAY"]`,
+		},
+		{
+			name:        "no signing",
+			sign:        sebtcjson.Bool(false),
+			bip32Derivs: sebtcjson.Bool(false),
+			params:      `["cHNidP8=",false,null,false]`,
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		_, err := client.WalletProcessPsbt("cHNidP8=", test.sign,
+			test.hashType, test.bip32Derivs)
+		if err != nil {
+			t.Errorf("Test #%d (%s) unexpected error: %v", i,
+				test.name, err)
+			continue
+		}
+		params := marshalParams(t, server.lastRequest("walletprocesspsbt"))
+		if params != test.params {
+			t.Errorf("Test #%d (%s) unexpected params - got %s, "+
+				"want %s", i, test.name, params, test.params)
+		}
+	}
+}
@@ -6,6 +6,7 @@
 package selrpcclient
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"github.com/zzpu/lib-bitcore/sebtcjson"
@@ -27,6 +28,17 @@ func (r FutureRawResult) Receive() (json.RawMessage, error) {
 //
 // See RawRequest for the blocking version and more details.
 func (c *Client) RawRequestAsync(method string, params []json.RawMessage) FutureRawResult {
+	return c.RawRequestContextAsync(context.Background(), method, params)
+}
+
+// RawRequestContextAsync returns an instance of a type that can be used to get
+// the result of a custom RPC request at some future time by invoking the
+// Receive function on the returned instance.
+//
+// See RawRequestContext for the blocking version and more details.
+func (c *Client) RawRequestContextAsync(ctx context.Context, method string,
+	params []json.RawMessage) FutureRawResult {
+
 	// Method may not be empty.
 	if method == "" {
 		return newFutureError(errors.New("no method"))
@@ -55,6 +67,7 @@ func (c *Client) RawRequestAsync(method string, params []json.RawMessage) Future
 	}
 
 	// Generate the request and send it along with a channel to respond on.
+	ctx, cancel := c.requestContext(ctx)
 	responseChan := make(chan *response, 1)
 	jReq := &jsonRequest{
 		id:             id,
@@ -62,10 +75,12 @@ func (c *Client) RawRequestAsync(method string, params []json.RawMessage) Future
 		cmd:            nil,
 		marshalledJSON: marshalledJSON,
 		responseChan:   responseChan,
+		ctx:            ctx,
+		cancel:         cancel,
 	}
 	c.sendRequest(jReq)
 
-	return responseChan
+	return c.watchContext(ctx, jReq)
 }
 
 // RawRequest allows the caller to send a raw or custom request to the server.
@@ -76,3 +91,24 @@ func (c *Client) RawRequestAsync(method string, params []json.RawMessage) Future
 func (c *Client) RawRequest(method string, params []json.RawMessage) (json.RawMessage, error) {
 	return c.RawRequestAsync(method, params).Receive()
 }
+
+// RawRequestContext is the same as RawRequest except the passed context is
+// handed to the RequestHeaders hook of the connection configuration, which
+// allows request scoped headers to be derived from the context values.  The
+// request is also abandoned, returning the error of the context, once the
+// context is done before the server answers.
+func (c *Client) RawRequestContext(ctx context.Context, method string,
+	params []json.RawMessage) (json.RawMessage, error) {
+
+	return c.RawRequestContextAsync(ctx, method, params).Receive()
+}
+
+// SendCmdCtx sends the passed command, which must be a command registered with
+// sebtcjson such as one returned by sebtcjson.NewGetBestBlockCmd, and returns
+// the raw result.  It allows any command to be sent with a context, including
+// those without a Ctx variant of their own.  Once the context is done before
+// the server answers, the request is abandoned and the error of the context is
+// returned.  The result is left to be unmarshalled by the caller.
+func (c *Client) SendCmdCtx(ctx context.Context, cmd interface{}) (json.RawMessage, error) {
+	return receiveFuture(c.sendCmdCtx(ctx, cmd))
+}
@@ -7,12 +7,15 @@ package selrpcclient
 
 import (
 	"bytes"
+	"context"
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
 	"github.com/ltcsuite/ltcd/wire"
 	"github.com/ltcsuite/ltcutil"
 	"github.com/zzpu/lib-bitcore/sebtcjson"
+	"strings"
 )
 
 // SigHashType enumerates the available signature hashing types that the
@@ -114,6 +117,18 @@ func (c *Client) GetRawTransaction(txHash *chainhash.Hash) (*ltcutil.Tx, error)
 	return c.GetRawTransactionAsync(txHash).Receive()
 }
 
+// GetRawTransactionCtx is like GetRawTransaction, but returns the error of the
+// passed context once it is done before the transaction is received.
+func (c *Client) GetRawTransactionCtx(ctx context.Context, txHash *chainhash.Hash) (*ltcutil.Tx, error) {
+	hash := ""
+	if txHash != nil {
+		hash = txHash.String()
+	}
+
+	cmd := sebtcjson.NewGetRawTransactionCmd(hash, sebtcjson.Int(0))
+	return FutureGetRawTransactionResult(c.sendCmdCtx(ctx, cmd)).Receive()
+}
+
 // FutureGetRawTransactionVerboseResult is a future promise to deliver the
 // result of a GetRawTransactionVerboseAsync RPC invocation (or an applicable
 // error).
@@ -160,6 +175,31 @@ func (c *Client) GetRawTransactionVerbose(txHash *chainhash.Hash) (*sebtcjson.Tx
 	return c.GetRawTransactionVerboseAsync(txHash).Receive()
 }
 
+// GetRawTransactionVerbose2Async returns an instance of a type that can be used
+// to get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See GetRawTransactionVerbose2 for the blocking version and more details.
+func (c *Client) GetRawTransactionVerbose2Async(txHash *chainhash.Hash) FutureGetRawTransactionVerboseResult {
+	hash := ""
+	if txHash != nil {
+		hash = txHash.String()
+	}
+
+	cmd := sebtcjson.NewGetRawTransactionCmd(hash, sebtcjson.Int(2))
+	return c.sendCmd(cmd)
+}
+
+// GetRawTransactionVerbose2 returns information about a transaction given its
+// hash like GetRawTransactionVerbose, but also includes the fee of the
+// transaction and the PrevOut of each input describing the output it spends.
+//
+// NOTE: This requires Bitcoin Core 23.0 or later.  Older servers treat the
+// verbosity as GetRawTransactionVerbose does and leave the additions unset.
+func (c *Client) GetRawTransactionVerbose2(txHash *chainhash.Hash) (*sebtcjson.TxRawResult, error) {
+	return c.GetRawTransactionVerbose2Async(txHash).Receive()
+}
+
 // FutureDecodeRawTransactionResult is a future promise to deliver the result
 // of a DecodeRawTransactionAsync RPC invocation (or an applicable error).
 type FutureDecodeRawTransactionResult chan *response
@@ -267,7 +307,7 @@ type FutureSendRawTransactionResult chan *response
 func (r FutureSendRawTransactionResult) Receive() (*chainhash.Hash, error) {
 	res, err := receiveFuture(r)
 	if err != nil {
-		return nil, err
+		return nil, newTxRejectedError(err)
 	}
 
 	// Unmarshal result as a string.
@@ -302,10 +342,67 @@ func (c *Client) SendRawTransactionAsync(tx *wire.MsgTx, allowHighFees bool) Fut
 
 // SendRawTransaction submits the encoded transaction to the server which will
 // then relay it to the network.
+//
+// When the server rejects the transaction, the returned error is a
+// *TxRejectedError carrying the reject reason parsed from the message.  It
+// wraps the *sebtcjson.RPCError returned by the server.
 func (c *Client) SendRawTransaction(tx *wire.MsgTx, allowHighFees bool) (*chainhash.Hash, error) {
 	return c.SendRawTransactionAsync(tx, allowHighFees).Receive()
 }
 
+// FutureTestMempoolAcceptResult is a future promise to deliver the result of a
+// TestMempoolAcceptAsync RPC invocation (or an applicable error).
+type FutureTestMempoolAcceptResult chan *response
+
+// Receive waits for the response promised by the future and returns whether
+// each of the transactions would be accepted to the mempool.
+func (r FutureTestMempoolAcceptResult) Receive() ([]sebtcjson.TestMempoolAcceptResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as an array of testmempoolaccept result objects.
+	var results []sebtcjson.TestMempoolAcceptResult
+	err = json.Unmarshal(res, &results)
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// TestMempoolAcceptAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See TestMempoolAccept for the blocking version and more details.
+func (c *Client) TestMempoolAcceptAsync(txs []*wire.MsgTx, maxFeeRate *float64) FutureTestMempoolAcceptResult {
+	rawTxns := make([]string, 0, len(txs))
+	for _, tx := range txs {
+		// Serialize the transaction and convert to hex string.
+		buf := bytes.NewBuffer(make([]byte, 0, tx.SerializeSize()))
+		if err := tx.Serialize(buf); err != nil {
+			return newFutureError(err)
+		}
+		rawTxns = append(rawTxns, hex.EncodeToString(buf.Bytes()))
+	}
+
+	cmd := sebtcjson.NewTestMempoolAcceptCmd(rawTxns, maxFeeRate)
+	return c.sendCmd(cmd)
+}
+
+// TestMempoolAccept returns whether each of the passed transactions would be
+// accepted to the mempool of the server, without adding or relaying them.
+// Transactions paying a fee rate above maxFeeRate, in BTC/kvB, are rejected;
+// the server default applies when it is nil.  The results are in the order of
+// the transactions and carry the reason of rejected ones.
+//
+// NOTE: Servers before version 22.0 only accept a single transaction.
+func (c *Client) TestMempoolAccept(txs []*wire.MsgTx, maxFeeRate *float64) ([]sebtcjson.TestMempoolAcceptResult, error) {
+	return c.TestMempoolAcceptAsync(txs, maxFeeRate).Receive()
+}
+
 // FutureSignRawTransactionResult is a future promise to deliver the result
 // of one of the SignRawTransactionAsync family of RPC invocations (or an
 // applicable error).
@@ -505,6 +602,199 @@ func (c *Client) SignRawTransaction4(tx *wire.MsgTx,
 		hashType).Receive()
 }
 
+// checkPrevTxs ensures every input of the passed transaction spends an output
+// described by one of the passed previous transaction entries.  The returned
+// error lists each outpoint which has no corresponding entry.
+func checkPrevTxs(tx *wire.MsgTx, prevTxs []sebtcjson.RawTxWitnessInput) error {
+	known := make(map[wire.OutPoint]struct{}, len(prevTxs))
+	for _, prevTx := range prevTxs {
+		hash, err := chainhash.NewHashFromStr(prevTx.Txid)
+		if err != nil {
+			return fmt.Errorf("invalid prevtxs txid %q: %v",
+				prevTx.Txid, err)
+		}
+		known[*wire.NewOutPoint(hash, prevTx.Vout)] = struct{}{}
+	}
+
+	var missing []string
+	for _, txIn := range tx.TxIn {
+		if _, ok := known[txIn.PreviousOutPoint]; !ok {
+			missing = append(missing, txIn.PreviousOutPoint.String())
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing prevtxs entries for inputs: %s",
+			strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// SignRawTransactionWithKeyAsync returns an instance of a type that can be used
+// to get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See SignRawTransactionWithKey for the blocking version and more details.
+func (c *Client) SignRawTransactionWithKeyAsync(tx *wire.MsgTx,
+	privKeysWIF []string, prevTxs []sebtcjson.RawTxWitnessInput,
+	hashType SigHashType) FutureSignRawTransactionResult {
+
+	txHex := ""
+	if tx != nil {
+		if err := checkPrevTxs(tx, prevTxs); err != nil {
+			return newFutureError(err)
+		}
+
+		// Serialize the transaction and convert to hex string.
+		buf := bytes.NewBuffer(make([]byte, 0, tx.SerializeSize()))
+		if err := tx.Serialize(buf); err != nil {
+			return newFutureError(err)
+		}
+		txHex = hex.EncodeToString(buf.Bytes())
+	}
+
+	var sigHashType *string
+	if hashType != "" {
+		sigHashType = sebtcjson.String(string(hashType))
+	}
+	cmd := sebtcjson.NewSignRawTransactionWithKeyCmd(txHex, privKeysWIF,
+		&prevTxs, sigHashType)
+	return c.sendCmd(cmd)
+}
+
+// SignRawTransactionWithKey signs inputs for the passed transaction using only
+// the passed private keys, which must be in wallet import format (WIF), along
+// with the specified signature hash type.
+//
+// Each input of the transaction must have a corresponding entry in prevTxs
+// describing the output it spends.  The entries are checked locally before the
+// RPC is issued and an error listing the inputs without an entry is returned
+// when any are missing.  Entries for segwit outputs must include their amount,
+// along with the witness script for P2WSH outputs and the redeem script for
+// outputs nested in P2SH.
+//
+// The signature hash type defaults to SigHashAll when it is empty.
+func (c *Client) SignRawTransactionWithKey(tx *wire.MsgTx,
+	privKeysWIF []string, prevTxs []sebtcjson.RawTxWitnessInput,
+	hashType SigHashType) (*wire.MsgTx, bool, error) {
+
+	return c.SignRawTransactionWithKeyAsync(tx, privKeysWIF, prevTxs,
+		hashType).Receive()
+}
+
+// FutureSignRawTransactionWithWalletResult is a future promise to deliver the
+// result of one of the SignRawTransactionWithWalletAsync family of RPC
+// invocations (or an applicable error).
+type FutureSignRawTransactionWithWalletResult chan *response
+
+// Receive waits for the response promised by the future and returns the
+// signed transaction, whether or not all inputs are now signed, and the errors
+// reported for the inputs which could not be signed.
+func (r FutureSignRawTransactionWithWalletResult) Receive() (*wire.MsgTx, bool, []sebtcjson.SignRawTransactionError, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, false, nil, err
+	}
+
+	// Unmarshal as a signrawtransactionwithwallet result.
+	var signRawTxResult sebtcjson.SignRawTransactionWithWalletResult
+	err = json.Unmarshal(res, &signRawTxResult)
+	if err != nil {
+		return nil, false, nil, err
+	}
+
+	// Decode the serialized transaction hex to raw bytes.
+	serializedTx, err := hex.DecodeString(signRawTxResult.Hex)
+	if err != nil {
+		return nil, false, nil, err
+	}
+
+	// Deserialize the transaction and return it.
+	var msgTx wire.MsgTx
+	if err := msgTx.Deserialize(bytes.NewReader(serializedTx)); err != nil {
+		return nil, false, nil, err
+	}
+
+	return &msgTx, signRawTxResult.Complete, signRawTxResult.Errors, nil
+}
+
+// SignRawTransactionWithWalletAsync returns an instance of a type that can be
+// used to get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See SignRawTransactionWithWallet for the blocking version and more details.
+func (c *Client) SignRawTransactionWithWalletAsync(tx *wire.MsgTx) FutureSignRawTransactionWithWalletResult {
+	txHex := ""
+	if tx != nil {
+		// Serialize the transaction and convert to hex string.
+		buf := bytes.NewBuffer(make([]byte, 0, tx.SerializeSize()))
+		if err := tx.Serialize(buf); err != nil {
+			return newFutureError(err)
+		}
+		txHex = hex.EncodeToString(buf.Bytes())
+	}
+
+	cmd := sebtcjson.NewSignRawTransactionWithWalletCmd(txHex, nil, nil)
+	return c.sendCmd(cmd)
+}
+
+// SignRawTransactionWithWallet signs inputs for the passed transaction with the
+// keys of the wallet and returns the signed transaction, whether or not all
+// inputs are now signed, and the errors reported for the inputs which could not
+// be signed.
+//
+// This is the replacement of SignRawTransaction for servers which split the
+// signrawtransaction command, so the outputs spent by the transaction must be
+// known to the server.  See SignRawTransactionWithWallet2 to pass the outputs
+// which are not known or to use a different signature hash type.
+func (c *Client) SignRawTransactionWithWallet(tx *wire.MsgTx) (*wire.MsgTx, bool, []sebtcjson.SignRawTransactionError, error) {
+	return c.SignRawTransactionWithWalletAsync(tx).Receive()
+}
+
+// SignRawTransactionWithWallet2Async returns an instance of a type that can be
+// used to get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See SignRawTransactionWithWallet2 for the blocking version and more details.
+func (c *Client) SignRawTransactionWithWallet2Async(tx *wire.MsgTx,
+	prevTxs []sebtcjson.RawTxWitnessInput,
+	hashType SigHashType) FutureSignRawTransactionWithWalletResult {
+
+	txHex := ""
+	if tx != nil {
+		// Serialize the transaction and convert to hex string.
+		buf := bytes.NewBuffer(make([]byte, 0, tx.SerializeSize()))
+		if err := tx.Serialize(buf); err != nil {
+			return newFutureError(err)
+		}
+		txHex = hex.EncodeToString(buf.Bytes())
+	}
+
+	var sigHashType *string
+	if hashType != "" {
+		sigHashType = sebtcjson.String(string(hashType))
+	}
+	cmd := sebtcjson.NewSignRawTransactionWithWalletCmd(txHex, &prevTxs,
+		sigHashType)
+	return c.sendCmd(cmd)
+}
+
+// SignRawTransactionWithWallet2 signs inputs for the passed transaction with
+// the keys of the wallet using the specified signature hash type, given the
+// list of information about the outputs spent by the transaction.
+//
+// The only outputs that need to be specified are ones the RPC server does not
+// already know, so the list can be nil if the RPC server already knows them
+// all.  Segwit outputs the server does not know must include their amount.
+//
+// The signature hash type defaults to SigHashAll when it is empty.
+func (c *Client) SignRawTransactionWithWallet2(tx *wire.MsgTx,
+	prevTxs []sebtcjson.RawTxWitnessInput,
+	hashType SigHashType) (*wire.MsgTx, bool, []sebtcjson.SignRawTransactionError, error) {
+
+	return c.SignRawTransactionWithWallet2Async(tx, prevTxs,
+		hashType).Receive()
+}
+
 // FutureSearchRawTransactionsResult is a future promise to deliver the result
 // of the SearchRawTransactionsAsync RPC invocation (or an applicable error).
 type FutureSearchRawTransactionsResult chan *response
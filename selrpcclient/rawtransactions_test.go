@@ -0,0 +1,494 @@
+// Copyright (c) 2018 The box developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package selrpcclient
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/wire"
+	"github.com/zzpu/lib-bitcore/sebtcjson"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestSignRawTransactionWithKeyMissingPrevTx ensures inputs without a
+// corresponding prevtxs entry are reported locally without issuing the RPC.
+func TestSignRawTransactionWithKeyMissingPrevTx(t *testing.T) {
+	t.Parallel()
+
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		return nil, sebtcjson.ErrRPCMethodNotFound
+	})
+	defer closeTestClient(client, server)
+
+	known := wire.NewOutPoint(&chainhash.Hash{0x01}, 0)
+	missing := wire.NewOutPoint(&chainhash.Hash{0x02}, 3)
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxIn(wire.NewTxIn(known, nil, nil))
+	tx.AddTxIn(wire.NewTxIn(missing, nil, nil))
+
+	prevTxs := []sebtcjson.RawTxWitnessInput{{
+		Txid:         known.Hash.String(),
+		Vout:         known.Index,
+		ScriptPubKey: "00",
+	}}
+	_, _, err := client.SignRawTransactionWithKey(tx, []string{"key"},
+		prevTxs, SigHashAll)
+	if err == nil {
+		t.Fatalf("expected error for missing prevtx")
+	}
+	if !strings.Contains(err.Error(), missing.String()) {
+		t.Errorf("error %q does not list missing input %v", err, missing)
+	}
+	if strings.Contains(err.Error(), known.String()) {
+		t.Errorf("error %q lists known input %v", err, known)
+	}
+	if n := server.calls("signrawtransactionwithkey"); n != 0 {
+		t.Errorf("unexpected signrawtransactionwithkey calls - got %d, "+
+			"want %d", n, 0)
+	}
+}
+
+// TestSignRawTransactionWithKey ensures the transaction, keys, previous outputs
+// and hash type are passed to signrawtransactionwithkey and that the signed
+// transaction is returned along with whether it is complete.
+func TestSignRawTransactionWithKey(t *testing.T) {
+	t.Parallel()
+
+	prevOut := wire.NewOutPoint(&chainhash.Hash{0x01}, 0)
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxIn(wire.NewTxIn(prevOut, nil, nil))
+	tx.AddTxOut(wire.NewTxOut(1000, []byte{0x51}))
+
+	signed := tx.Copy()
+	signed.TxIn[0].SignatureScript = []byte{0x01, 0x02}
+	var buf bytes.Buffer
+	if err := signed.Serialize(&buf); err != nil {
+		t.Fatalf("Serialize: unexpected error: %v", err)
+	}
+
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		if method != "signrawtransactionwithkey" {
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		}
+		return &sebtcjson.SignRawTransactionWithKeyResult{
+			Hex:      hex.EncodeToString(buf.Bytes()),
+			Complete: false,
+			Errors: []sebtcjson.SignRawTransactionError{{
+				TxID:  prevOut.Hash.String(),
+				Vout:  prevOut.Index,
+				Error: "Operation not valid with the current stack size",
+			}},
+		}, nil
+	})
+	defer closeTestClient(client, server)
+
+	prevTxs := []sebtcjson.RawTxWitnessInput{{
+		Txid:         prevOut.Hash.String(),
+		Vout:         prevOut.Index,
+		ScriptPubKey: "51",
+	}}
+	got, complete, err := client.SignRawTransactionWithKey(tx,
+		[]string{"key"}, prevTxs, SigHashAll)
+	if err != nil {
+		t.Fatalf("SignRawTransactionWithKey: unexpected error: %v", err)
+	}
+	if complete {
+		t.Errorf("incomplete signature reported as complete")
+	}
+	if !reflect.DeepEqual(got, signed) {
+		t.Errorf("unexpected signed transaction - got %v, want %v",
+			got, signed)
+	}
+
+	var unsigned bytes.Buffer
+	if err := tx.Serialize(&unsigned); err != nil {
+		t.Fatalf("Serialize: unexpected error: %v", err)
+	}
+	params := marshalParams(t, server.lastRequest("signrawtransactionwithkey"))
+	want := `["` + hex.EncodeToString(unsigned.Bytes()) + `",["key"],` +
+		`[{"txid":"` + prevOut.Hash.String() + `","vout":0,` +
+		`"scriptPubKey":"51"}],"ALL"]`
+	if params != want {
+		t.Errorf("unexpected params - got %s, want %s", params, want)
+	}
+}
+
+// TestSignRawTransactionWithWallet ensures a partially signed transaction
+// returned by signrawtransactionwithwallet is decoded along with the errors of
+// the inputs which could not be signed, and that the previous outputs and hash
+// type are only passed by SignRawTransactionWithWallet2, which leaves the
+// server default in place for an empty hash type.
+func TestSignRawTransactionWithWallet(t *testing.T) {
+	t.Parallel()
+
+	ours := wire.NewOutPoint(&chainhash.Hash{0x01}, 0)
+	theirs := wire.NewOutPoint(&chainhash.Hash{0x02}, 1)
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxIn(wire.NewTxIn(ours, nil, nil))
+	tx.AddTxIn(wire.NewTxIn(theirs, nil, nil))
+	tx.AddTxOut(wire.NewTxOut(1000, []byte{0x51}))
+
+	// Only the input spending an output of the wallet is signed.
+	signed := tx.Copy()
+	signed.TxIn[0].SignatureScript = []byte{0x01, 0x02}
+	var buf bytes.Buffer
+	if err := signed.Serialize(&buf); err != nil {
+		t.Fatalf("Serialize: unexpected error: %v", err)
+	}
+	wantErrors := []sebtcjson.SignRawTransactionError{{
+		TxID:      theirs.Hash.String(),
+		Vout:      theirs.Index,
+		ScriptSig: "",
+		Sequence:  wire.MaxTxInSequenceNum,
+		Error:     "Input not found or already spent",
+	}}
+
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		if method != "signrawtransactionwithwallet" {
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		}
+		return json.RawMessage(`{"hex":"` + hex.EncodeToString(buf.Bytes()) +
+			`","complete":false,"errors":[{"txid":"` +
+			theirs.Hash.String() + `","vout":1,"witness":[],` +
+			`"scriptSig":"","sequence":4294967295,` +
+			`"error":"Input not found or already spent"}]}`), nil
+	})
+	defer closeTestClient(client, server)
+
+	var unsigned bytes.Buffer
+	if err := tx.Serialize(&unsigned); err != nil {
+		t.Fatalf("Serialize: unexpected error: %v", err)
+	}
+	txHex := hex.EncodeToString(unsigned.Bytes())
+	prevTxs := []sebtcjson.RawTxWitnessInput{{
+		Txid:         theirs.Hash.String(),
+		Vout:         theirs.Index,
+		ScriptPubKey: "51",
+		Amount:       sebtcjson.Float64(0.5),
+	}}
+
+	tests := []struct {
+		name   string
+		sign   func() (*wire.MsgTx, bool, []sebtcjson.SignRawTransactionError, error)
+		params string
+	}{
+		{
+			name: "wallet",
+			sign: func() (*wire.MsgTx, bool, []sebtcjson.SignRawTransactionError, error) {
+				return client.SignRawTransactionWithWallet(tx)
+			},
+			params: `["` + txHex + `"]`,
+		},
+		{
+			name: "prevtxs and hash type",
+			sign: func() (*wire.MsgTx, bool, []sebtcjson.SignRawTransactionError, error) {
+				return client.SignRawTransactionWithWallet2(tx, prevTxs,
+					SigHashAllAnyoneCanPay)
+			},
+			params: `["` + txHex + `",[{"txid":"` + theirs.Hash.String() +
+				`","vout":1,"scriptPubKey":"51","amount":0.5}],` +
+				`"ALL|ANYONECANPAY"]`,
+		},
+		{
+			name: "prevtxs and default hash type",
+			sign: func() (*wire.MsgTx, bool, []sebtcjson.SignRawTransactionError, error) {
+				return client.SignRawTransactionWithWallet2(tx, prevTxs, "")
+			},
+			params: `["` + txHex + `",[{"txid":"` + theirs.Hash.String() +
+				`","vout":1,"scriptPubKey":"51","amount":0.5}]]`,
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		got, complete, signErrors, err := test.sign()
+		if err != nil {
+			t.Errorf("Test #%d (%s) unexpected error: %v", i,
+				test.name, err)
+			continue
+		}
+		if complete {
+			t.Errorf("Test #%d (%s) incomplete signature reported as "+
+				"complete", i, test.name)
+		}
+		if got.TxHash() != signed.TxHash() {
+			t.Errorf("Test #%d (%s) unexpected signed transaction - "+
+				"got %v, want %v", i, test.name, got.TxHash(),
+				signed.TxHash())
+		}
+		if !reflect.DeepEqual(signErrors, wantErrors) {
+			t.Errorf("Test #%d (%s) unexpected errors - got %+v, "+
+				"want %+v", i, test.name, signErrors, wantErrors)
+		}
+		params := marshalParams(t, server.lastRequest("signrawtransactionwithwallet"))
+		if params != test.params {
+			t.Errorf("Test #%d (%s) unexpected params - got %s, "+
+				"want %s", i, test.name, params, test.params)
+		}
+	}
+}
+
+// TestSignRawTransactionWithKeyWitness ensures the scripts and amounts of
+// previous segwit outputs are passed to signrawtransactionwithkey and that an
+// empty hash type leaves the server default in place.
+func TestSignRawTransactionWithKeyWitness(t *testing.T) {
+	t.Parallel()
+
+	nested := wire.NewOutPoint(&chainhash.Hash{0x01}, 0)
+	native := wire.NewOutPoint(&chainhash.Hash{0x02}, 1)
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxIn(wire.NewTxIn(nested, nil, nil))
+	tx.AddTxIn(wire.NewTxIn(native, nil, nil))
+	tx.AddTxOut(wire.NewTxOut(1000, []byte{0x51}))
+	var buf bytes.Buffer
+	if err := tx.Serialize(&buf); err != nil {
+		t.Fatalf("Serialize: unexpected error: %v", err)
+	}
+	txHex := hex.EncodeToString(buf.Bytes())
+
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		if method != "signrawtransactionwithkey" {
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		}
+		return &sebtcjson.SignRawTransactionWithKeyResult{
+			Hex:      txHex,
+			Complete: true,
+		}, nil
+	})
+	defer closeTestClient(client, server)
+
+	prevTxs := []sebtcjson.RawTxWitnessInput{
+		{
+			Txid:          nested.Hash.String(),
+			Vout:          nested.Index,
+			ScriptPubKey:  "a914",
+			RedeemScript:  sebtcjson.String("0020"),
+			WitnessScript: sebtcjson.String("51"),
+			Amount:        sebtcjson.Float64(0.25),
+		},
+		{
+			Txid:         native.Hash.String(),
+			Vout:         native.Index,
+			ScriptPubKey: "0014",
+			Amount:       sebtcjson.Float64(1.5),
+		},
+	}
+	_, complete, err := client.SignRawTransactionWithKey(tx,
+		[]string{"key"}, prevTxs, "")
+	if err != nil {
+		t.Fatalf("SignRawTransactionWithKey: unexpected error: %v", err)
+	}
+	if !complete {
+		t.Errorf("complete signature reported as incomplete")
+	}
+
+	params := marshalParams(t, server.lastRequest("signrawtransactionwithkey"))
+	want := `["` + txHex + `",["key"],[` +
+		`{"txid":"` + nested.Hash.String() + `","vout":0,` +
+		`"scriptPubKey":"a914","redeemScript":"0020",` +
+		`"witnessScript":"51","amount":0.25},` +
+		`{"txid":"` + native.Hash.String() + `","vout":1,` +
+		`"scriptPubKey":"0014","amount":1.5}]]`
+	if params != want {
+		t.Errorf("unexpected params - got %s, want %s", params, want)
+	}
+}
+
+// TestCheckPrevTxsTxidFormat ensures prevtxs entries are matched against the
+// transaction inputs regardless of the case of their txids and that invalid
+// txids are rejected.
+func TestCheckPrevTxsTxidFormat(t *testing.T) {
+	t.Parallel()
+
+	outpoint := wire.NewOutPoint(&chainhash.Hash{0xab, 0xcd}, 1)
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxIn(wire.NewTxIn(outpoint, nil, nil))
+
+	prevTxs := []sebtcjson.RawTxWitnessInput{{
+		Txid: strings.ToUpper(outpoint.Hash.String()),
+		Vout: outpoint.Index,
+	}}
+	if err := checkPrevTxs(tx, prevTxs); err != nil {
+		t.Errorf("unexpected error for uppercase txid: %v", err)
+	}
+
+	prevTxs[0].Txid = "zz"
+	if err := checkPrevTxs(tx, prevTxs); err == nil {
+		t.Errorf("expected error for invalid txid")
+	}
+}
+
+// TestGetRawTransactionVerbose2 ensures verbosity 2 is requested and that the
+// fee and the prevout of each input are decoded.
+func TestGetRawTransactionVerbose2(t *testing.T) {
+	t.Parallel()
+
+	txHash := chainhash.Hash{0x01}
+	payload := json.RawMessage(`{
+		"txid": "` + txHash.String() + `",
+		"hash": "` + txHash.String() + `",
+		"version": 2,
+		"size": 110,
+		"vsize": 110,
+		"locktime": 0,
+		"vin": [{
+			"txid": "` + chainhash.Hash{0x02}.String() + `",
+			"vout": 1,
+			"scriptSig": {"asm": "", "hex": ""},
+			"sequence": 4294967293,
+			"prevout": {
+				"generated": true,
+				"height": 120,
+				"value": 0.5,
+				"scriptPubKey": {
+					"asm": "0 0000000000000000000000000000000000000000",
+					"hex": "00140000000000000000000000000000000000000000",
+					"type": "witness_v0_keyhash"
+				}
+			}
+		}],
+		"vout": [],
+		"fee": 0.0001,
+		"hex": "00"
+	}`)
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		if method != "getrawtransaction" {
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		}
+		return payload, nil
+	})
+	defer closeTestClient(client, server)
+
+	result, err := client.GetRawTransactionVerbose2(&txHash)
+	if err != nil {
+		t.Fatalf("GetRawTransactionVerbose2: unexpected error: %v", err)
+	}
+	if got, want := marshalParams(t, server.lastRequest("getrawtransaction")), `["`+txHash.String()+`",2]`; got != want {
+		t.Errorf("GetRawTransactionVerbose2: unexpected params - got %s, "+
+			"want %s", got, want)
+	}
+	if result.Fee != 0.0001 {
+		t.Errorf("GetRawTransactionVerbose2: unexpected fee - got %v, "+
+			"want 0.0001", result.Fee)
+	}
+	if len(result.Vin) != 1 {
+		t.Fatalf("GetRawTransactionVerbose2: got %d inputs, want 1",
+			len(result.Vin))
+	}
+	want := &sebtcjson.TxInPrevOut{
+		Generated: true,
+		Height:    120,
+		Value:     0.5,
+		ScriptPubKey: sebtcjson.ScriptPubKeyResult{
+			Asm:  "0 0000000000000000000000000000000000000000",
+			Hex:  "00140000000000000000000000000000000000000000",
+			Type: "witness_v0_keyhash",
+		},
+	}
+	if !reflect.DeepEqual(result.Vin[0].PrevOut, want) {
+		t.Errorf("GetRawTransactionVerbose2: unexpected prevout - got "+
+			"%+v, want %+v", result.Vin[0].PrevOut, want)
+	}
+}
+
+// TestTestMempoolAccept ensures the transactions are sent as an array of raw
+// transactions along with the maximum fee rate, and that the results of
+// accepted and rejected transactions are decoded, including those of servers
+// which do not return the virtual size and the fees.
+func TestTestMempoolAccept(t *testing.T) {
+	t.Parallel()
+
+	accepted := wire.NewMsgTx(wire.TxVersion)
+	accepted.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: chainhash.Hash{0x01}}, nil, nil))
+	accepted.AddTxOut(wire.NewTxOut(1000, []byte{0x51}))
+	rejected := wire.NewMsgTx(wire.TxVersion)
+	rejected.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: chainhash.Hash{0x02}}, nil, nil))
+	rejected.AddTxOut(wire.NewTxOut(1, []byte{0x51}))
+
+	tests := []struct {
+		name    string
+		payload json.RawMessage
+		want    []sebtcjson.TestMempoolAcceptResult
+	}{
+		{
+			name: "fees and vsize",
+			payload: json.RawMessage(`[{"txid":"` + accepted.TxHash().String() +
+				`","allowed":true,"vsize":60,"fees":{"base":0.0001}},` +
+				`{"txid":"` + rejected.TxHash().String() +
+				`","allowed":false,"reject-reason":"dust"}]`),
+			want: []sebtcjson.TestMempoolAcceptResult{
+				{
+					TxID:    accepted.TxHash().String(),
+					Allowed: true,
+					Vsize:   sebtcjson.Int32(60),
+					Fees:    &sebtcjson.TestMempoolAcceptFees{Base: 0.0001},
+				},
+				{
+					TxID:         rejected.TxHash().String(),
+					RejectReason: "dust",
+				},
+			},
+		},
+		{
+			name: "old server",
+			payload: json.RawMessage(`[{"txid":"` + accepted.TxHash().String() +
+				`","allowed":true},` +
+				`{"txid":"` + rejected.TxHash().String() +
+				`","allowed":false,"reject-reason":"dust"}]`),
+			want: []sebtcjson.TestMempoolAcceptResult{
+				{
+					TxID:    accepted.TxHash().String(),
+					Allowed: true,
+				},
+				{
+					TxID:         rejected.TxHash().String(),
+					RejectReason: "dust",
+				},
+			},
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		payload := test.payload
+		client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+			if method != "testmempoolaccept" {
+				return nil, sebtcjson.ErrRPCMethodNotFound
+			}
+			return payload, nil
+		})
+
+		txs := []*wire.MsgTx{accepted, rejected}
+		results, err := client.TestMempoolAccept(txs, sebtcjson.Float64(0.1))
+		if err != nil {
+			closeTestClient(client, server)
+			t.Errorf("Test #%d (%s) unexpected error: %v", i,
+				test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(results, test.want) {
+			t.Errorf("Test #%d (%s) unexpected results - got %+v, "+
+				"want %+v", i, test.name, results, test.want)
+		}
+
+		var rawTxns []string
+		for _, tx := range txs {
+			var buf bytes.Buffer
+			tx.Serialize(&buf)
+			rawTxns = append(rawTxns, `"`+hex.EncodeToString(buf.Bytes())+`"`)
+		}
+		params := marshalParams(t, server.lastRequest("testmempoolaccept"))
+		want := `[[` + strings.Join(rawTxns, ",") + `],0.1]`
+		if params != want {
+			t.Errorf("Test #%d (%s) unexpected params - got %s, "+
+				"want %s", i, test.name, params, want)
+		}
+		closeTestClient(client, server)
+	}
+}
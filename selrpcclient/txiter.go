@@ -0,0 +1,95 @@
+// Copyright (c) 2018 The box developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package selrpcclient
+
+import (
+	"github.com/ltcsuite/ltcutil"
+	"github.com/zzpu/lib-bitcore/sebtcjson"
+)
+
+const (
+	// defaultTxIterPageSize is the number of transactions requested per
+	// page by an address transactions iterator when no page size is passed.
+	defaultTxIterPageSize = 100
+)
+
+// AddressTransactionsIterator steps through the wallet transactions associated
+// with a set of addresses one at a time, holding no more than a page of them in
+// memory.  It is returned by ListAddressTransactionsIter.
+//
+// The iterator is not safe for concurrent access.
+type AddressTransactionsIterator struct {
+	client   *Client
+	account  string
+	addrs    map[string]struct{}
+	pageSize int
+
+	from int
+	page []sebtcjson.ListTransactionsResult
+	done bool
+}
+
+// ListAddressTransactionsIter returns an iterator over the transactions of the
+// passed account associated with the provided addresses, which returns the
+// same transactions as ListAddressTransactions without holding all of them in
+// memory.  Use "*" as the account to include every account.
+//
+// The iterator pages through the transactions of the wallet with
+// listtransactions, requesting the passed number of transactions at once, and
+// skips those paying other addresses.  The page size defaults to 100 when it is
+// not positive.  Since listtransactions skips the most recent transactions
+// first, transactions received while iterating shift the pages, so a
+// transaction may be returned twice.
+func (c *Client) ListAddressTransactionsIter(addresses []ltcutil.Address, account string, pageSize int) *AddressTransactionsIterator {
+	if pageSize <= 0 {
+		pageSize = defaultTxIterPageSize
+	}
+	addrs := make(map[string]struct{}, len(addresses))
+	for _, addr := range addresses {
+		addrs[addr.EncodeAddress()] = struct{}{}
+	}
+	return &AddressTransactionsIterator{
+		client:   c,
+		account:  account,
+		addrs:    addrs,
+		pageSize: pageSize,
+	}
+}
+
+// Next returns the next transaction associated with the addresses of the
+// iterator, fetching the next page when the current one is used up.  Iteration
+// stops after a page shorter than the page size, after which Next returns a nil
+// transaction and a nil error.
+//
+// When a page can not be fetched, the error is returned and the same page is
+// requested again on the next call.
+func (it *AddressTransactionsIterator) Next() (*sebtcjson.ListTransactionsResult, error) {
+	for len(it.page) == 0 {
+		if it.done {
+			return nil, nil
+		}
+
+		page, err := it.client.ListTransactionsCountFrom(it.account,
+			it.pageSize, it.from, true)
+		if err != nil {
+			return nil, err
+		}
+		it.from += len(page)
+		it.done = len(page) < it.pageSize
+
+		// Filter the page in place so only the transactions of the
+		// addresses are retained.
+		it.page = page[:0]
+		for _, tx := range page {
+			if _, ok := it.addrs[tx.Address]; ok {
+				it.page = append(it.page, tx)
+			}
+		}
+	}
+
+	tx := &it.page[0]
+	it.page = it.page[1:]
+	return tx, nil
+}
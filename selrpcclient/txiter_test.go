@@ -0,0 +1,98 @@
+// Copyright (c) 2018 The box developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package selrpcclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcutil"
+	"github.com/zzpu/lib-bitcore/sebtcjson"
+	"reflect"
+	"testing"
+)
+
+// TestListAddressTransactionsIter ensures the iterator pages through the wallet
+// transactions, only returns those of its addresses, and stops after a short
+// page.
+func TestListAddressTransactionsIter(t *testing.T) {
+	t.Parallel()
+
+	mine, err := ltcutil.NewAddressPubKeyHash(bytes.Repeat([]byte{0x01}, 20),
+		&chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatalf("NewAddressPubKeyHash: unexpected error: %v", err)
+	}
+	other, err := ltcutil.NewAddressPubKeyHash(bytes.Repeat([]byte{0x02}, 20),
+		&chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatalf("NewAddressPubKeyHash: unexpected error: %v", err)
+	}
+
+	// Seven transactions, every other one paying the address of interest.
+	var txs []sebtcjson.ListTransactionsResult
+	var want []string
+	for i := 0; i < 7; i++ {
+		addr := other
+		if i%2 == 0 {
+			addr = mine
+			want = append(want, fmt.Sprintf("tx%d", i))
+		}
+		txs = append(txs, sebtcjson.ListTransactionsResult{
+			Address: addr.EncodeAddress(),
+			TxID:    fmt.Sprintf("tx%d", i),
+		})
+	}
+
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		if method != "listtransactions" {
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		}
+		var count, from int
+		if err := json.Unmarshal(params[1], &count); err != nil {
+			return nil, sebtcjson.NewRPCError(sebtcjson.ErrRPCInvalidParameter, "invalid count")
+		}
+		if err := json.Unmarshal(params[2], &from); err != nil {
+			return nil, sebtcjson.NewRPCError(sebtcjson.ErrRPCInvalidParameter, "invalid from")
+		}
+		page := []sebtcjson.ListTransactionsResult{}
+		for i := from; i < from+count && i < len(txs); i++ {
+			page = append(page, txs[i])
+		}
+		return page, nil
+	})
+	defer closeTestClient(client, server)
+
+	it := client.ListAddressTransactionsIter([]ltcutil.Address{mine}, "*", 3)
+	var got []string
+	for {
+		tx, err := it.Next()
+		if err != nil {
+			t.Fatalf("Next: unexpected error: %v", err)
+		}
+		if tx == nil {
+			break
+		}
+		got = append(got, tx.TxID)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected transactions - got %v, want %v", got, want)
+	}
+
+	// The third page holds a single transaction, so no fourth page is
+	// requested, not even by further calls of Next.
+	if tx, err := it.Next(); tx != nil || err != nil {
+		t.Errorf("Next after the end: unexpected result %v, %v", tx, err)
+	}
+	if calls := server.calls("listtransactions"); calls != 3 {
+		t.Errorf("unexpected number of pages requested - got %d, want 3",
+			calls)
+	}
+	params := marshalParams(t, server.lastRequest("listtransactions"))
+	if want := `["*",3,6,true]`; params != want {
+		t.Errorf("unexpected params - got %s, want %s", params, want)
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright (c) 2018 The box developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package selrpcclient
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/ltcsuite/ltcd/wire"
+	"github.com/zzpu/lib-bitcore/sebtcjson"
+	"testing"
+)
+
+// TestParseTxRejection ensures the reject messages of the various versions of
+// Bitcoin Core are parsed and classified.
+func TestParseTxRejection(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		message   string
+		rejection TxRejection
+		kind      TxRejectKind
+	}{
+		{
+			name:      "0.13 code prefix",
+			message:   "66: min relay fee not met",
+			rejection: TxRejection{Reason: "min relay fee not met", Code: 66},
+			kind:      TxRejectInsufficientFee,
+		},
+		{
+			name:    "0.13 script failure",
+			message: "16: mandatory-script-verify-flag-failed (Script failed an OP_EQUALVERIFY operation)",
+			rejection: TxRejection{
+				Reason:  "mandatory-script-verify-flag-failed",
+				Code:    16,
+				Details: "Script failed an OP_EQUALVERIFY operation",
+			},
+			kind: TxRejectInvalid,
+		},
+		{
+			name:      "0.17 code suffix",
+			message:   "txn-mempool-conflict (code 18)",
+			rejection: TxRejection{Reason: "txn-mempool-conflict", Code: 18},
+			kind:      TxRejectDoubleSpend,
+		},
+		{
+			name:    "0.17 details and code suffix",
+			message: "mempool min fee not met, 1000 < 1250 (code 66)",
+			rejection: TxRejection{
+				Reason:  "mempool min fee not met",
+				Code:    66,
+				Details: "1000 < 1250",
+			},
+			kind: TxRejectInsufficientFee,
+		},
+		{
+			name:    "0.17 non-standard script",
+			message: "non-mandatory-script-verify-flag (Witness program hash mismatch) (code 64)",
+			rejection: TxRejection{
+				Reason:  "non-mandatory-script-verify-flag",
+				Code:    64,
+				Details: "Witness program hash mismatch",
+			},
+			kind: TxRejectNonStandard,
+		},
+		{
+			name:      "0.17 dust",
+			message:   "dust (code 64)",
+			rejection: TxRejection{Reason: "dust", Code: 64},
+			kind:      TxRejectNonStandard,
+		},
+		{
+			name:    "0.20 details",
+			message: "min relay fee not met, 100 < 141",
+			rejection: TxRejection{
+				Reason:  "min relay fee not met",
+				Details: "100 < 141",
+			},
+			kind: TxRejectInsufficientFee,
+		},
+		{
+			name:    "0.20 replacement fee",
+			message: "insufficient fee, rejecting replacement 8a3f; new feerate 0.00001 BTC/kvB <= old feerate 0.00002 BTC/kvB",
+			rejection: TxRejection{
+				Reason:  "insufficient fee",
+				Details: "rejecting replacement 8a3f; new feerate 0.00001 BTC/kvB <= old feerate 0.00002 BTC/kvB",
+			},
+			kind: TxRejectInsufficientFee,
+		},
+		{
+			name:    "0.20 script failure with nested parentheses",
+			message: "non-mandatory-script-verify-flag (Signature must be zero for failed CHECK(MULTI)SIG operation)",
+			rejection: TxRejection{
+				Reason:  "non-mandatory-script-verify-flag",
+				Details: "Signature must be zero for failed CHECK(MULTI)SIG operation",
+			},
+			kind: TxRejectNonStandard,
+		},
+		{
+			name:      "missing inputs",
+			message:   "bad-txns-inputs-missingorspent",
+			rejection: TxRejection{Reason: "bad-txns-inputs-missingorspent"},
+			kind:      TxRejectMissingInputs,
+		},
+		{
+			name:      "already in chain",
+			message:   "Transaction already in block chain",
+			rejection: TxRejection{Reason: "Transaction already in block chain"},
+			kind:      TxRejectAlreadyKnown,
+		},
+		{
+			name:    "max fee",
+			message: "Fee exceeds maximum configured by user (e.g. -maxtxfee, maxfeerate)",
+			rejection: TxRejection{
+				Reason:  "Fee exceeds maximum configured by user",
+				Details: "e.g. -maxtxfee, maxfeerate",
+			},
+			kind: TxRejectHighFee,
+		},
+		{
+			name:      "consensus failure",
+			message:   "bad-txns-in-belowout, value in (0.01) < value out (0.02)",
+			rejection: TxRejection{Reason: "bad-txns-in-belowout", Details: "value in (0.01) < value out (0.02)"},
+			kind:      TxRejectInvalid,
+		},
+		{
+			name:      "unrecognized",
+			message:   "something went wrong",
+			rejection: TxRejection{Reason: "something went wrong"},
+			kind:      TxRejectUnknown,
+		},
+		{
+			name:      "unparsable",
+			message:   ", oops",
+			rejection: TxRejection{Reason: ", oops"},
+			kind:      TxRejectUnknown,
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		rejection := ParseTxRejection(test.message)
+		if rejection != test.rejection {
+			t.Errorf("Test #%d (%s) unexpected rejection - got %+v, "+
+				"want %+v", i, test.name, rejection, test.rejection)
+			continue
+		}
+		if kind := rejection.Kind(); kind != test.kind {
+			t.Errorf("Test #%d (%s) unexpected kind - got %v, "+
+				"want %v", i, test.name, kind, test.kind)
+		}
+	}
+}
+
+// TestSendRawTransactionRejected ensures rejections reported by the server are
+// returned as a TxRejectedError which wraps the server error.
+func TestSendRawTransactionRejected(t *testing.T) {
+	t.Parallel()
+
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		return nil, sebtcjson.NewRPCError(sebtcjson.ErrRPCVerifyRejected,
+			"txn-mempool-conflict (code 18)")
+	})
+	defer closeTestClient(client, server)
+
+	_, err := client.SendRawTransaction(wire.NewMsgTx(wire.TxVersion), false)
+	rejectErr, ok := err.(*TxRejectedError)
+	if !ok {
+		t.Fatalf("SendRawTransaction: unexpected error type %T: %v",
+			err, err)
+	}
+	if kind := rejectErr.Rejection.Kind(); kind != TxRejectDoubleSpend {
+		t.Errorf("SendRawTransaction: unexpected kind - got %v, want %v",
+			kind, TxRejectDoubleSpend)
+	}
+	var rpcErr *sebtcjson.RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != sebtcjson.ErrRPCVerifyRejected {
+		t.Errorf("SendRawTransaction: error does not wrap the server "+
+			"error: %v", err)
+	}
+}
@@ -7,12 +7,17 @@ package selrpcclient
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/ltcsuite/ltcd/chaincfg"
 	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
 	"github.com/ltcsuite/ltcd/wire"
 	"github.com/ltcsuite/ltcutil"
 	"github.com/zzpu/lib-bitcore/sebtcjson"
+	"math"
+	"sort"
 	"strconv"
+	"strings"
 )
 
 // *****************************
@@ -76,6 +81,37 @@ func (c *Client) GetTransactionPlus(txHash *chainhash.Hash) (*sebtcjson.GetTrans
 	return c.GetTransactionAsyncPlus(txHash).Receive()
 }
 
+// GetTransactions returns detailed information about each of the passed wallet
+// transactions, requesting all of them from the server in a single batch.  The
+// results and errors are in the order of the passed hashes, so a transaction
+// which can not be returned, such as one which is not in the wallet, only
+// fails its own position.  An error is returned when the batch as a whole
+// fails, which is then also reported for every position.
+//
+// Batches require the client to run in HTTP POST mode.
+func (c *Client) GetTransactions(txHashes []*chainhash.Hash,
+	includeWatchOnly bool) ([]*sebtcjson.GetTransactionResult, []error, error) {
+
+	batch := c.Batch()
+	futures := make([]FutureGetTransactionResult, len(txHashes))
+	for i, txHash := range txHashes {
+		hash := ""
+		if txHash != nil {
+			hash = txHash.String()
+		}
+		cmd := sebtcjson.NewGetTransactionCmd(hash, &includeWatchOnly)
+		futures[i] = batch.sendCmd(cmd)
+	}
+	sendErr := batch.Send()
+
+	results := make([]*sebtcjson.GetTransactionResult, len(futures))
+	errs := make([]error, len(futures))
+	for i, future := range futures {
+		results[i], errs[i] = future.Receive()
+	}
+	return results, errs, sendErr
+}
+
 // FutureListTransactionsResult is a future promise to deliver the result of a
 // ListTransactionsAsync, ListTransactionsCountAsync, or
 // ListTransactionsCountFromAsync RPC invocation (or an applicable error).
@@ -154,6 +190,40 @@ func (c *Client) ListTransactionsCountFrom(account string, count, from int, Incl
 	return c.ListTransactionsCountFromAsync(account, count, from, IncludeWatchOnly).Receive()
 }
 
+// ListTransactionsFullAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See ListTransactionsFull for the blocking version and more details.
+func (c *Client) ListTransactionsFullAsync(label *string, count, skip *int, includeWatchOnly *bool) FutureListTransactionsResult {
+	// The parameters are positional, so any nil parameter which precedes a
+	// provided one is filled in with the server default.  The skip is
+	// always sent along with an explicit count.
+	if skip == nil && (count != nil || includeWatchOnly != nil) {
+		skip = sebtcjson.Int(0)
+	}
+	if skip != nil && count == nil {
+		count = sebtcjson.Int(10)
+	}
+	if count != nil && label == nil {
+		label = sebtcjson.String("*")
+	}
+
+	cmd := sebtcjson.NewListTransactionsCmd(label, count, skip, includeWatchOnly)
+	return c.sendCmd(cmd)
+}
+
+// ListTransactionsFull returns a list of the most recent transactions using
+// the full listtransactions signature.  All parameters are optional and are
+// omitted from the request when nil, in which case the server defaults apply.
+//
+// A label of "*" selects transactions for all labels.  When a later parameter
+// is provided along with a nil label, "*" is sent in its place since it is the
+// server default.
+func (c *Client) ListTransactionsFull(label *string, count, skip *int, includeWatchOnly *bool) ([]sebtcjson.ListTransactionsResult, error) {
+	return c.ListTransactionsFullAsync(label, count, skip, includeWatchOnly).Receive()
+}
+
 // FutureListUnspentResult is a future promise to deliver the result of a
 // ListUnspentAsync, ListUnspentMinAsync, ListUnspentMinMaxAsync, or
 // ListUnspentMinMaxAddressesAsync RPC invocation (or an applicable error).
@@ -325,6 +395,51 @@ func (c *Client) ListSinceBlockMinConf(blockHash *chainhash.Hash, minConfirms in
 	return c.ListSinceBlockMinConfAsync(blockHash, minConfirms).Receive()
 }
 
+// WalletSync houses the changes to the transactions of a wallet since a block,
+// as returned by SyncWalletSince.
+type WalletSync struct {
+	// Added holds the transactions in blocks after the block synced from,
+	// as well as those in the mempool.
+	Added []sebtcjson.ListTransactionsResult
+
+	// Removed holds the transactions which were in blocks disconnected by
+	// a reorganization since the block synced from.  Transactions which
+	// were mined again on the new chain are also listed in Added.
+	Removed []sebtcjson.ListTransactionsResult
+
+	// LastBlock is the block to sync from next time.
+	LastBlock *chainhash.Hash
+}
+
+// Reorged returns whether a reorganization disconnected wallet transactions
+// since the block synced from.
+func (s *WalletSync) Reorged() bool {
+	return len(s.Removed) > 0
+}
+
+// SyncWalletSince returns the changes to the transactions of the wallet since
+// the passed block, or all transactions if it is nil, along with the block to
+// pass on the next call.  Any transactions reported as removed must be undone
+// before the added ones are applied.
+//
+// NOTE: Removed transactions are only reported by Bitcoin Core 0.16 or later.
+func (c *Client) SyncWalletSince(lastBlock *chainhash.Hash) (*WalletSync, error) {
+	result, err := c.ListSinceBlock(lastBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := chainhash.NewHashFromStr(result.LastBlock)
+	if err != nil {
+		return nil, err
+	}
+	return &WalletSync{
+		Added:     result.Transactions,
+		Removed:   result.Removed,
+		LastBlock: next,
+	}, nil
+}
+
 // **************************
 // Transaction Send Functions
 // **************************
@@ -484,7 +599,8 @@ func (r FutureSendToAddressResult) Receive() (*chainhash.Hash, error) {
 // See SendToAddress for the blocking version and more details.
 func (c *Client) SendToAddressAsync(address ltcutil.Address, amount ltcutil.Amount) FutureSendToAddressResult {
 	addr := address.EncodeAddress()
-	cmd := sebtcjson.NewSendToAddressCmd(addr, amount.ToBTC(), nil, nil)
+	btc := amount.ToBTC()
+	cmd := sebtcjson.NewSendToAddressCmd(addr, btc, nil, nil)
 	return c.sendCmd(cmd)
 }
 
@@ -510,7 +626,8 @@ func (c *Client) SendToAddressCommentAsync(address ltcutil.Address,
 	commentTo string) FutureSendToAddressResult {
 
 	addr := address.EncodeAddress()
-	cmd := sebtcjson.NewSendToAddressCmd(addr, amount.ToBTC(), &comment,
+	btc := amount.ToBTC()
+	cmd := sebtcjson.NewSendToAddressCmd(addr, btc, &comment,
 		&commentTo)
 	return c.sendCmd(cmd)
 }
@@ -532,6 +649,33 @@ func (c *Client) SendToAddressComment(address ltcutil.Address, amount ltcutil.Am
 		commentTo).Receive()
 }
 
+// SendToAddressAvoidReuseAsync returns an instance of a type that can be used
+// to get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See SendToAddressAvoidReuse for the blocking version and more details.
+func (c *Client) SendToAddressAvoidReuseAsync(address ltcutil.Address,
+	amount ltcutil.Amount, avoidReuse bool) FutureSendToAddressResult {
+
+	addr := address.EncodeAddress()
+	btc := amount.ToBTC()
+	cmd := sebtcjson.NewSendToAddressCmd(addr, btc, nil, nil)
+	cmd.AvoidReuse = &avoidReuse
+	return c.sendCmd(cmd)
+}
+
+// SendToAddressAvoidReuse sends the passed amount to the given address.  When
+// avoidReuse is true, outputs paying to addresses which have already been spent
+// from are not used to fund the transaction.  The parameters before it are sent
+// as null so the server defaults apply.
+//
+// NOTE: This function requires Bitcoin Core 0.19 or later with the
+// avoid_reuse wallet flag set.  It also requires to the wallet to be unlocked.
+// See the WalletPassphrase function for more details.
+func (c *Client) SendToAddressAvoidReuse(address ltcutil.Address, amount ltcutil.Amount, avoidReuse bool) (*chainhash.Hash, error) {
+	return c.SendToAddressAvoidReuseAsync(address, amount, avoidReuse).Receive()
+}
+
 // FutureSendFromResult is a future promise to deliver the result of a
 // SendFromAsync, SendFromMinConfAsync, or SendFromCommentAsync RPC invocation
 // (or an applicable error).
@@ -563,7 +707,8 @@ func (r FutureSendFromResult) Receive() (*chainhash.Hash, error) {
 // See SendFrom for the blocking version and more details.
 func (c *Client) SendFromAsync(fromAccount string, toAddress ltcutil.Address, amount ltcutil.Amount) FutureSendFromResult {
 	addr := toAddress.EncodeAddress()
-	cmd := sebtcjson.NewSendFromCmd(fromAccount, addr, amount.ToBTC(), nil,
+	btc := amount.ToBTC()
+	cmd := sebtcjson.NewSendFromCmd(fromAccount, addr, btc, nil,
 		nil, nil)
 	return c.sendCmd(cmd)
 }
@@ -587,7 +732,8 @@ func (c *Client) SendFrom(fromAccount string, toAddress ltcutil.Address, amount
 // See SendFromMinConf for the blocking version and more details.
 func (c *Client) SendFromMinConfAsync(fromAccount string, toAddress ltcutil.Address, amount ltcutil.Amount, minConfirms int) FutureSendFromResult {
 	addr := toAddress.EncodeAddress()
-	cmd := sebtcjson.NewSendFromCmd(fromAccount, addr, amount.ToBTC(),
+	btc := amount.ToBTC()
+	cmd := sebtcjson.NewSendFromCmd(fromAccount, addr, btc,
 		&minConfirms, nil, nil)
 	return c.sendCmd(cmd)
 }
@@ -616,7 +762,8 @@ func (c *Client) SendFromCommentAsync(fromAccount string,
 	comment, commentTo string) FutureSendFromResult {
 
 	addr := toAddress.EncodeAddress()
-	cmd := sebtcjson.NewSendFromCmd(fromAccount, addr, amount.ToBTC(),
+	btc := amount.ToBTC()
+	cmd := sebtcjson.NewSendFromCmd(fromAccount, addr, btc,
 		&minConfirms, &comment, &commentTo)
 	return c.sendCmd(cmd)
 }
@@ -907,15 +1054,43 @@ func (r FutureGetNewAddressResult) Receive() (ltcutil.Address, error) {
 //
 // See GetNewAddress for the blocking version and more details.
 func (c *Client) GetNewAddressAsync(account string) FutureGetNewAddressResult {
-	cmd := sebtcjson.NewGetNewAddressCmd(&account)
-	return c.sendCmd(cmd)
+	return c.GetNewAddressTypeAsync(account, nil)
 }
 
-// GetNewAddress returns a new address.
+// GetNewAddress returns a new address.  The address is of the type set by the
+// DefaultAddressType field of the connection configuration when it is set.
 func (c *Client) GetNewAddress(account string) (ltcutil.Address, error) {
 	return c.GetNewAddressAsync(account).Receive()
 }
 
+// defaultAddressType returns the passed address type or, when it is nil, the
+// DefaultAddressType of the connection configuration.  It returns nil when
+// neither is set.
+func (c *Client) defaultAddressType(addressType *string) *string {
+	if addressType == nil && c.config.DefaultAddressType != "" {
+		return sebtcjson.String(c.config.DefaultAddressType)
+	}
+	return addressType
+}
+
+// GetNewAddressTypeAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GetNewAddressType for the blocking version and more details.
+func (c *Client) GetNewAddressTypeAsync(account string, addressType *string) FutureGetNewAddressResult {
+	cmd := sebtcjson.NewGetNewAddressCmd(&account)
+	cmd.AddressType = c.defaultAddressType(addressType)
+	return c.sendCmd(cmd)
+}
+
+// GetNewAddressType returns a new address of the passed type, such as
+// "bech32".  Passing nil uses the DefaultAddressType field of the connection
+// configuration, or the server default when that is not set either.
+func (c *Client) GetNewAddressType(account string, addressType *string) (ltcutil.Address, error) {
+	return c.GetNewAddressTypeAsync(account, addressType).Receive()
+}
+
 // FutureGetRawChangeAddressResult is a future promise to deliver the result of
 // a GetRawChangeAddressAsync RPC invocation (or an applicable error).
 type FutureGetRawChangeAddressResult chan *response
@@ -945,6 +1120,14 @@ func (r FutureGetRawChangeAddressResult) Receive() (ltcutil.Address, error) {
 //
 // See GetRawChangeAddress for the blocking version and more details.
 func (c *Client) GetRawChangeAddressAsync(account string) FutureGetRawChangeAddressResult {
+	if c.config.DefaultAddressType != "" {
+		if account != "" {
+			return newFutureError(fmt.Errorf("account %q can not be "+
+				"passed along with the default address type %q",
+				account, c.config.DefaultAddressType))
+		}
+		return c.GetRawChangeAddressTypeAsync(nil)
+	}
 	cmd := sebtcjson.NewGetRawChangeAddressCmd(&account)
 	return c.sendCmd(cmd)
 }
@@ -952,10 +1135,35 @@ func (c *Client) GetRawChangeAddressAsync(account string) FutureGetRawChangeAddr
 // GetRawChangeAddress returns a new address for receiving change that will be
 // associated with the provided account.  Note that this is only for raw
 // transactions and NOT for normal use.
+//
+// When the DefaultAddressType field of the connection configuration is set,
+// the address is of that type.  The server takes the address type in place of
+// the account, so the account must then be empty and an error is returned
+// otherwise.
 func (c *Client) GetRawChangeAddress(account string) (ltcutil.Address, error) {
 	return c.GetRawChangeAddressAsync(account).Receive()
 }
 
+// GetRawChangeAddressTypeAsync returns an instance of a type that can be used
+// to get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See GetRawChangeAddressType for the blocking version and more details.
+func (c *Client) GetRawChangeAddressTypeAsync(addressType *string) FutureGetRawChangeAddressResult {
+	// Servers which support address types take the address type as the
+	// only parameter, in the position of the account.
+	cmd := sebtcjson.NewGetRawChangeAddressCmd(c.defaultAddressType(addressType))
+	return c.sendCmd(cmd)
+}
+
+// GetRawChangeAddressType returns a new address of the passed type for
+// receiving change.  Passing nil uses the DefaultAddressType field of the
+// connection configuration, or the server default when that is not set either.
+// Note that this is only for raw transactions and NOT for normal use.
+func (c *Client) GetRawChangeAddressType(addressType *string) (ltcutil.Address, error) {
+	return c.GetRawChangeAddressTypeAsync(addressType).Receive()
+}
+
 // FutureAddWitnessAddressResult is a future promise to deliver the result of
 // a AddWitnessAddressAsync RPC invocation (or an applicable error).
 type FutureAddWitnessAddressResult chan *response
@@ -1145,6 +1353,65 @@ func (c *Client) GetAddressesByAccount(account string) ([]ltcutil.Address, error
 	return c.GetAddressesByAccountAsync(account).Receive()
 }
 
+// FutureGetAddressesByLabelResult is a future promise to deliver the result of
+// a GetAddressesByLabelAsync RPC invocation (or an applicable error).
+type FutureGetAddressesByLabelResult chan *response
+
+// Receive waits for the response promised by the future and returns the
+// addresses with the passed label, keyed by address.
+func (r FutureGetAddressesByLabelResult) Receive() (map[string]sebtcjson.GetAddressesByLabelResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var addresses map[string]sebtcjson.GetAddressesByLabelResult
+	err = json.Unmarshal(res, &addresses)
+	if err != nil {
+		return nil, err
+	}
+	return addresses, nil
+}
+
+// GetAddressesByLabelAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See GetAddressesByLabel for the blocking version and more details.
+func (c *Client) GetAddressesByLabelAsync(label string) FutureGetAddressesByLabelResult {
+	cmd := sebtcjson.NewGetAddressesByLabelCmd(label)
+	return c.sendCmd(cmd)
+}
+
+// GetAddressesByLabel returns the addresses with the passed label, keyed by
+// address.  The purpose of each address is either "send" or "receive".  See
+// DecodeLabelAddresses to decode the addresses.
+func (c *Client) GetAddressesByLabel(label string) (map[string]sebtcjson.GetAddressesByLabelResult, error) {
+	return c.GetAddressesByLabelAsync(label).Receive()
+}
+
+// DecodeLabelAddresses decodes the addresses returned by GetAddressesByLabel
+// for the network set by the ChainParams field of the connection
+// configuration.  The addresses are returned in lexicographic order.
+func (c *Client) DecodeLabelAddresses(addresses map[string]sebtcjson.GetAddressesByLabelResult) ([]ltcutil.Address, error) {
+	addrStrings := make([]string, 0, len(addresses))
+	for addrStr := range addresses {
+		addrStrings = append(addrStrings, addrStr)
+	}
+	sort.Strings(addrStrings)
+
+	params := c.chainParams()
+	addrs := make([]ltcutil.Address, 0, len(addrStrings))
+	for _, addrStr := range addrStrings {
+		addr, err := ltcutil.DecodeAddress(addrStr, params)
+		if err != nil {
+			return nil, err
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs, nil
+}
+
 // FutureMoveResult is a future promise to deliver the result of a MoveAsync,
 // MoveMinConfAsync, or MoveCommentAsync RPC invocation (or an applicable
 // error).
@@ -1174,7 +1441,8 @@ func (r FutureMoveResult) Receive() (bool, error) {
 //
 // See Move for the blocking version and more details.
 func (c *Client) MoveAsync(fromAccount, toAccount string, amount ltcutil.Amount) FutureMoveResult {
-	cmd := sebtcjson.NewMoveCmd(fromAccount, toAccount, amount.ToBTC(), nil,
+	btc := amount.ToBTC()
+	cmd := sebtcjson.NewMoveCmd(fromAccount, toAccount, btc, nil,
 		nil)
 	return c.sendCmd(cmd)
 }
@@ -1195,7 +1463,8 @@ func (c *Client) Move(fromAccount, toAccount string, amount ltcutil.Amount) (boo
 func (c *Client) MoveMinConfAsync(fromAccount, toAccount string,
 	amount ltcutil.Amount, minConfirms int) FutureMoveResult {
 
-	cmd := sebtcjson.NewMoveCmd(fromAccount, toAccount, amount.ToBTC(),
+	btc := amount.ToBTC()
+	cmd := sebtcjson.NewMoveCmd(fromAccount, toAccount, btc,
 		&minConfirms, nil)
 	return c.sendCmd(cmd)
 }
@@ -1218,7 +1487,8 @@ func (c *Client) MoveMinConf(fromAccount, toAccount string, amount ltcutil.Amoun
 func (c *Client) MoveCommentAsync(fromAccount, toAccount string,
 	amount ltcutil.Amount, minConfirms int, comment string) FutureMoveResult {
 
-	cmd := sebtcjson.NewMoveCmd(fromAccount, toAccount, amount.ToBTC(),
+	btc := amount.ToBTC()
+	cmd := sebtcjson.NewMoveCmd(fromAccount, toAccount, btc,
 		&minConfirms, &comment)
 	return c.sendCmd(cmd)
 }
@@ -1300,6 +1570,64 @@ func (c *Client) ValidateAddress(address ltcutil.Address) (*sebtcjson.ValidateAd
 	return c.ValidateAddressAsync(address).Receive()
 }
 
+// ValidateAddressStringAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See ValidateAddressString for the blocking version and more details.
+func (c *Client) ValidateAddressStringAsync(address string) FutureValidateAddressResult {
+	cmd := sebtcjson.NewValidateAddressCmd(address)
+	return c.sendCmd(cmd)
+}
+
+// ValidateAddressString returns information about the given encoded address as
+// reported by the server.  Unlike ValidateAddress, the address does not need
+// to be decoded first, so it may be used on externally supplied addresses.  An
+// address which the server considers invalid is reported through the IsValid
+// field of the result rather than as an error.
+func (c *Client) ValidateAddressString(address string) (*sebtcjson.ValidateAddressWalletResult, error) {
+	return c.ValidateAddressStringAsync(address).Receive()
+}
+
+// chainParams returns the network set by the ChainParams field of the
+// connection configuration, which defaults to the main network.
+func (c *Client) chainParams() *chaincfg.Params {
+	if c.config.ChainParams == nil {
+		return &chaincfg.MainNetParams
+	}
+	return c.config.ChainParams
+}
+
+// ParseAndValidateAddress decodes the passed address for the network set by the
+// ChainParams field of the connection configuration and confirms with the
+// server that the address is valid.  An error is returned when the address is
+// for a different network or the server reports it as invalid.
+func (c *Client) ParseAndValidateAddress(address string) (ltcutil.Address, error) {
+	params := c.chainParams()
+
+	addr, err := ltcutil.DecodeAddress(address, params)
+	if err != nil {
+		return nil, err
+	}
+	if !addr.IsForNet(params) {
+		return nil, fmt.Errorf("address %s is not for network %s",
+			address, params.Name)
+	}
+
+	result, err := c.ValidateAddressString(address)
+	if err != nil {
+		return nil, err
+	}
+	if !result.IsValid {
+		if result.Error != "" {
+			return nil, fmt.Errorf("address %s is invalid: %s",
+				address, result.Error)
+		}
+		return nil, fmt.Errorf("address %s is invalid", address)
+	}
+	return addr, nil
+}
+
 // FutureKeyPoolRefillResult is a future promise to deliver the result of a
 // KeyPoolRefillAsync RPC invocation (or an applicable error).
 type FutureKeyPoolRefillResult chan *response
@@ -1520,6 +1848,63 @@ func (c *Client) GetBalanceMinConf(account string, minConfirms int) (ltcutil.Amo
 	return c.GetBalanceMinConfAsync(account, minConfirms).Receive()
 }
 
+// FutureGetBalancesResult is a future promise to deliver the result of a
+// GetBalancesAsync RPC invocation (or an applicable error).
+type FutureGetBalancesResult chan *response
+
+// Receive waits for the response promised by the future and returns the
+// balances of the wallet broken down by trust and maturity.
+func (r FutureGetBalancesResult) Receive() (*sebtcjson.GetBalancesResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getbalances result object.
+	var balances sebtcjson.GetBalancesResult
+	err = json.Unmarshal(res, &balances)
+	if err != nil {
+		return nil, err
+	}
+
+	return &balances, nil
+}
+
+// GetBalancesAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See GetBalances for the blocking version and more details.
+func (c *Client) GetBalancesAsync() FutureGetBalancesResult {
+	cmd := sebtcjson.NewGetBalancesCmd()
+	return c.sendCmd(cmd)
+}
+
+// GetBalances returns the balances of the wallet broken down by trust and
+// maturity.
+func (c *Client) GetBalances() (*sebtcjson.GetBalancesResult, error) {
+	return c.GetBalancesAsync().Receive()
+}
+
+// AvailableBalance returns the confirmed balance of the wallet which is
+// available for spending, excluding immature coinbase outputs.
+//
+// The trusted balance reported by getbalances is preferred.  It does not take a
+// minimum number of confirmations, so minConf is only used for the getbalance
+// fallback on servers which do not support getbalances.
+func (c *Client) AvailableBalance(minConf int) (ltcutil.Amount, error) {
+	balances, err := c.GetBalances()
+	if err == nil {
+		return ltcutil.NewAmount(balances.Mine.Trusted)
+	}
+	if rpcErr, ok := err.(*sebtcjson.RPCError); !ok ||
+		rpcErr.Code != sebtcjson.ErrRPCMethodNotFound.Code {
+		return 0, err
+	}
+
+	return c.GetBalanceMinConf("*", minConf)
+}
+
 // FutureGetReceivedByAccountResult is a future promise to deliver the result of
 // a GetReceivedByAccountAsync or GetReceivedByAccountMinConfAsync RPC
 // invocation (or an applicable error).
@@ -1871,6 +2256,74 @@ func (c *Client) ListReceivedByAddressIncludeEmpty(minConfirms int, includeEmpty
 		includeEmpty).Receive()
 }
 
+// FutureListReceivedByLabelResult is a future promise to deliver the result of
+// a ListReceivedByLabelAsync RPC invocation (or an applicable error).
+type FutureListReceivedByLabelResult chan *response
+
+// Receive waits for the response promised by the future and returns a list of
+// balances by label.
+func (r FutureListReceivedByLabelResult) Receive() ([]sebtcjson.ListReceivedByLabelResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal as an array of listreceivedbylabel result objects.
+	var received []sebtcjson.ListReceivedByLabelResult
+	err = json.Unmarshal(res, &received)
+	if err != nil {
+		return nil, err
+	}
+
+	return received, nil
+}
+
+// ListReceivedByLabelAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See ListReceivedByLabel for the blocking version and more details.
+func (c *Client) ListReceivedByLabelAsync(minConfirms int, includeEmpty, includeWatchOnly bool) FutureListReceivedByLabelResult {
+	cmd := sebtcjson.NewListReceivedByLabelCmd(&minConfirms, &includeEmpty,
+		&includeWatchOnly)
+	return c.sendCmd(cmd)
+}
+
+// ListReceivedByLabel lists balances by label using the specified number of
+// minimum confirmations, including labels that haven't received any payments
+// and watch-only addresses depending on the specified flags.
+//
+// NOTE: This requires Bitcoin Core 0.17 or later, where labels replace
+// accounts.
+func (c *Client) ListReceivedByLabel(minConfirms int, includeEmpty, includeWatchOnly bool) ([]sebtcjson.ListReceivedByLabelResult, error) {
+	return c.ListReceivedByLabelAsync(minConfirms, includeEmpty,
+		includeWatchOnly).Receive()
+}
+
+// GetAllReceivedByLabel returns the total received by every label of the
+// wallet, including labels that haven't received any payments, using the
+// specified number of minimum confirmations.  The default label is keyed by
+// the empty string.
+//
+// See ListReceivedByLabel for the per-label details.
+func (c *Client) GetAllReceivedByLabel(minConfirms int, includeWatchOnly bool) (map[string]ltcutil.Amount, error) {
+	received, err := c.ListReceivedByLabel(minConfirms, true,
+		includeWatchOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]ltcutil.Amount, len(received))
+	for _, r := range received {
+		amount, err := ltcutil.NewAmount(r.Amount)
+		if err != nil {
+			return nil, err
+		}
+		totals[r.Label] += amount
+	}
+	return totals, nil
+}
+
 // ************************
 // Wallet Locking Functions
 // ************************
@@ -1951,10 +2404,13 @@ type FutureSignMessageResult chan *response
 
 // Receive waits for the response promised by the future and returns the message
 // signed with the private key of the specified address.
+//
+// An error matching ErrWalletLocked with errors.Is is returned when the wallet
+// must be unlocked first.
 func (r FutureSignMessageResult) Receive() (string, error) {
 	res, err := receiveFuture(r)
 	if err != nil {
-		return "", err
+		return "", newWalletLockedError(err)
 	}
 
 	// Unmarshal result as a string.
@@ -1967,6 +2423,33 @@ func (r FutureSignMessageResult) Receive() (string, error) {
 	return b64, nil
 }
 
+// walletLockedError wraps the error returned by the server when a wallet
+// request failed because the wallet is locked.
+type walletLockedError struct {
+	*sebtcjson.RPCError
+}
+
+// Is returns whether the target is ErrWalletLocked.
+func (e *walletLockedError) Is(target error) bool {
+	return target == ErrWalletLocked
+}
+
+// Unwrap returns the error returned by the server.
+func (e *walletLockedError) Unwrap() error {
+	return e.RPCError
+}
+
+// newWalletLockedError returns a walletLockedError for the passed error when
+// the server reports that the wallet must be unlocked, and the error itself
+// otherwise.
+func newWalletLockedError(err error) error {
+	rpcErr, ok := err.(*sebtcjson.RPCError)
+	if !ok || rpcErr.Code != sebtcjson.ErrRPCWalletUnlockNeeded {
+		return err
+	}
+	return &walletLockedError{rpcErr}
+}
+
 // SignMessageAsync returns an instance of a type that can be used to get the
 // result of the RPC at some future time by invoking the Receive function on the
 // returned instance.
@@ -1978,10 +2461,12 @@ func (c *Client) SignMessageAsync(address ltcutil.Address, message string) Futur
 	return c.sendCmd(cmd)
 }
 
-// SignMessage signs a message with the private key of the specified address.
+// SignMessage signs a message with the private key of the specified address
+// and returns the base64-encoded signature.
 //
 // NOTE: This function requires to the wallet to be unlocked.  See the
-// WalletPassphrase function for more details.
+// WalletPassphrase function for more details.  An error matching
+// ErrWalletLocked with errors.Is is returned otherwise.
 func (c *Client) SignMessage(address ltcutil.Address, message string) (string, error) {
 	return c.SignMessageAsync(address, message).Receive()
 }
@@ -2074,6 +2559,203 @@ func (c *Client) DumpPrivKey(address ltcutil.Address) (*ltcutil.WIF, error) {
 	return c.DumpPrivKeyAsync(address).Receive()
 }
 
+// FutureSetHdSeedResult is a future promise to deliver the result of a
+// SetHdSeedAsync RPC invocation (or an applicable error).
+type FutureSetHdSeedResult chan *response
+
+// Receive waits for the response promised by the future and returns the result
+// of setting the HD seed of the wallet.
+func (r FutureSetHdSeedResult) Receive() error {
+	_, err := receiveFuture(r)
+	return err
+}
+
+// SetHdSeedAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See SetHdSeed for the blocking version and more details.
+func (c *Client) SetHdSeedAsync(newKeyPool *bool, seed *string) FutureSetHdSeedResult {
+	if seed != nil {
+		if *seed == "" {
+			return newFutureError(errors.New("seed must not be empty"))
+		}
+
+		// The parameters are positional, so the keypool flag must be
+		// sent with its default for the seed to be included.
+		if newKeyPool == nil {
+			newKeyPool = sebtcjson.Bool(true)
+		}
+	}
+
+	cmd := sebtcjson.NewSetHdSeedCmd(newKeyPool, seed)
+	return c.sendCmd(cmd)
+}
+
+// SetHdSeed sets or generates a new HD seed for a non-descriptor wallet.  The
+// seed, when provided, must be a private key in wallet import format (WIF);
+// otherwise a new random seed is generated.  When newKeyPool is true, or nil,
+// the keypool is flushed and refilled from the new seed.
+//
+// Like all requests, the seed is never written to the log.
+//
+// NOTE: This function requires to the wallet to be unlocked.  See the
+// WalletPassphrase function for more details.
+func (c *Client) SetHdSeed(newKeyPool *bool, seed *string) error {
+	return c.SetHdSeedAsync(newKeyPool, seed).Receive()
+}
+
+// FutureListDescriptorsResult is a future promise to deliver the result of a
+// ListDescriptorsAsync RPC invocation (or an applicable error).
+type FutureListDescriptorsResult chan *response
+
+// Receive waits for the response promised by the future and returns the
+// descriptors imported into the wallet.
+func (r FutureListDescriptorsResult) Receive() (*sebtcjson.ListDescriptorsResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a listdescriptors result object.
+	var descriptors sebtcjson.ListDescriptorsResult
+	err = json.Unmarshal(res, &descriptors)
+	if err != nil {
+		return nil, err
+	}
+
+	return &descriptors, nil
+}
+
+// ListDescriptorsAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See ListDescriptors for the blocking version and more details.
+func (c *Client) ListDescriptorsAsync(private *bool) FutureListDescriptorsResult {
+	cmd := sebtcjson.NewListDescriptorsCmd(private)
+	return c.sendCmd(cmd)
+}
+
+// ListDescriptors returns the descriptors imported into a descriptor wallet.
+// When private is true the descriptors include the private keys, so the result
+// is never written to the trace log.
+//
+// NOTE: Listing private descriptors requires the wallet to be unlocked.  See
+// the WalletPassphrase function for more details.
+func (c *Client) ListDescriptors(private *bool) (*sebtcjson.ListDescriptorsResult, error) {
+	return c.ListDescriptorsAsync(private).Receive()
+}
+
+// FutureImportDescriptorsResult is a future promise to deliver the result of an
+// ImportDescriptorsAsync RPC invocation (or an applicable error).
+type FutureImportDescriptorsResult chan *response
+
+// Receive waits for the response promised by the future and returns the result
+// of importing each of the requested descriptors.
+func (r FutureImportDescriptorsResult) Receive() ([]sebtcjson.ImportMultiResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []sebtcjson.ImportMultiResult
+	err = json.Unmarshal(res, &results)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// ImportDescriptorsAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See ImportDescriptors for the blocking version and more details.
+func (c *Client) ImportDescriptorsAsync(requests []sebtcjson.ImportDescriptorsRequest) FutureImportDescriptorsResult {
+	cmd := sebtcjson.NewImportDescriptorsCmd(requests)
+	return c.sendCmd(cmd)
+}
+
+// ImportDescriptors imports the passed descriptors into a descriptor wallet.
+// The result of each request, in the same order, reports whether it succeeded
+// rather than the whole call failing.  Each descriptor must include its
+// checksum, which AddDescriptorChecksum computes.
+//
+// NOTE: This requires Bitcoin Core 0.21 or later.
+func (c *Client) ImportDescriptors(requests []sebtcjson.ImportDescriptorsRequest) ([]sebtcjson.ImportMultiResult, error) {
+	return c.ImportDescriptorsAsync(requests).Receive()
+}
+
+// ImportWatchDescriptors imports the passed public descriptors into a
+// descriptor wallet to watch them.  Checksums are appended to the descriptors
+// which lack one and validated for those which do, before anything is sent to
+// the server.  The descriptors are imported as inactive, so the wallet does not
+// hand out addresses from them.  When rescan is true, the wallet is rescanned
+// from the genesis block for transactions involving them; otherwise only new
+// transactions are tracked.
+//
+// NOTE: This requires Bitcoin Core 0.21 or later.
+func (c *Client) ImportWatchDescriptors(descs []string, rescan bool) ([]sebtcjson.ImportMultiResult, error) {
+	var timestamp interface{} = "now"
+	if rescan {
+		timestamp = 0
+	}
+
+	requests := make([]sebtcjson.ImportDescriptorsRequest, 0, len(descs))
+	for _, desc := range descs {
+		desc, err := AddDescriptorChecksum(desc)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, sebtcjson.ImportDescriptorsRequest{
+			Descriptor: desc,
+			Active:     sebtcjson.Bool(false),
+			Timestamp:  timestamp,
+		})
+	}
+	return c.ImportDescriptors(requests)
+}
+
+// FutureImportMultiResult is a future promise to deliver the result of an
+// ImportMultiAsync RPC invocation (or an applicable error).
+type FutureImportMultiResult chan *response
+
+// Receive waits for the response promised by the future and returns the result
+// of importing each of the requested addresses and scripts.
+func (r FutureImportMultiResult) Receive() ([]sebtcjson.ImportMultiResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []sebtcjson.ImportMultiResult
+	err = json.Unmarshal(res, &results)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// ImportMultiAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See ImportMulti for the blocking version and more details.
+func (c *Client) ImportMultiAsync(requests []sebtcjson.ImportMultiRequest, options *sebtcjson.ImportMultiOptions) FutureImportMultiResult {
+	cmd := sebtcjson.NewImportMultiCmd(requests, options)
+	return c.sendCmd(cmd)
+}
+
+// ImportMulti imports the passed addresses, scripts, public keys and private
+// keys into the wallet in a single call, rescanning the wallet at most once
+// from the earliest timestamp of the requests.  The result of each request, in
+// the same order, reports whether it succeeded rather than the whole call
+// failing.  Passing nil options rescans the wallet.
+func (c *Client) ImportMulti(requests []sebtcjson.ImportMultiRequest, options *sebtcjson.ImportMultiOptions) ([]sebtcjson.ImportMultiResult, error) {
+	return c.ImportMultiAsync(requests, options).Receive()
+}
+
 // FutureImportAddressResult is a future promise to deliver the result of an
 // ImportAddressAsync RPC invocation (or an applicable error).
 type FutureImportAddressResult chan *response
@@ -2303,21 +2985,77 @@ func (r FutureEstimateSmartFeeResult) Receive() (*sebtcjson.EstimateSmartFeeResu
 // the result of the RPC at some future time by invoking the Receive function on
 // the returned instance.
 //
-// See EstimateSmartFee and EstimateSmartFeeWithMode for the blocking versions and more details.
-func (c *Client) EstimateSmartFeeAsync(confTarget uint32, estimateMode sebtcjson.EstimateMode) FutureEstimateSmartFeeResult {
+// See EstimateSmartFee for the blocking version and more details.
+func (c *Client) EstimateSmartFeeAsync(confTarget int64, estimateMode *sebtcjson.EstimateSmartFeeMode) FutureEstimateSmartFeeResult {
 	cmd := sebtcjson.NewEstimateSmartFeeCmd(confTarget, estimateMode)
 	return c.sendCmd(cmd)
 }
 
 // EstimateSmartFee estimates the approximate fee per kilobyte needed for a transaction
-// using the UNSET mode
-func (c *Client) EstimateSmartFee(confTarget uint32) (*sebtcjson.EstimateSmartFeeResult, error) {
-	return c.EstimateSmartFeeWithMode(confTarget, sebtcjson.UnsetEstimeMode)
+// to begin confirmation within confTarget blocks using the passed mode.  A nil mode is
+// omitted from the request, so the server uses its default mode.
+func (c *Client) EstimateSmartFee(confTarget int64, estimateMode *sebtcjson.EstimateSmartFeeMode) (*sebtcjson.EstimateSmartFeeResult, error) {
+	return c.EstimateSmartFeeAsync(confTarget, estimateMode).Receive()
 }
 
-// EstimateSmartFeeWithMode estimates the approximate fee per kilobyte needed for a transaction
-func (c *Client) EstimateSmartFeeWithMode(confTarget uint32, estimateMode sebtcjson.EstimateMode) (*sebtcjson.EstimateSmartFeeResult, error) {
-	return c.EstimateSmartFeeAsync(confTarget, estimateMode).Receive()
+// satPerVByte converts a fee rate in BTC per kilo virtual byte, as reported by
+// the server, to satoshi per virtual byte.  Fractional satoshis are rounded up
+// so the result never falls below the passed rate.
+func satPerVByte(btcPerKvB float64) (int64, error) {
+	perKvB, err := ltcutil.NewAmount(btcPerKvB)
+	if err != nil {
+		return 0, err
+	}
+	return (int64(perKvB) + 999) / 1000, nil
+}
+
+// clampFeeRate limits the passed fee rate to the range [min, max] and reports
+// whether it had to be adjusted.  A min or max of zero disables that bound.
+func clampFeeRate(feeRate, min, max int64) (int64, bool) {
+	if min > 0 && feeRate < min {
+		return min, true
+	}
+	if max > 0 && feeRate > max {
+		return max, true
+	}
+	return feeRate, false
+}
+
+// EstimateSmartFeeClamped estimates the fee rate, in satoshi per virtual byte,
+// needed for a transaction to begin confirmation within confTarget blocks and
+// limits it to the range [minFeeRate, maxFeeRate], also expressed in satoshi
+// per virtual byte.  A bound of zero is not enforced, and a nil mode uses the
+// default mode of the server.
+//
+// The server reports fee rates in BTC per kilo virtual byte, which are rounded
+// up to the next whole satoshi per virtual byte.  The returned flag indicates
+// whether the estimate fell outside the range and was clamped.
+func (c *Client) EstimateSmartFeeClamped(confTarget int64, estimateMode *sebtcjson.EstimateSmartFeeMode, minFeeRate, maxFeeRate int64) (int64, bool, error) {
+	if confTarget < 1 || confTarget > math.MaxUint32 {
+		return 0, false, fmt.Errorf("confirmation target %d is out of "+
+			"range", confTarget)
+	}
+	if maxFeeRate > 0 && minFeeRate > maxFeeRate {
+		return 0, false, fmt.Errorf("minimum fee rate %d exceeds maximum "+
+			"fee rate %d", minFeeRate, maxFeeRate)
+	}
+
+	res, err := c.EstimateSmartFee(confTarget, estimateMode)
+	if err != nil {
+		return 0, false, err
+	}
+	if res.FeeRate == nil {
+		return 0, false, fmt.Errorf("no fee estimate available: %s",
+			strings.Join(res.Errors, "; "))
+	}
+
+	feeRate, err := satPerVByte(*res.FeeRate)
+	if err != nil {
+		return 0, false, err
+	}
+
+	feeRate, clamped := clampFeeRate(feeRate, minFeeRate, maxFeeRate)
+	return feeRate, clamped, nil
 }
 
 // TODO(davec): Implement
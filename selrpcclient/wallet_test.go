@@ -0,0 +1,926 @@
+// Copyright (c) 2018 The box developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package selrpcclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcutil"
+	"github.com/zzpu/lib-bitcore/sebtcjson"
+	"reflect"
+	"sort"
+	"sync/atomic"
+	"testing"
+)
+
+// TestListTransactionsFull ensures the optional listtransactions parameters
+// are sent in order and that gaps before a provided parameter are filled in.
+func TestListTransactionsFull(t *testing.T) {
+	t.Parallel()
+
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		return []sebtcjson.ListTransactionsResult{}, nil
+	})
+	defer closeTestClient(client, server)
+
+	tests := []struct {
+		name             string
+		label            *string
+		count            *int
+		skip             *int
+		includeWatchOnly *bool
+		params           string
+	}{
+		{
+			name:   "no params",
+			params: `[]`,
+		},
+		{
+			name:   "label only",
+			label:  sebtcjson.String("savings"),
+			params: `["savings"]`,
+		},
+		{
+			name:   "wildcard label",
+			label:  sebtcjson.String("*"),
+			params: `["*"]`,
+		},
+		{
+			name:   "count sends explicit skip",
+			label:  sebtcjson.String("savings"),
+			count:  sebtcjson.Int(50),
+			params: `["savings",50,0]`,
+		},
+		{
+			name:   "count without label",
+			count:  sebtcjson.Int(50),
+			params: `["*",50,0]`,
+		},
+		{
+			name:   "skip without count",
+			skip:   sebtcjson.Int(20),
+			params: `["*",10,20]`,
+		},
+		{
+			name:             "all params",
+			label:            sebtcjson.String("savings"),
+			count:            sebtcjson.Int(50),
+			skip:             sebtcjson.Int(20),
+			includeWatchOnly: sebtcjson.Bool(true),
+			params:           `["savings",50,20,true]`,
+		},
+		{
+			name:             "watchonly only",
+			includeWatchOnly: sebtcjson.Bool(true),
+			params:           `["*",10,0,true]`,
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		_, err := client.ListTransactionsFull(test.label, test.count,
+			test.skip, test.includeWatchOnly)
+		if err != nil {
+			t.Errorf("Test #%d (%s) unexpected error: %v", i,
+				test.name, err)
+			continue
+		}
+
+		params := marshalParams(t, server.lastRequest("listtransactions"))
+		if params != test.params {
+			t.Errorf("Test #%d (%s) unexpected params - got %s, "+
+				"want %s", i, test.name, params, test.params)
+		}
+	}
+}
+
+// TestEstimateSmartFeeClamped ensures fee estimates are converted to satoshi
+// per virtual byte and clamped to the requested range.
+func TestEstimateSmartFeeClamped(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		feeRate float64
+		min     int64
+		max     int64
+		want    int64
+		clamped bool
+	}{
+		{
+			name:    "below min",
+			feeRate: 0.00001,
+			min:     2,
+			max:     100,
+			want:    2,
+			clamped: true,
+		},
+		{
+			name:    "in range",
+			feeRate: 0.0002,
+			min:     2,
+			max:     100,
+			want:    20,
+			clamped: false,
+		},
+		{
+			name:    "in range rounded up",
+			feeRate: 0.00020001,
+			min:     2,
+			max:     100,
+			want:    21,
+			clamped: false,
+		},
+		{
+			name:    "above max",
+			feeRate: 0.5,
+			min:     2,
+			max:     100,
+			want:    100,
+			clamped: true,
+		},
+		{
+			name:    "no bounds",
+			feeRate: 0.5,
+			want:    50000,
+			clamped: false,
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		feeRate := test.feeRate
+		client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+			return &sebtcjson.EstimateSmartFeeResult{
+				FeeRate: &feeRate,
+				Blocks:  6,
+			}, nil
+		})
+
+		mode := sebtcjson.EstimateModeConservative
+		got, clamped, err := client.EstimateSmartFeeClamped(6, &mode,
+			test.min, test.max)
+		closeTestClient(client, server)
+		if err != nil {
+			t.Errorf("Test #%d (%s) unexpected error: %v", i,
+				test.name, err)
+			continue
+		}
+		if got != test.want || clamped != test.clamped {
+			t.Errorf("Test #%d (%s) unexpected result - got %d "+
+				"(clamped %v), want %d (clamped %v)", i,
+				test.name, got, clamped, test.want, test.clamped)
+		}
+	}
+}
+
+// TestEstimateSmartFee ensures the estimation mode is only sent when one is
+// passed and that a missing fee rate is reported as nil along with the errors
+// of the server.
+func TestEstimateSmartFee(t *testing.T) {
+	t.Parallel()
+
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		if len(params) > 1 {
+			return json.RawMessage(`{"feerate":0.0002,"blocks":6}`), nil
+		}
+		return json.RawMessage(`{"errors":["Insufficient data or no ` +
+			`feerate found"],"blocks":0}`), nil
+	})
+	defer closeTestClient(client, server)
+
+	result, err := client.EstimateSmartFee(6, nil)
+	if err != nil {
+		t.Fatalf("EstimateSmartFee: unexpected error: %v", err)
+	}
+	if result.FeeRate != nil || len(result.Errors) != 1 {
+		t.Errorf("unexpected result without estimate: %+v", result)
+	}
+	if got := marshalParams(t, server.lastRequest("estimatesmartfee")); got != `[6]` {
+		t.Errorf("unexpected params without mode %s", got)
+	}
+
+	mode := sebtcjson.EstimateModeEconomical
+	result, err = client.EstimateSmartFee(6, &mode)
+	if err != nil {
+		t.Fatalf("EstimateSmartFee: unexpected error: %v", err)
+	}
+	if result.FeeRate == nil || *result.FeeRate != 0.0002 ||
+		result.Blocks != 6 {
+
+		t.Errorf("unexpected result with estimate: %+v", result)
+	}
+	if got := marshalParams(t, server.lastRequest("estimatesmartfee")); got != `[6,"ECONOMICAL"]` {
+		t.Errorf("unexpected params with mode %s", got)
+	}
+}
+
+// TestSetHdSeed ensures the seed is sent along with the keypool flag and that
+// empty seeds are rejected without issuing the RPC.
+func TestSetHdSeed(t *testing.T) {
+	t.Parallel()
+
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		return nil, nil
+	})
+	defer closeTestClient(client, server)
+
+	if err := client.SetHdSeed(nil, sebtcjson.String("cVWIF")); err != nil {
+		t.Fatalf("SetHdSeed: unexpected error: %v", err)
+	}
+	params := marshalParams(t, server.lastRequest("sethdseed"))
+	if want := `[true,"cVWIF"]`; params != want {
+		t.Errorf("unexpected params - got %s, want %s", params, want)
+	}
+
+	if err := client.SetHdSeed(nil, sebtcjson.String("")); err == nil {
+		t.Errorf("expected error for empty seed")
+	}
+	if n := server.calls("sethdseed"); n != 1 {
+		t.Errorf("unexpected sethdseed calls - got %d, want %d", n, 1)
+	}
+}
+
+// TestAvailableBalance ensures the trusted balance from getbalances is used
+// when supported and that getbalance is used on servers lacking getbalances.
+func TestAvailableBalance(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		getBalances bool
+		minConf     int
+		want        ltcutil.Amount
+		getBalance  string
+	}{
+		{
+			name:        "getbalances",
+			getBalances: true,
+			minConf:     6,
+			want:        ltcutil.Amount(2099999997690000),
+		},
+		{
+			name:       "getbalance fallback",
+			minConf:    6,
+			want:       ltcutil.Amount(12345678),
+			getBalance: `["*",6]`,
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		getBalances := test.getBalances
+		client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+			switch {
+			case method == "getbalances" && getBalances:
+				return json.RawMessage(`{"mine":{"trusted":20999999.9769,` +
+					`"untrusted_pending":0.5,"immature":12.5}}`), nil
+			case method == "getbalance":
+				return json.RawMessage(`0.12345678`), nil
+			}
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		})
+
+		balance, err := client.AvailableBalance(test.minConf)
+		var params string
+		if test.getBalance != "" {
+			params = marshalParams(t, server.lastRequest("getbalance"))
+		}
+		closeTestClient(client, server)
+		if err != nil {
+			t.Errorf("Test #%d (%s) unexpected error: %v", i,
+				test.name, err)
+			continue
+		}
+		if balance != test.want {
+			t.Errorf("Test #%d (%s) unexpected balance - got %d, "+
+				"want %d", i, test.name, int64(balance),
+				int64(test.want))
+		}
+		if params != test.getBalance {
+			t.Errorf("Test #%d (%s) unexpected getbalance params - "+
+				"got %s, want %s", i, test.name, params,
+				test.getBalance)
+		}
+	}
+}
+
+// TestListDescriptors ensures the listdescriptors result is decoded, including
+// the range of ranged descriptors, and that the private flag is sent.
+func TestListDescriptors(t *testing.T) {
+	t.Parallel()
+
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		if method != "listdescriptors" {
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		}
+		return json.RawMessage(`{"wallet_name":"hot","descriptors":[` +
+			`{"desc":"wpkh([d34db33f/84h/0h/0h]xpub/0/*)#abcd","timestamp":1600000000,"active":true,"internal":false,"range":[0,999],"next":12},` +
+			`{"desc":"addr(bc1q)#efgh","timestamp":1600000001,"active":false}]}`), nil
+	})
+	defer closeTestClient(client, server)
+
+	result, err := client.ListDescriptors(sebtcjson.Bool(true))
+	if err != nil {
+		t.Fatalf("ListDescriptors: unexpected error: %v", err)
+	}
+	if got, want := marshalParams(t, server.lastRequest("listdescriptors")), `[true]`; got != want {
+		t.Errorf("unexpected params - got %s, want %s", got, want)
+	}
+
+	next := 12
+	want := &sebtcjson.ListDescriptorsResult{
+		WalletName: "hot",
+		Descriptors: []sebtcjson.ListDescriptorsResultDescriptor{
+			{
+				Desc:      "wpkh([d34db33f/84h/0h/0h]xpub/0/*)#abcd",
+				Timestamp: 1600000000,
+				Active:    true,
+				Internal:  sebtcjson.Bool(false),
+				Range:     &[2]int{0, 999},
+				Next:      &next,
+			},
+			{
+				Desc:      "addr(bc1q)#efgh",
+				Timestamp: 1600000001,
+			},
+		},
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("unexpected result - got %+v, want %+v", result, want)
+	}
+}
+
+// TestImportMulti ensures importmulti requests are sent with timestamps
+// marshalled as numbers or "now" and that per-request failures are decoded.
+func TestImportMulti(t *testing.T) {
+	t.Parallel()
+
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		if method != "importmulti" {
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		}
+		return json.RawMessage(`[{"success":true},` +
+			`{"success":false,"error":{"code":-5,"message":"Invalid address"}}]`), nil
+	})
+	defer closeTestClient(client, server)
+
+	requests := []sebtcjson.ImportMultiRequest{
+		{
+			ScriptPubKey: sebtcjson.ImportMultiScriptPubKey{Address: "mkmZxiEcEd8ZqjQWVZuC6so5dFMKEFpN2j"},
+			Timestamp:    sebtcjson.TimestampOrNow{Now: true},
+			WatchOnly:    sebtcjson.Bool(true),
+		},
+		{
+			ScriptPubKey: sebtcjson.ImportMultiScriptPubKey{Address: "invalid"},
+			Timestamp:    sebtcjson.TimestampOrNow{Value: 1600000000},
+			Label:        sebtcjson.String("cold"),
+		},
+	}
+	results, err := client.ImportMulti(requests,
+		&sebtcjson.ImportMultiOptions{Rescan: sebtcjson.Bool(false)})
+	if err != nil {
+		t.Fatalf("ImportMulti: unexpected error: %v", err)
+	}
+
+	params := marshalParams(t, server.lastRequest("importmulti"))
+	wantParams := `[[{"scriptPubKey":{"address":"mkmZxiEcEd8ZqjQWVZuC6so5dFMKEFpN2j"},"timestamp":"now","watchonly":true},` +
+		`{"scriptPubKey":{"address":"invalid"},"timestamp":1600000000,"label":"cold"}],` +
+		`{"rescan":false}]`
+	if params != wantParams {
+		t.Errorf("unexpected params - got %s, want %s", params, wantParams)
+	}
+
+	want := []sebtcjson.ImportMultiResult{
+		{Success: true},
+		{
+			Success: false,
+			Error: &sebtcjson.RPCError{
+				Code:    sebtcjson.ErrRPCInvalidAddressOrKey,
+				Message: "Invalid address",
+			},
+		},
+	}
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("unexpected results - got %+v, want %+v", results, want)
+	}
+}
+
+// TestDefaultAddressType ensures the configured default address type is sent
+// by GetNewAddress and GetRawChangeAddress when no address type is passed and
+// that a passed address type overrides it.
+func TestDefaultAddressType(t *testing.T) {
+	t.Parallel()
+
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		return "address", nil
+	})
+	defer closeTestClient(client, server)
+
+	tests := []struct {
+		name        string
+		defaultType string
+		call        func() chan *response
+		method      string
+		params      string
+	}{
+		{
+			name: "getnewaddress no default",
+			call: func() chan *response {
+				return client.GetNewAddressAsync("acct")
+			},
+			method: "getnewaddress",
+			params: `["acct"]`,
+		},
+		{
+			name:        "getnewaddress default",
+			defaultType: "bech32",
+			call: func() chan *response {
+				return client.GetNewAddressAsync("acct")
+			},
+			method: "getnewaddress",
+			params: `["acct","bech32"]`,
+		},
+		{
+			name:        "getnewaddress override",
+			defaultType: "bech32",
+			call: func() chan *response {
+				return client.GetNewAddressTypeAsync("acct",
+					sebtcjson.String("legacy"))
+			},
+			method: "getnewaddress",
+			params: `["acct","legacy"]`,
+		},
+		{
+			name: "getrawchangeaddress no default",
+			call: func() chan *response {
+				return client.GetRawChangeAddressAsync("acct")
+			},
+			method: "getrawchangeaddress",
+			params: `["acct"]`,
+		},
+		{
+			name:        "getrawchangeaddress default",
+			defaultType: "bech32",
+			call: func() chan *response {
+				return client.GetRawChangeAddressAsync("")
+			},
+			method: "getrawchangeaddress",
+			params: `["bech32"]`,
+		},
+		{
+			name:        "getrawchangeaddress override",
+			defaultType: "bech32",
+			call: func() chan *response {
+				return client.GetRawChangeAddressTypeAsync(
+					sebtcjson.String("p2sh-segwit"))
+			},
+			method: "getrawchangeaddress",
+			params: `["p2sh-segwit"]`,
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		client.config.DefaultAddressType = test.defaultType
+		if _, err := receiveFuture(test.call()); err != nil {
+			t.Errorf("Test #%d (%s) unexpected error: %v", i,
+				test.name, err)
+			continue
+		}
+		params := marshalParams(t, server.lastRequest(test.method))
+		if params != test.params {
+			t.Errorf("Test #%d (%s) unexpected params - got %s, "+
+				"want %s", i, test.name, params, test.params)
+		}
+	}
+
+	// An account can not be sent in place of the default address type.
+	client.config.DefaultAddressType = "bech32"
+	calls := server.calls("getrawchangeaddress")
+	if _, err := client.GetRawChangeAddress("acct"); err == nil {
+		t.Errorf("GetRawChangeAddress: expected error for account with " +
+			"default address type")
+	}
+	if got := server.calls("getrawchangeaddress"); got != calls {
+		t.Errorf("getrawchangeaddress was sent along with an account")
+	}
+}
+
+// TestParseAndValidateAddress ensures addresses are checked against the
+// configured network and the server, and that addresses the server reports as
+// invalid are returned as such without a transport error.
+func TestParseAndValidateAddress(t *testing.T) {
+	t.Parallel()
+
+	program := make([]byte, 20)
+	mainAddr, err := ltcutil.NewAddressWitnessPubKeyHash(program,
+		&chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("NewAddressWitnessPubKeyHash: unexpected error: %v", err)
+	}
+	regtestAddr, err := ltcutil.NewAddressWitnessPubKeyHash(program,
+		&chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatalf("NewAddressWitnessPubKeyHash: unexpected error: %v", err)
+	}
+
+	var valid int32 = 1
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		if method != "validateaddress" {
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		}
+		if atomic.LoadInt32(&valid) == 0 {
+			return &sebtcjson.ValidateAddressWalletResult{
+				IsValid: false,
+				Error:   "Invalid checksum",
+			}, nil
+		}
+		var addr string
+		json.Unmarshal(params[0], &addr)
+		return &sebtcjson.ValidateAddressWalletResult{
+			IsValid:        true,
+			Address:        addr,
+			IsWitness:      true,
+			WitnessVersion: sebtcjson.Int32(0),
+		}, nil
+	})
+	defer closeTestClient(client, server)
+
+	addr, err := client.ParseAndValidateAddress(mainAddr.EncodeAddress())
+	if err != nil {
+		t.Fatalf("ParseAndValidateAddress: unexpected error: %v", err)
+	}
+	if addr.EncodeAddress() != mainAddr.EncodeAddress() {
+		t.Errorf("unexpected address - got %v, want %v", addr, mainAddr)
+	}
+
+	calls := server.calls("validateaddress")
+	if _, err := client.ParseAndValidateAddress(regtestAddr.EncodeAddress()); err == nil {
+		t.Errorf("expected error for address of another network")
+	}
+	if n := server.calls("validateaddress"); n != calls {
+		t.Errorf("unexpected validateaddress call for address of " +
+			"another network")
+	}
+
+	atomic.StoreInt32(&valid, 0)
+	result, err := client.ValidateAddressString("not-an-address")
+	if err != nil {
+		t.Fatalf("ValidateAddressString: unexpected error: %v", err)
+	}
+	if result.IsValid {
+		t.Errorf("expected address to be reported invalid")
+	}
+	if _, err := client.ParseAndValidateAddress(mainAddr.EncodeAddress()); err == nil {
+		t.Errorf("expected error for address reported invalid")
+	}
+}
+
+// TestGetAddressesByLabel ensures the addresses with a label are returned with
+// their purpose and are decoded for the configured network.
+func TestGetAddressesByLabel(t *testing.T) {
+	t.Parallel()
+
+	var addrs []ltcutil.Address
+	for i := byte(1); i <= 2; i++ {
+		addr, err := ltcutil.NewAddressWitnessPubKeyHash(
+			bytes.Repeat([]byte{i}, 20), &chaincfg.RegressionNetParams)
+		if err != nil {
+			t.Fatalf("NewAddressWitnessPubKeyHash: unexpected error: %v",
+				err)
+		}
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return addrs[i].EncodeAddress() < addrs[j].EncodeAddress()
+	})
+	want := map[string]sebtcjson.GetAddressesByLabelResult{
+		addrs[0].EncodeAddress(): {Purpose: "receive"},
+		addrs[1].EncodeAddress(): {Purpose: "send"},
+	}
+
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		if method != "getaddressesbylabel" {
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		}
+		return want, nil
+	})
+	defer closeTestClient(client, server)
+	client.config.ChainParams = &chaincfg.RegressionNetParams
+
+	got, err := client.GetAddressesByLabel("savings")
+	if err != nil {
+		t.Fatalf("GetAddressesByLabel: unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAddressesByLabel: unexpected result - got %v, "+
+			"want %v", got, want)
+	}
+	if params := server.lastRequest("getaddressesbylabel").Params; len(params) != 1 ||
+		string(params[0]) != `"savings"` {
+		t.Errorf("GetAddressesByLabel: unexpected params %s", params)
+	}
+
+	decoded, err := client.DecodeLabelAddresses(got)
+	if err != nil {
+		t.Fatalf("DecodeLabelAddresses: unexpected error: %v", err)
+	}
+	if len(decoded) != len(addrs) {
+		t.Fatalf("DecodeLabelAddresses: got %d addresses, want %d",
+			len(decoded), len(addrs))
+	}
+	for i, addr := range decoded {
+		if addr.EncodeAddress() != addrs[i].EncodeAddress() {
+			t.Errorf("Test #%d unexpected address - got %s, want %s",
+				i, addr.EncodeAddress(), addrs[i].EncodeAddress())
+		}
+	}
+}
+
+// TestWalletNotLoaded ensures errors of wallet methods reporting that the
+// wallet is not loaded match ErrWalletNotLoaded while still unwrapping to the
+// server error, and that errors of other methods never do.
+func TestWalletNotLoaded(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		err       *sebtcjson.RPCError
+		notLoaded bool
+	}{
+		{
+			name: "no wallet loaded",
+			err: sebtcjson.NewRPCError(sebtcjson.ErrRPCWalletNotFound,
+				"No wallet is loaded. Load a wallet using loadwallet "+
+					"or create a new one with createwallet. (Note: A "+
+					"default wallet is no longer automatically created)"),
+			notLoaded: true,
+		},
+		{
+			name: "wallet does not exist",
+			err: sebtcjson.NewRPCError(sebtcjson.ErrRPCWalletNotFound,
+				"Requested wallet does not exist or is not loaded"),
+			notLoaded: true,
+		},
+		{
+			name: "message only",
+			err: sebtcjson.NewRPCError(sebtcjson.ErrRPCWallet,
+				"Requested wallet does not exist or is not loaded"),
+			notLoaded: true,
+		},
+		{
+			name: "other wallet error",
+			err: sebtcjson.NewRPCError(sebtcjson.ErrRPCWalletUnlockNeeded,
+				"Please enter the wallet passphrase with "+
+					"walletpassphrase first."),
+			notLoaded: false,
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+			return nil, test.err
+		})
+		_, err := client.GetBalances()
+		closeTestClient(client, server)
+
+		if got := errors.Is(err, ErrWalletNotLoaded); got != test.notLoaded {
+			t.Errorf("Test #%d (%s) unexpected errors.Is - got %v, "+
+				"want %v", i, test.name, got, test.notLoaded)
+		}
+		var rpcErr *sebtcjson.RPCError
+		if !errors.As(err, &rpcErr) || rpcErr.Code != test.err.Code {
+			t.Errorf("Test #%d (%s) error does not unwrap to the "+
+				"server error: %v", i, test.name, err)
+		}
+	}
+
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		return nil, sebtcjson.NewRPCError(sebtcjson.ErrRPCWalletNotFound,
+			"Requested wallet does not exist or is not loaded")
+	})
+	defer closeTestClient(client, server)
+	if _, err := client.GetBlockCount(); errors.Is(err, ErrWalletNotLoaded) {
+		t.Errorf("error of a chain method matches ErrWalletNotLoaded: %v",
+			err)
+	}
+}
+
+// TestGetAllReceivedByLabel ensures the totals of every label, including empty
+// labels and the default label, are returned.
+func TestGetAllReceivedByLabel(t *testing.T) {
+	t.Parallel()
+
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		if method != "listreceivedbylabel" {
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		}
+		return []sebtcjson.ListReceivedByLabelResult{
+			{Label: "", Amount: 0.5, Confirmations: 3},
+			{Label: "savings", Amount: 1.25, Confirmations: 10},
+			{Label: "unused"},
+		}, nil
+	})
+	defer closeTestClient(client, server)
+
+	totals, err := client.GetAllReceivedByLabel(2, true)
+	if err != nil {
+		t.Fatalf("GetAllReceivedByLabel: unexpected error: %v", err)
+	}
+	want := map[string]ltcutil.Amount{
+		"":        ltcutil.Amount(50000000),
+		"savings": ltcutil.Amount(125000000),
+		"unused":  0,
+	}
+	if !reflect.DeepEqual(totals, want) {
+		t.Errorf("GetAllReceivedByLabel: got %v, want %v", totals, want)
+	}
+
+	if got, want := marshalParams(t, server.lastRequest("listreceivedbylabel")), `[2,true,true]`; got != want {
+		t.Errorf("GetAllReceivedByLabel: unexpected params - got %s, "+
+			"want %s", got, want)
+	}
+}
+
+// TestSyncWalletSince ensures the transactions added and removed since a block
+// are returned along with the block to sync from next and whether a
+// reorganization occurred.
+func TestSyncWalletSince(t *testing.T) {
+	t.Parallel()
+
+	since := chainhash.Hash{0x01}
+	tip := chainhash.Hash{0x02}
+	result := sebtcjson.ListSinceBlockResult{
+		Transactions: []sebtcjson.ListTransactionsResult{
+			{TxID: "aa", Category: "receive", Confirmations: 1},
+			{TxID: "bb", Category: "send"},
+		},
+		Removed: []sebtcjson.ListTransactionsResult{
+			{TxID: "cc", Category: "receive", Confirmations: -1},
+		},
+		LastBlock: tip.String(),
+	}
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		if method != "listsinceblock" {
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		}
+		return &result, nil
+	})
+	defer closeTestClient(client, server)
+
+	changes, err := client.SyncWalletSince(&since)
+	if err != nil {
+		t.Fatalf("SyncWalletSince: unexpected error: %v", err)
+	}
+	if got, want := marshalParams(t, server.lastRequest("listsinceblock")), `["`+since.String()+`"]`; got != want {
+		t.Errorf("SyncWalletSince: unexpected params - got %s, want %s",
+			got, want)
+	}
+	if !reflect.DeepEqual(changes.Added, result.Transactions) {
+		t.Errorf("SyncWalletSince: unexpected added transactions - got "+
+			"%+v", changes.Added)
+	}
+	if !reflect.DeepEqual(changes.Removed, result.Removed) {
+		t.Errorf("SyncWalletSince: unexpected removed transactions - "+
+			"got %+v", changes.Removed)
+	}
+	if !changes.Reorged() {
+		t.Errorf("SyncWalletSince: reorganization not reported")
+	}
+	if *changes.LastBlock != tip {
+		t.Errorf("SyncWalletSince: unexpected last block - got %v, want "+
+			"%v", changes.LastBlock, tip)
+	}
+
+	result.Removed = nil
+	changes, err = client.SyncWalletSince(changes.LastBlock)
+	if err != nil {
+		t.Fatalf("SyncWalletSince: unexpected error: %v", err)
+	}
+	if changes.Reorged() {
+		t.Errorf("SyncWalletSince: unexpected reorganization")
+	}
+}
+
+// TestSignMessage ensures signmessage is sent with the encoded address and that
+// a locked wallet is reported with an error matching ErrWalletLocked.
+func TestSignMessage(t *testing.T) {
+	t.Parallel()
+
+	addr, err := ltcutil.NewAddressPubKeyHash(make([]byte, 20),
+		&chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("NewAddressPubKeyHash: unexpected error: %v", err)
+	}
+
+	var locked int32
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		if method != "signmessage" {
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		}
+		if atomic.LoadInt32(&locked) != 0 {
+			return nil, sebtcjson.NewRPCError(sebtcjson.ErrRPCWalletUnlockNeeded,
+				"Error: Please enter the wallet passphrase with "+
+					"walletpassphrase first.")
+		}
+		return "c2lnbmF0dXJl", nil
+	})
+	defer closeTestClient(client, server)
+
+	sig, err := client.SignMessage(addr, "hello")
+	if err != nil {
+		t.Fatalf("SignMessage: unexpected error: %v", err)
+	}
+	if sig != "c2lnbmF0dXJl" {
+		t.Errorf("SignMessage: got %q, want %q", sig, "c2lnbmF0dXJl")
+	}
+	params := marshalParams(t, server.lastRequest("signmessage"))
+	want := `["` + addr.EncodeAddress() + `","hello"]`
+	if params != want {
+		t.Errorf("signmessage params: got %s, want %s", params, want)
+	}
+
+	atomic.StoreInt32(&locked, 1)
+	_, err = client.SignMessage(addr, "hello")
+	if !errors.Is(err, ErrWalletLocked) {
+		t.Errorf("SignMessage on locked wallet: got %v, want %v", err,
+			ErrWalletLocked)
+	}
+	var rpcErr *sebtcjson.RPCError
+	if !errors.As(err, &rpcErr) ||
+		rpcErr.Code != sebtcjson.ErrRPCWalletUnlockNeeded {
+
+		t.Errorf("SignMessage on locked wallet: error %v does not "+
+			"unwrap to the server error", err)
+	}
+}
+
+// TestGetTransactions ensures wallet transactions are requested in a single
+// batch and that transactions which can not be returned only fail their own
+// position.
+func TestGetTransactions(t *testing.T) {
+	t.Parallel()
+
+	found := chainhash.Hash{0x01}
+	missing := chainhash.Hash{0x02}
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		if method != "gettransaction" {
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		}
+		var txid string
+		json.Unmarshal(params[0], &txid)
+		if txid != found.String() {
+			return nil, sebtcjson.NewRPCError(sebtcjson.ErrRPCInvalidAddressOrKey,
+				"Invalid or non-wallet transaction id")
+		}
+		return json.RawMessage(`{"amount":0.5,"confirmations":3,` +
+			`"txid":"` + txid + `","details":[{"address":"addr",` +
+			`"category":"receive","amount":0.5,"vout":1}]}`), nil
+	})
+	defer closeTestClient(client, server)
+
+	results, errs, err := client.GetTransactions(
+		[]*chainhash.Hash{&missing, &found, &missing}, true)
+	if err != nil {
+		t.Fatalf("GetTransactions: unexpected error: %v", err)
+	}
+	if len(results) != 3 || len(errs) != 3 {
+		t.Fatalf("GetTransactions: got %d results and %d errors, want 3",
+			len(results), len(errs))
+	}
+	for _, i := range []int{0, 2} {
+		rpcErr, ok := errs[i].(*sebtcjson.RPCError)
+		if !ok || rpcErr.Code != sebtcjson.ErrRPCInvalidAddressOrKey {
+			t.Errorf("result #%d: unexpected error %v", i, errs[i])
+		}
+		if results[i] != nil {
+			t.Errorf("result #%d: unexpected result %v", i, results[i])
+		}
+	}
+	if errs[1] != nil {
+		t.Fatalf("result #1: unexpected error: %v", errs[1])
+	}
+	if results[1].TxID != found.String() || len(results[1].Details) != 1 ||
+		results[1].Details[0].Vout != 1 {
+
+		t.Errorf("result #1: unexpected result %+v", results[1])
+	}
+
+	server.mtx.Lock()
+	posts := len(server.paths)
+	server.mtx.Unlock()
+	if posts != 1 {
+		t.Errorf("got %d HTTP requests, want 1", posts)
+	}
+	params := marshalParams(t, server.lastRequest("gettransaction"))
+	if want := `["` + missing.String() + `",true]`; params != want {
+		t.Errorf("gettransaction params: got %s, want %s", params, want)
+	}
+}
@@ -0,0 +1,222 @@
+// Copyright (c) 2018 The box developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package serpcclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/zzpu/lib-bitcore/sebtcjson"
+	"io/ioutil"
+	"sync"
+)
+
+var (
+	// ErrBatchSent is returned when a batch is sent more than once and
+	// delivered to the futures of requests queued after it was sent.
+	ErrBatchSent = errors.New("batch has already been sent")
+
+	// ErrBatchNotSupported is returned when a batch is sent by a client
+	// which is not running in HTTP POST mode.
+	ErrBatchNotSupported = errors.New("batches are only supported in " +
+		"HTTP POST mode")
+
+	// ErrBatchNoResponse is delivered to the future of a batched request
+	// the server did not answer.
+	ErrBatchNoResponse = errors.New("server did not answer batched " +
+		"request")
+)
+
+// BatchClient queues the requests issued through the Async methods of its
+// embedded client instead of sending them, and sends all of them to the server
+// as a single JSON-RPC 2.0 batch in one HTTP POST request once Send is called.  The futures returned by
+// the Async methods resolve once the batch is sent, each with the response the
+// server gave for its own request, so some requests of a batch may fail while
+// others succeed.
+//
+// The blocking methods of the embedded client wait for their response, which
+// only arrives once the batch is sent, so only the Async methods should be used
+// until then.
+type BatchClient struct {
+	*Client
+
+	mtx      sync.Mutex
+	requests []*jsonRequest
+	sent     bool
+}
+
+// Batch returns a client whose requests are queued on a batch until it is sent.
+// Like a client returned by Clone, it shares the connection of the client it is
+// called on.
+func (c *Client) Batch() *BatchClient {
+	b := &BatchClient{Client: c.Clone()}
+	b.Client.batch = b
+	return b
+}
+
+// queue adds the passed request to the batch, or fails it when the batch was
+// already sent.
+func (b *BatchClient) queue(jReq *jsonRequest) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if b.sent {
+		jReq.responseChan <- &response{err: ErrBatchSent}
+		return
+	}
+	b.requests = append(b.requests, jReq)
+}
+
+// Send sends all of the queued requests to the server and delivers the
+// responses to their futures, matching them by id since the server may answer
+// in any order.  A request which is not answered fails with
+// ErrBatchNoResponse.  The RequestHeaders hook of the connection configuration
+// is invoked once for the batch with the method "batch".
+//
+// An error is returned, and delivered to every queued future, when the batch as
+// a whole fails, such as when the server can not be reached.  A batch may only
+// be sent once.
+func (b *BatchClient) Send() error {
+	return b.SendCtx(context.Background())
+}
+
+// SendCtx is like Send, but the batch is abandoned when the passed context is
+// done before the server answers.  Every queued future then resolves with the
+// error of the context, which is also returned.  The context is also handed to
+// the RequestHeaders hook of the connection configuration.
+func (b *BatchClient) SendCtx(ctx context.Context) error {
+	b.mtx.Lock()
+	if b.sent {
+		b.mtx.Unlock()
+		return ErrBatchSent
+	}
+	b.sent = true
+	requests := b.requests
+	b.requests = nil
+	b.mtx.Unlock()
+
+	if len(requests) == 0 {
+		return nil
+	}
+
+	responses, err := b.send(ctx, requests)
+	if err != nil {
+		// The HTTP client wraps the error of the context.
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = ctxErr
+		}
+		for _, jReq := range requests {
+			jReq.responseChan <- &response{err: err}
+		}
+		return err
+	}
+
+	for _, jReq := range requests {
+		resp, ok := responses[jReq.id]
+		if !ok {
+			jReq.responseChan <- &response{err: ErrBatchNoResponse}
+			continue
+		}
+		delete(responses, jReq.id)
+		res, err := resp.result(jReq.method)
+		jReq.responseChan <- &response{result: res, err: err}
+	}
+
+	// Responses left over carry ids which were not part of the batch.
+	for id := range responses {
+		b.logger().Warnf("Received batched response with unexpected "+
+			"id %d", id)
+	}
+	return nil
+}
+
+// send issues the passed requests to the server as a JSON-RPC 2.0 batch and
+// returns the responses keyed by id.
+func (b *BatchClient) send(ctx context.Context, requests []*jsonRequest) (map[uint64]*rawResponse, error) {
+	if !b.config.HTTPPostMode {
+		return nil, ErrBatchNotSupported
+	}
+	select {
+	case <-b.shutdown:
+		return nil, ErrClientShutdown
+	default:
+	}
+
+	ids := make([]interface{}, 0, len(requests))
+	cmds := make([]interface{}, 0, len(requests))
+	for _, jReq := range requests {
+		cmd := jReq.cmd
+		if cmd == nil {
+			// Raw requests have no registered command, so the request
+			// they were marshalled to is sent instead.
+			var req sebtcjson.Request
+			if err := json.Unmarshal(jReq.marshalledJSON, &req); err != nil {
+				return nil, err
+			}
+			cmd = &req
+		}
+		ids = append(ids, jReq.id)
+		cmds = append(cmds, cmd)
+		log.Tracef("Batching command [%s] with id %d", jReq.method,
+			jReq.id)
+	}
+	body, err := sebtcjson.MarshalCmds(ids, cmds)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := b.newPostRequest(ctx, "batch", body)
+	if err != nil {
+		return nil, err
+	}
+	httpReq = httpReq.WithContext(ctx)
+	log.Tracef("Sending batch of %d commands", len(requests))
+	httpResponse, err := b.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	respBytes, err := ioutil.ReadAll(httpResponse.Body)
+	httpResponse.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error reading json reply: %v", err)
+	}
+
+	batch, err := sebtcjson.UnmarshalResponses(respBytes)
+	if err != nil {
+		if rpcErr, ok := err.(*sebtcjson.RPCError); ok {
+			_, err := rawResponse{Error: rpcErr}.result("")
+			return nil, err
+		}
+		return nil, fmt.Errorf("status code: %d, response: %q",
+			httpResponse.StatusCode, string(respBytes))
+	}
+
+	responses := make(map[uint64]*rawResponse, len(batch))
+	for _, resp := range batch {
+		id, ok := batchResponseID(resp.ID)
+		if !ok {
+			b.logger().Warnf("Received batched response without " +
+				"valid id")
+			continue
+		}
+		responses[id] = &rawResponse{Result: resp.Result, Error: resp.Error}
+	}
+	return responses, nil
+}
+
+// batchResponseID returns the request id the passed response id refers to and
+// whether it is valid.  Request ids are unsigned integers, which are decoded as
+// float64 values.
+func batchResponseID(id *interface{}) (uint64, bool) {
+	if id == nil {
+		return 0, false
+	}
+	f, ok := (*id).(float64)
+	if !ok || f < 0 || f != float64(uint64(f)) {
+		return 0, false
+	}
+	return uint64(f), true
+}
@@ -0,0 +1,207 @@
+// Copyright (c) 2018 The box developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package serpcclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/zzpu/lib-bitcore/sebtcjson"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestBatchMixedResults ensures every future of a batch resolves with the
+// response to its own request when the server answers out of order and some of
+// the requests fail.
+func TestBatchMixedResults(t *testing.T) {
+	t.Parallel()
+
+	hash := chainhash.Hash{0x01}
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		switch method {
+		case "getblockcount":
+			return 100, nil
+
+		case "getblockhash":
+			var height int64
+			json.Unmarshal(params[0], &height)
+			if height > 100 {
+				return nil, sebtcjson.NewRPCError(sebtcjson.ErrRPCOutOfRange,
+					"Block height out of range")
+			}
+			return hash.String(), nil
+		}
+		return nil, sebtcjson.ErrRPCMethodNotFound
+	})
+	defer closeTestClient(client, server)
+
+	batch := client.Batch()
+	count := batch.GetBlockCountAsync()
+	outOfRange := batch.GetBlockHashAsync(101)
+	found := batch.GetBlockHashAsync(10)
+	unknown := batch.GetDifficultyAsync()
+	raw := batch.RawRequestAsync("getblockcount", nil)
+	if got := server.calls("getblockcount"); got != 0 {
+		t.Fatalf("batched request sent before Send")
+	}
+	if err := batch.Send(); err != nil {
+		t.Fatalf("Send: unexpected error: %v", err)
+	}
+
+	if got, err := count.Receive(); err != nil || got != 100 {
+		t.Errorf("getblockcount: got %d, %v, want 100", got, err)
+	}
+	if got, err := found.Receive(); err != nil || *got != hash {
+		t.Errorf("getblockhash: got %v, %v, want %v", got, err, hash)
+	}
+	if got, err := raw.Receive(); err != nil || string(got) != "100" {
+		t.Errorf("raw getblockcount: got %s, %v, want 100", got, err)
+	}
+	_, err := outOfRange.Receive()
+	if rpcErr, ok := err.(*sebtcjson.RPCError); !ok ||
+		rpcErr.Code != sebtcjson.ErrRPCOutOfRange {
+
+		t.Errorf("getblockhash out of range: unexpected error %v", err)
+	}
+	_, err = unknown.Receive()
+	if rpcErr, ok := err.(*sebtcjson.RPCError); !ok ||
+		rpcErr.Code != sebtcjson.ErrRPCMethodNotFound.Code {
+
+		t.Errorf("getdifficulty: unexpected error %v", err)
+	}
+
+	// The whole batch is issued as one HTTP request.
+	server.mtx.Lock()
+	posts := len(server.paths)
+	server.mtx.Unlock()
+	if posts != 1 {
+		t.Errorf("got %d HTTP requests, want 1", posts)
+	}
+
+	// Requests queued after the batch was sent fail immediately.
+	if _, err := batch.GetBlockCountAsync().Receive(); err != ErrBatchSent {
+		t.Errorf("request after Send: got %v, want %v", err, ErrBatchSent)
+	}
+	if err := batch.Send(); err != ErrBatchSent {
+		t.Errorf("second Send: got %v, want %v", err, ErrBatchSent)
+	}
+}
+
+// TestBatchSendCtxCancel ensures cancelling the context of a batch in flight
+// resolves every queued future with the error of the context.
+func TestBatchSendCtxCancel(t *testing.T) {
+	t.Parallel()
+
+	received := make(chan struct{}, 1)
+	release := make(chan struct{})
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		select {
+		case received <- struct{}{}:
+		default:
+		}
+		<-release
+		return 100, nil
+	})
+	defer closeTestClient(client, server)
+	defer close(release)
+
+	batch := client.Batch()
+	futures := []FutureGetBlockCountResult{
+		batch.GetBlockCountAsync(),
+		batch.GetBlockCountAsync(),
+		batch.GetBlockCountAsync(),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-received
+		cancel()
+	}()
+	if err := batch.SendCtx(ctx); err != context.Canceled {
+		t.Fatalf("SendCtx: got %v, want %v", err, context.Canceled)
+	}
+	for i, future := range futures {
+		if _, err := future.Receive(); err != context.Canceled {
+			t.Errorf("future #%d: got %v, want %v", i, err,
+				context.Canceled)
+		}
+	}
+}
+
+// TestBatchJSONRPC2 ensures batches are sent as JSON-RPC 2.0 requests and that
+// the responses of a JSON-RPC 2.0 server, which omit the result member of
+// failed requests, are delivered to the right futures.
+func TestBatchJSONRPC2(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		var reqs []struct {
+			Jsonrpc string            `json:"jsonrpc"`
+			Method  string            `json:"method"`
+			Params  []json.RawMessage `json:"params"`
+			ID      uint64            `json:"id"`
+		}
+		if err := json.Unmarshal(body, &reqs); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		replies := make([]string, 0, len(reqs))
+		for _, req := range reqs {
+			switch {
+			case req.Jsonrpc != "2.0":
+				replies = append(replies, fmt.Sprintf(`{"jsonrpc":"2.0",`+
+					`"error":{"code":-32600,"message":"version %q"},`+
+					`"id":%d}`, req.Jsonrpc, req.ID))
+			case req.Method == "getblockcount":
+				replies = append(replies, fmt.Sprintf(`{"jsonrpc":"2.0",`+
+					`"result":100,"id":%d}`, req.ID))
+			default:
+				replies = append(replies, fmt.Sprintf(`{"jsonrpc":"2.0",`+
+					`"error":{"code":-32601,"message":"Method not found"},`+
+					`"id":%d}`, req.ID))
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, "[%s]", strings.Join(replies, ","))
+	}))
+	defer server.Close()
+
+	client, err := New(&ConnConfig{
+		Host:         strings.TrimPrefix(server.URL, "http://"),
+		DisableTLS:   true,
+		HTTPPostMode: true,
+	}, nil)
+	if err != nil {
+		t.Fatalf("New: unexpected error: %v", err)
+	}
+	defer func() {
+		client.Shutdown()
+		client.WaitForShutdown()
+	}()
+
+	batch := client.Batch()
+	count := batch.GetBlockCountAsync()
+	unknown := batch.GetDifficultyAsync()
+	if err := batch.Send(); err != nil {
+		t.Fatalf("Send: unexpected error: %v", err)
+	}
+
+	if got, err := count.Receive(); err != nil || got != 100 {
+		t.Errorf("getblockcount: got %d, %v, want 100", got, err)
+	}
+	_, err = unknown.Receive()
+	if rpcErr, ok := err.(*sebtcjson.RPCError); !ok ||
+		rpcErr.Code != sebtcjson.ErrRPCMethodNotFound.Code {
+
+		t.Errorf("getdifficulty: unexpected error %v", err)
+	}
+}
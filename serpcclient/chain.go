@@ -7,11 +7,21 @@ package serpcclient
 
 import (
 	"bytes"
+	"context"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/gcs"
+	"github.com/btcsuite/btcutil/gcs/builder"
 	"github.com/zzpu/lib-bitcore/sebtcjson"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
 )
 
 // FutureGetBestBlockHashResult is a future promise to deliver the result of a
@@ -51,16 +61,25 @@ func (c *Client) GetBestBlockHash() (*chainhash.Hash, error) {
 	return c.GetBestBlockHashAsync().Receive()
 }
 
+// GetBestBlockHashCtx is like GetBestBlockHash, but returns the error of the
+// passed context once it is done before the server answers.
+func (c *Client) GetBestBlockHashCtx(ctx context.Context) (*chainhash.Hash, error) {
+	cmd := sebtcjson.NewGetBestBlockHashCmd()
+	return FutureGetBestBlockHashResult(c.sendCmdCtx(ctx, cmd)).Receive()
+}
+
 // FutureGetBlockResult is a future promise to deliver the result of a
 // GetBlockAsync RPC invocation (or an applicable error).
 type FutureGetBlockResult chan *response
 
 // Receive waits for the response promised by the future and returns the raw
-// block requested from the server given its hash.
+// block requested from the server given its hash.  An error matching
+// ErrBlockPruned with errors.Is is returned when the server no longer stores
+// the block.
 func (r FutureGetBlockResult) Receive() (*wire.MsgBlock, error) {
 	res, err := receiveFuture(r)
 	if err != nil {
-		return nil, err
+		return nil, newPrunedBlockError(err)
 	}
 
 	// Unmarshal result as a string.
@@ -108,6 +127,42 @@ func (c *Client) GetBlock(blockHash *chainhash.Hash) (*wire.MsgBlock, error) {
 	return c.GetBlockAsync(blockHash).Receive()
 }
 
+// GetBlockCtx is like GetBlock, but returns the error of the passed context
+// once it is done before the block is received.
+func (c *Client) GetBlockCtx(ctx context.Context, blockHash *chainhash.Hash) (*wire.MsgBlock, error) {
+	hash := ""
+	if blockHash != nil {
+		hash = blockHash.String()
+	}
+
+	cmd := sebtcjson.NewGetBlockCmd(hash, sebtcjson.Bool(false), nil)
+	return FutureGetBlockResult(c.sendCmdCtx(ctx, cmd)).Receive()
+}
+
+// GetBlockByHeight returns a raw block from the server given its height in the
+// main chain.
+//
+// When the server is pruned, ErrBlockPruned is returned without requesting
+// blocks below its prune height.  The prune height is fetched with
+// getblockchaininfo and cached until a block is connected or it is ten minutes
+// old.  The check is skipped entirely once the server reports that it is not
+// pruned.
+func (c *Client) GetBlockByHeight(blockHeight int64) (*wire.MsgBlock, error) {
+	pruneHeight, err := c.pruneHeight()
+	if err != nil {
+		return nil, err
+	}
+	if blockHeight < int64(pruneHeight) {
+		return nil, ErrBlockPruned
+	}
+
+	blockHash, err := c.GetBlockHash(blockHeight)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetBlock(blockHash)
+}
+
 // FutureGetBlockVerboseResult is a future promise to deliver the result of a
 // GetBlockVerboseAsync RPC invocation (or an applicable error).
 type FutureGetBlockVerboseResult chan *response
@@ -153,30 +208,106 @@ func (c *Client) GetBlockVerbose(blockHash *chainhash.Hash) (*sebtcjson.GetBlock
 	return c.GetBlockVerboseAsync(blockHash).Receive()
 }
 
+// GetBlockVerboseCtx is like GetBlockVerbose, but returns the error of the
+// passed context once it is done before the block is received.
+func (c *Client) GetBlockVerboseCtx(ctx context.Context, blockHash *chainhash.Hash) (*sebtcjson.GetBlockVerboseResult, error) {
+	hash := ""
+	if blockHash != nil {
+		hash = blockHash.String()
+	}
+
+	cmd := sebtcjson.NewGetBlockCmd(hash, sebtcjson.Bool(true), nil)
+	return FutureGetBlockVerboseResult(c.sendCmdCtx(ctx, cmd)).Receive()
+}
+
+// FutureGetBlockVerboseTxResult is a future promise to deliver the result of a
+// GetBlockVerboseTxAsync RPC invocation (or an applicable error).
+type FutureGetBlockVerboseTxResult chan *response
+
+// Receive waits for the response promised by the future and returns the data
+// structure from the server with information about the requested block and its
+// transactions.
+func (r FutureGetBlockVerboseTxResult) Receive() (*sebtcjson.GetBlockVerboseTxResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal the raw result into a GetBlockVerboseTxResult.
+	var blockResult sebtcjson.GetBlockVerboseTxResult
+	err = json.Unmarshal(res, &blockResult)
+	if err != nil {
+		return nil, err
+	}
+	return &blockResult, nil
+}
+
 // GetBlockVerboseTxAsync returns an instance of a type that can be used to get
 // the result of the RPC at some future time by invoking the Receive function on
 // the returned instance.
 //
 // See GetBlockVerboseTx or the blocking version and more details.
-func (c *Client) GetBlockVerboseTxAsync(blockHash *chainhash.Hash) FutureGetBlockVerboseResult {
+func (c *Client) GetBlockVerboseTxAsync(blockHash *chainhash.Hash) FutureGetBlockVerboseTxResult {
 	hash := ""
 	if blockHash != nil {
 		hash = blockHash.String()
 	}
 
-	cmd := sebtcjson.NewGetBlockCmd(hash, sebtcjson.Bool(true), sebtcjson.Bool(true))
-	return c.sendCmd(cmd)
+	// The verbosity of 2 can not be expressed with the boolean verbose
+	// flag of the registered getblock command, so the request is sent raw.
+	hashParam, err := json.Marshal(hash)
+	if err != nil {
+		return FutureGetBlockVerboseTxResult(newFutureError(err))
+	}
+	params := []json.RawMessage{hashParam, json.RawMessage("2")}
+	return FutureGetBlockVerboseTxResult(c.RawRequestAsync("getblock", params))
 }
 
 // GetBlockVerboseTx returns a data structure from the server with information
-// about a block and its transactions given its hash.
+// about a block and its transactions given its hash.  The transactions are
+// decoded by the server with getblock at verbosity 2, which saves requesting
+// each of them with getrawtransaction.
 //
 // See GetBlockVerbose if only transaction hashes are preferred.
 // See GetBlock to retrieve a raw block instead.
-func (c *Client) GetBlockVerboseTx(blockHash *chainhash.Hash) (*sebtcjson.GetBlockVerboseResult, error) {
+func (c *Client) GetBlockVerboseTx(blockHash *chainhash.Hash) (*sebtcjson.GetBlockVerboseTxResult, error) {
 	return c.GetBlockVerboseTxAsync(blockHash).Receive()
 }
 
+// GetBlockVerboseCached returns the same data as GetBlockVerbose, but serves
+// repeated requests for a block from a least recently used cache whose size is
+// set by the BlockCacheSize field of the connection configuration.  Cache hits
+// are served without issuing any RPC.
+//
+// Block contents are immutable, so a ttl of zero keeps entries until they are
+// evicted by size, while a positive ttl forces a refetch once it has elapsed.
+// The confirmations and next block hash fields change as the chain grows and
+// are therefore omitted, so they are always zero.  Use GetBlockCount or
+// GetBlockHash to determine them when needed.
+//
+// The returned result shares its slices with the cached copy and must not be
+// modified.
+func (c *Client) GetBlockVerboseCached(blockHash *chainhash.Hash, ttl time.Duration) (*sebtcjson.GetBlockVerboseResult, error) {
+	if blockHash == nil {
+		return c.GetBlockVerbose(blockHash)
+	}
+
+	if cached, ok := c.blockCache.Get(blockHash, ttl); ok {
+		result := *cached.(*sebtcjson.GetBlockVerboseResult)
+		return &result, nil
+	}
+
+	res, err := c.GetBlockVerboseAsync(blockHash).Receive()
+	if err != nil {
+		return nil, err
+	}
+	res.Confirmations = 0
+	res.NextHash = ""
+	block := *res
+	c.blockCache.Add(blockHash, &block)
+	return res, nil
+}
+
 // FutureGetBlockCountResult is a future promise to deliver the result of a
 // GetBlockCountAsync RPC invocation (or an applicable error).
 type FutureGetBlockCountResult chan *response
@@ -213,6 +344,13 @@ func (c *Client) GetBlockCount() (int64, error) {
 	return c.GetBlockCountAsync().Receive()
 }
 
+// GetBlockCountCtx is like GetBlockCount, but returns the error of the passed
+// context once it is done before the server answers.
+func (c *Client) GetBlockCountCtx(ctx context.Context) (int64, error) {
+	cmd := sebtcjson.NewGetBlockCountCmd()
+	return FutureGetBlockCountResult(c.sendCmdCtx(ctx, cmd)).Receive()
+}
+
 // FutureGetDifficultyResult is a future promise to deliver the result of a
 // GetDifficultyAsync RPC invocation (or an applicable error).
 type FutureGetDifficultyResult chan *response
@@ -334,6 +472,230 @@ func (c *Client) GetBlockChainInfo() (*sebtcjson.GetBlockChainInfoResult, error)
 	return c.GetBlockChainInfoAsync().Receive()
 }
 
+// GetBlockChainInfoCtx is like GetBlockChainInfo, but returns the error of the
+// passed context once it is done before the server answers.
+func (c *Client) GetBlockChainInfoCtx(ctx context.Context) (*sebtcjson.GetBlockChainInfoResult, error) {
+	cmd := sebtcjson.NewGetBlockChainInfoCmd()
+	return FutureGetBlockChainInfoResult(c.sendCmdCtx(ctx, cmd)).Receive()
+}
+
+// FutureGetChainTipsResult is a promise to deliver the result of a
+// GetChainTipsAsync RPC invocation (or an applicable error).
+type FutureGetChainTipsResult chan *response
+
+// Receive waits for the response promised by the future and returns the known
+// tips of the block tree.
+func (r FutureGetChainTipsResult) Receive() ([]sebtcjson.GetChainTipsResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var chainTips []sebtcjson.GetChainTipsResult
+	if err := json.Unmarshal(res, &chainTips); err != nil {
+		return nil, err
+	}
+	return chainTips, nil
+}
+
+// GetChainTipsAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See GetChainTips for the blocking version and more details.
+func (c *Client) GetChainTipsAsync() FutureGetChainTipsResult {
+	cmd := sebtcjson.NewGetChainTipsCmd()
+	return c.sendCmd(cmd)
+}
+
+// GetChainTips returns information about all known tips in the block tree,
+// including the main chain as well as orphaned branches.
+func (c *Client) GetChainTips() ([]sebtcjson.GetChainTipsResult, error) {
+	return c.GetChainTipsAsync().Receive()
+}
+
+// FutureGetChainTxStatsResult is a promise to deliver the result of a
+// GetChainTxStatsAsync RPC invocation (or an applicable error).
+type FutureGetChainTxStatsResult chan *response
+
+// Receive waits for the response promised by the future and returns the
+// transaction statistics of the chain.
+func (r FutureGetChainTxStatsResult) Receive() (*sebtcjson.GetChainTxStatsResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var stats sebtcjson.GetChainTxStatsResult
+	if err := json.Unmarshal(res, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// GetChainTxStatsAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GetChainTxStats for the blocking version and more details.
+func (c *Client) GetChainTxStatsAsync(nBlocks *int32, blockHash *chainhash.Hash) FutureGetChainTxStatsResult {
+	var hash *string
+	if blockHash != nil {
+		hash = sebtcjson.String(blockHash.String())
+	}
+	cmd := sebtcjson.NewGetChainTxStatsCmd(nBlocks, hash)
+	return c.sendCmd(cmd)
+}
+
+// GetChainTxStats returns the total number of transactions in the chain up to
+// the passed block, along with the number and rate of the transactions within
+// the window of nBlocks blocks ending at it.  The block defaults to the best
+// block and the window to about one month of blocks when they are nil.
+func (c *Client) GetChainTxStats(nBlocks *int32, blockHash *chainhash.Hash) (*sebtcjson.GetChainTxStatsResult, error) {
+	return c.GetChainTxStatsAsync(nBlocks, blockHash).Receive()
+}
+
+// DeepestForkDepth returns the largest branch length among the tips which are
+// not part of the active chain, which serves as a measure of reorg risk.  Zero
+// is returned when there are no forks.
+func (c *Client) DeepestForkDepth() (int32, error) {
+	chainTips, err := c.GetChainTips()
+	if err != nil {
+		return 0, err
+	}
+
+	var depth int32
+	for _, tip := range chainTips {
+		if tip.Status != "active" && tip.BranchLen > depth {
+			depth = tip.BranchLen
+		}
+	}
+	return depth, nil
+}
+
+// ErrBlockFiltersUnsupported is returned by BlockMatchesScripts when the server
+// does not serve basic block filters, either because it does not support the
+// getblockfilter RPC or because it was started without -blockfilterindex.
+var ErrBlockFiltersUnsupported = errors.New("server does not serve basic " +
+	"block filters")
+
+// FutureGetBlockFilterResult is a future promise to deliver the result of a
+// GetBlockFilterAsync RPC invocation (or an applicable error).
+type FutureGetBlockFilterResult chan *response
+
+// Receive waits for the response promised by the future and returns the
+// serialized filter and filter header of the block.
+func (r FutureGetBlockFilterResult) Receive() (*sebtcjson.GetBlockFilterResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var filter sebtcjson.GetBlockFilterResult
+	err = json.Unmarshal(res, &filter)
+	if err != nil {
+		return nil, err
+	}
+	return &filter, nil
+}
+
+// GetBlockFilterAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See GetBlockFilter for the blocking version and more details.
+func (c *Client) GetBlockFilterAsync(blockHash *chainhash.Hash, filterType *string) FutureGetBlockFilterResult {
+	hash := ""
+	if blockHash != nil {
+		hash = blockHash.String()
+	}
+
+	cmd := sebtcjson.NewGetBlockFilterCmd(hash, filterType)
+	return c.sendCmd(cmd)
+}
+
+// GetBlockFilter returns the compact block filter of the given type for the
+// block with the given hash.  A nil filter type selects the basic filter.
+//
+// NOTE: This requires Bitcoin Core 0.19 or later started with
+// -blockfilterindex.
+func (c *Client) GetBlockFilter(blockHash *chainhash.Hash, filterType *string) (*sebtcjson.GetBlockFilterResult, error) {
+	return c.GetBlockFilterAsync(blockHash, filterType).Receive()
+}
+
+// BlockMatchesScripts fetches the basic filter of the block with the given hash
+// and returns whether any of the passed output scripts match it.  Matches are
+// probabilistic, so a true result may be a false positive, which happens about
+// once per 784931 scripts, while a false result is always correct.
+//
+// ErrBlockFiltersUnsupported is returned when the server does not serve basic
+// block filters.
+func (c *Client) BlockMatchesScripts(blockHash *chainhash.Hash, scripts [][]byte) (bool, error) {
+	result, err := c.GetBlockFilter(blockHash, nil)
+	if err != nil {
+		// Servers without the filter index reply with a generic
+		// error, so it can only be told apart by its message.
+		rpcErr, ok := err.(*sebtcjson.RPCError)
+		if ok && (rpcErr.Code == sebtcjson.ErrRPCMethodNotFound.Code ||
+			strings.HasPrefix(rpcErr.Message, "Index is not enabled")) {
+			return false, ErrBlockFiltersUnsupported
+		}
+		return false, err
+	}
+
+	filterBytes, err := hex.DecodeString(result.Filter)
+	if err != nil {
+		return false, err
+	}
+	filter, err := gcs.FromNBytes(builder.DefaultP, builder.DefaultM,
+		filterBytes)
+	if err != nil {
+		return false, err
+	}
+	if filter.N() == 0 || len(scripts) == 0 {
+		return false, nil
+	}
+
+	return filter.MatchAny(builder.DeriveKey(blockHash), scripts)
+}
+
+// FutureGetBlockFromPeerResult is a future promise to deliver the result of a
+// GetBlockFromPeerAsync RPC invocation (or an applicable error).
+type FutureGetBlockFromPeerResult chan *response
+
+// Receive waits for the response promised by the future and returns an error
+// if the block could not be requested from the peer.
+func (r FutureGetBlockFromPeerResult) Receive() error {
+	_, err := receiveFuture(r)
+	return err
+}
+
+// GetBlockFromPeerAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GetBlockFromPeer for the blocking version and more details.
+func (c *Client) GetBlockFromPeerAsync(blockHash *chainhash.Hash, peerID int64) FutureGetBlockFromPeerResult {
+	hash := ""
+	if blockHash != nil {
+		hash = blockHash.String()
+	}
+
+	cmd := sebtcjson.NewGetBlockFromPeerCmd(hash, peerID)
+	return c.sendCmd(cmd)
+}
+
+// GetBlockFromPeer requests the block with the given hash from the peer with
+// the given id, as reported by GetPeerInfo.  The header of the block must
+// already be known.  The request is only queued, so a nil error does not mean
+// the block has been received.  This is mainly useful to fetch blocks which
+// were pruned.
+//
+// NOTE: This requires Bitcoin Core 23.0 or later.
+func (c *Client) GetBlockFromPeer(blockHash *chainhash.Hash, peerID int64) error {
+	return c.GetBlockFromPeerAsync(blockHash, peerID).Receive()
+}
+
 // FutureGetBlockHashResult is a future promise to deliver the result of a
 // GetBlockHashAsync RPC invocation (or an applicable error).
 type FutureGetBlockHashResult chan *response
@@ -371,6 +733,36 @@ func (c *Client) GetBlockHash(blockHeight int64) (*chainhash.Hash, error) {
 	return c.GetBlockHashAsync(blockHeight).Receive()
 }
 
+// GetBlockHashCtx is like GetBlockHash, but returns the error of the passed
+// context once it is done before the server answers.
+func (c *Client) GetBlockHashCtx(ctx context.Context, blockHeight int64) (*chainhash.Hash, error) {
+	cmd := sebtcjson.NewGetBlockHashCmd(blockHeight)
+	return FutureGetBlockHashResult(c.sendCmdCtx(ctx, cmd)).Receive()
+}
+
+// GetBlockHashRelative returns the hash of the block in the best block chain at
+// the given height.  Negative heights are counted back from the tip of the
+// chain, so -1 refers to the tip itself and -6 to the block five below it.
+//
+// Negative heights are resolved against the current block count and an error
+// is returned when the resolved height would be below the genesis block.
+func (c *Client) GetBlockHashRelative(blockHeight int64) (*chainhash.Hash, error) {
+	if blockHeight >= 0 {
+		return c.GetBlockHash(blockHeight)
+	}
+
+	count, err := c.GetBlockCount()
+	if err != nil {
+		return nil, err
+	}
+	height := count + 1 + blockHeight
+	if height < 0 {
+		return nil, fmt.Errorf("relative height %d is beyond the genesis "+
+			"block (chain height %d)", blockHeight, count)
+	}
+	return c.GetBlockHash(height)
+}
+
 // FutureGetBlockHeaderResult is a future promise to deliver the result of a
 // GetBlockHeaderAsync RPC invocation (or an applicable error).
 type FutureGetBlockHeaderResult chan *response
@@ -473,6 +865,152 @@ func (c *Client) GetBlockHeaderVerbose(blockHash *chainhash.Hash) (*sebtcjson.Ge
 	return c.GetBlockHeaderVerboseAsync(blockHash).Receive()
 }
 
+// medianTimeBlocks is the number of blocks whose timestamps the median time
+// past of a block is computed from.
+const medianTimeBlocks = 11
+
+// GetMedianTimePast returns the median time past of the block with the passed
+// hash, which is the median of the timestamps of the block and its ten
+// predecessors, as used by the consensus rules to evaluate the time locks of
+// BIP 65 and BIP 112.  The headers are fetched by following the previous block
+// hash of each header, and blocks with fewer than ten predecessors use the
+// timestamps of however many blocks exist.
+func (c *Client) GetMedianTimePast(blockHash *chainhash.Hash) (time.Time, error) {
+	if blockHash == nil {
+		return time.Time{}, errors.New("block hash must not be nil")
+	}
+
+	timestamps := make([]int64, 0, medianTimeBlocks)
+	hash := blockHash
+	for len(timestamps) < medianTimeBlocks {
+		header, err := c.GetBlockHeader(hash)
+		if err != nil {
+			return time.Time{}, err
+		}
+		timestamps = append(timestamps, header.Timestamp.Unix())
+
+		// The genesis block has no predecessor.
+		if header.PrevBlock == (chainhash.Hash{}) {
+			break
+		}
+		hash = &header.PrevBlock
+	}
+
+	sort.Slice(timestamps, func(i, j int) bool {
+		return timestamps[i] < timestamps[j]
+	})
+	return time.Unix(timestamps[len(timestamps)/2], 0), nil
+}
+
+// FutureGetBlockStatsResult is a future promise to deliver the result of a
+// GetBlockStatsAsync RPC invocation (or an applicable error).
+type FutureGetBlockStatsResult chan *response
+
+// Receive waits for the response promised by the future and returns the
+// statistics of the block requested from the server.
+func (r FutureGetBlockStatsResult) Receive() (*sebtcjson.GetBlockStatsResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var stats sebtcjson.GetBlockStatsResult
+	err = json.Unmarshal(res, &stats)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+// GetBlockStatsAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See GetBlockStats for the blocking version and more details.
+func (c *Client) GetBlockStatsAsync(hashOrHeight interface{}, stats *[]string) FutureGetBlockStatsResult {
+	var value interface{}
+	switch v := hashOrHeight.(type) {
+	case int:
+		value = v
+	case int32:
+		value = int(v)
+	case int64:
+		value = int(v)
+	case string:
+		value = v
+	case *chainhash.Hash:
+		if v == nil {
+			return newFutureError(errors.New("block hash must not be nil"))
+		}
+		value = v.String()
+	default:
+		return newFutureError(fmt.Errorf("unsupported block hash or "+
+			"height type %T", hashOrHeight))
+	}
+
+	cmd := sebtcjson.NewGetBlockStatsCmd(sebtcjson.HashOrHeight{Value: value}, stats)
+	return c.sendCmd(cmd)
+}
+
+// GetBlockStats returns statistics about the block identified by hashOrHeight,
+// which may be a block height (int, int32 or int64) or a block hash (string
+// or *chainhash.Hash).  Passing a non-nil stats limits the result to the named
+// statistics.
+//
+// When ConnConfig.BlockStatsCacheSize is set, complete results are cached by
+// block hash and subsequent requests by hash are served from the cache.  Use
+// PurgeBlockStats to drop the entry of a block that has been reorganized out
+// of the main chain.
+func (c *Client) GetBlockStats(hashOrHeight interface{}, stats *[]string) (*sebtcjson.GetBlockStatsResult, error) {
+	cache := c.blockStatsCache
+	if cache == nil || stats != nil {
+		return c.GetBlockStatsAsync(hashOrHeight, stats).Receive()
+	}
+
+	var hash *chainhash.Hash
+	switch v := hashOrHeight.(type) {
+	case *chainhash.Hash:
+		hash = v
+	case string:
+		hash, _ = chainhash.NewHashFromStr(v)
+	}
+	if hash != nil {
+		if cached, ok := cache.Get(hash, 0); ok {
+			return copyBlockStats(cached.(*sebtcjson.GetBlockStatsResult)), nil
+		}
+	}
+
+	result, err := c.GetBlockStatsAsync(hashOrHeight, nil).Receive()
+	if err != nil {
+		return nil, err
+	}
+	if hash, err := chainhash.NewHashFromStr(result.Hash); err == nil {
+		cache.Add(hash, copyBlockStats(result))
+	}
+	return result, nil
+}
+
+// copyBlockStats returns a deep copy of the passed block stats so callers can
+// not modify the cached entries.
+func copyBlockStats(stats *sebtcjson.GetBlockStatsResult) *sebtcjson.GetBlockStatsResult {
+	result := *stats
+	if stats.FeeRatePercentiles != nil {
+		result.FeeRatePercentiles = make([]int64, len(stats.FeeRatePercentiles))
+		copy(result.FeeRatePercentiles, stats.FeeRatePercentiles)
+	}
+	return &result
+}
+
+// PurgeBlockStats removes the cached getblockstats result for the passed block
+// hash.  It should be called for blocks which are disconnected from the main
+// chain by a reorganization.  It is a no-op when the cache is disabled.
+func (c *Client) PurgeBlockStats(blockHash *chainhash.Hash) {
+	if c.blockStatsCache != nil && blockHash != nil {
+		c.blockStatsCache.Remove(blockHash)
+	}
+}
+
 // FutureGetMempoolEntryResult is a future promise to deliver the result of a
 // GetMempoolEntryAsync RPC invocation (or an applicable error).
 type FutureGetMempoolEntryResult chan *response
@@ -486,7 +1024,7 @@ func (r FutureGetMempoolEntryResult) Receive() (*sebtcjson.GetMempoolEntryResult
 		return nil, err
 	}
 
-	// Unmarshal the result as an array of strings.
+	// Unmarshal result as a getmempoolentry result object.
 	var mempoolEntryResult sebtcjson.GetMempoolEntryResult
 	err = json.Unmarshal(res, &mempoolEntryResult)
 	if err != nil {
@@ -512,6 +1050,240 @@ func (c *Client) GetMempoolEntry(txHash string) (*sebtcjson.GetMempoolEntryResul
 	return c.GetMempoolEntryAsync(txHash).Receive()
 }
 
+var (
+	// ErrTxNotFound is returned by IsReplaceable when the server knows of
+	// no transaction with the passed hash.
+	ErrTxNotFound = errors.New("transaction not found")
+
+	// ErrTxConfirmed is returned by IsReplaceable when the transaction is
+	// already included in a block and so can no longer be replaced.
+	ErrTxConfirmed = errors.New("transaction is already confirmed")
+)
+
+// bip125MaxSequence is the largest input sequence number which signals that a
+// transaction opts in to replacement as specified by BIP 125.
+const bip125MaxSequence = 0xfffffffd
+
+// IsReplaceable returns whether the unconfirmed transaction with the passed
+// hash may be replaced as specified by BIP 125.  A transaction is replaceable
+// when any of its inputs signals so, or, as reported by getmempoolentry on
+// servers which support it, when it inherits the signal from an unconfirmed
+// ancestor.
+//
+// ErrTxConfirmed is returned when the transaction is already confirmed, also
+// when it is mined while it is being checked, and ErrTxNotFound when the
+// server does not know of it.  Transactions which are not in the mempool can
+// only be found when the server maintains a transaction index.
+func (c *Client) IsReplaceable(txHash *chainhash.Hash) (bool, error) {
+	tx, err := c.GetRawTransactionVerbose(txHash)
+	if err != nil {
+		if isNoTxInfo(err) {
+			return false, ErrTxNotFound
+		}
+		return false, err
+	}
+	if tx.Confirmations > 0 || tx.BlockHash != "" {
+		return false, ErrTxConfirmed
+	}
+
+	for _, txIn := range tx.Vin {
+		if txIn.Sequence <= bip125MaxSequence {
+			return true, nil
+		}
+	}
+
+	entry, err := c.GetMempoolEntry(txHash.String())
+	if err != nil {
+		// The transaction was mined or evicted after it was fetched.
+		if isNoTxInfo(err) {
+			return false, c.leftMempoolError(txHash, tx)
+		}
+		return false, err
+	}
+	if entry.BIP125Replaceable != nil {
+		return *entry.BIP125Replaceable, nil
+	}
+	return false, nil
+}
+
+// leftMempoolError returns ErrTxConfirmed when the passed transaction, which
+// left the mempool after it was fetched, was mined, and ErrTxNotFound when it
+// was evicted.  The transaction is fetched again, which finds it once mined on
+// servers maintaining a transaction index, and its outputs are otherwise looked
+// up in the UTXO set, which finds it as long as any of them is unspent.
+func (c *Client) leftMempoolError(txHash *chainhash.Hash, tx *sebtcjson.TxRawResult) error {
+	mined, err := c.GetRawTransactionVerbose(txHash)
+	switch {
+	case err == nil && (mined.Confirmations > 0 || mined.BlockHash != ""):
+		return ErrTxConfirmed
+	case err != nil && !isNoTxInfo(err):
+		return err
+	}
+
+	for _, txOut := range tx.Vout {
+		out, err := c.GetTxOut(txHash, txOut.N, false)
+		if err != nil {
+			return err
+		}
+		if out != nil {
+			return ErrTxConfirmed
+		}
+	}
+	return ErrTxNotFound
+}
+
+// isNoTxInfo returns whether the passed error is the error returned by the
+// server for an unknown transaction.
+func isNoTxInfo(err error) bool {
+	rpcErr, ok := err.(*sebtcjson.RPCError)
+	return ok && rpcErr.Code == sebtcjson.ErrRPCNoTxInfo
+}
+
+// FutureGetMempoolInfoResult is a future promise to deliver the result of a
+// GetMempoolInfoAsync RPC invocation (or an applicable error).
+type FutureGetMempoolInfoResult chan *response
+
+// Receive waits for the response promised by the future and returns a data
+// structure with information about the current state of the memory pool.
+func (r FutureGetMempoolInfoResult) Receive() (*sebtcjson.GetMempoolInfoResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var mempoolInfoResult sebtcjson.GetMempoolInfoResult
+	err = json.Unmarshal(res, &mempoolInfoResult)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mempoolInfoResult, nil
+}
+
+// GetMempoolInfoAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See GetMempoolInfo for the blocking version and more details.
+func (c *Client) GetMempoolInfoAsync() FutureGetMempoolInfoResult {
+	cmd := sebtcjson.NewGetMempoolInfoCmd()
+	return c.sendCmd(cmd)
+}
+
+// GetMempoolInfo returns a data structure with information about the current
+// state of the memory pool.
+func (c *Client) GetMempoolInfo() (*sebtcjson.GetMempoolInfoResult, error) {
+	return c.GetMempoolInfoAsync().Receive()
+}
+
+// MinimumRelayFeeRate returns the lowest fee rate, in satoshi per virtual byte,
+// a transaction needs to be accepted into the memory pool of the server.  It is
+// the higher of the mempoolminfee and minrelaytxfee fields of getmempoolinfo,
+// rounded up to the next whole satoshi per virtual byte.
+func (c *Client) MinimumRelayFeeRate() (int64, error) {
+	perKvB, err := c.minimumRelayFeePerKvB()
+	if err != nil {
+		return 0, err
+	}
+	return (perKvB + 999) / 1000, nil
+}
+
+// minimumRelayFeePerKvB returns the minimum relay fee rate of the server like
+// MinimumRelayFeeRate, but in satoshi per kilo virtual byte without rounding.
+func (c *Client) minimumRelayFeePerKvB() (int64, error) {
+	info, err := c.GetMempoolInfo()
+	if err != nil {
+		return 0, err
+	}
+
+	floor := info.MempoolMinFee
+	if info.MinRelayTxFee > floor {
+		floor = info.MinRelayTxFee
+	}
+	perKvB, err := btcutil.NewAmount(floor)
+	if err != nil {
+		return 0, err
+	}
+	return int64(perKvB), nil
+}
+
+// CheckFeeRate returns whether the passed fee rate, in satoshi per virtual
+// byte, clears the current minimum relay fee rate of the server along with
+// that minimum.
+//
+// See MinimumRelayFeeRate for how the minimum is determined.
+func (c *Client) CheckFeeRate(feeRate int64) (bool, int64, error) {
+	floor, err := c.MinimumRelayFeeRate()
+	if err != nil {
+		return false, 0, err
+	}
+	return feeRate >= floor, floor, nil
+}
+
+// AtomicFeeFloor holds the minimum relay fee rate of a server, as kept up to
+// date by StartMinFeeRefresher.  It is safe for concurrent access.
+type AtomicFeeFloor struct {
+	// perKvB is the rate in satoshi per kilo virtual byte, which holds
+	// rates below one satoshi per virtual byte exactly.
+	perKvB int64 // atomic
+}
+
+// Rate returns the most recently polled minimum relay fee rate, in satoshi per
+// virtual byte, rounded up to the next whole satoshi per virtual byte.
+func (f *AtomicFeeFloor) Rate() int64 {
+	return (f.RatePerKvB() + 999) / 1000
+}
+
+// RatePerKvB returns the most recently polled minimum relay fee rate, in
+// satoshi per kilo virtual byte.  Unlike Rate, it is not rounded.
+func (f *AtomicFeeFloor) RatePerKvB() int64 {
+	return atomic.LoadInt64(&f.perKvB)
+}
+
+// StartMinFeeRefresher polls the minimum relay fee rate of the server, as
+// determined by MinimumRelayFeeRate, every poll interval and keeps the returned
+// floor up to date with it, so transactions can be checked against the floor
+// without a round trip to the server.
+//
+// Polls which fail are logged and retried at the next interval, leaving the
+// previous rate in place.  Polling stops once the passed context is done or
+// the client is shut down.  An error is returned when the first poll fails.
+func (c *Client) StartMinFeeRefresher(ctx context.Context, poll time.Duration) (*AtomicFeeFloor, error) {
+	if poll <= 0 {
+		return nil, errors.New("poll interval must be positive")
+	}
+
+	perKvB, err := c.minimumRelayFeePerKvB()
+	if err != nil {
+		return nil, err
+	}
+	floor := &AtomicFeeFloor{perKvB: perKvB}
+
+	go func() {
+		ticker := time.NewTicker(poll)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			case <-c.shutdown:
+				return
+			}
+
+			perKvB, err := c.minimumRelayFeePerKvB()
+			if err != nil {
+				c.logger().Warnf("Unable to poll minimum relay fee "+
+					"rate of %s: %v", c.config.Host, err)
+				continue
+			}
+			atomic.StoreInt64(&floor.perKvB, perKvB)
+		}
+	}()
+	return floor, nil
+}
+
 // FutureGetRawMempoolResult is a future promise to deliver the result of a
 // GetRawMempoolAsync RPC invocation (or an applicable error).
 type FutureGetRawMempoolResult chan *response
@@ -562,6 +1334,114 @@ func (c *Client) GetRawMempool() ([]*chainhash.Hash, error) {
 	return c.GetRawMempoolAsync().Receive()
 }
 
+// MempoolEventType identifies the kind of change reported by a MempoolEvent.
+type MempoolEventType int
+
+const (
+	// MempoolTxAdded indicates a transaction entered the memory pool.
+	MempoolTxAdded MempoolEventType = iota
+
+	// MempoolTxRemoved indicates a transaction left the memory pool, either
+	// because it was mined, replaced, or evicted.
+	MempoolTxRemoved
+)
+
+// String returns the MempoolEventType in human-readable form.
+func (t MempoolEventType) String() string {
+	switch t {
+	case MempoolTxAdded:
+		return "added"
+	case MempoolTxRemoved:
+		return "removed"
+	}
+	return "unknown"
+}
+
+// MempoolEvent describes a transaction which was added to or removed from the
+// memory pool between two polls of WatchMempool.
+type MempoolEvent struct {
+	Type MempoolEventType
+	TxID chainhash.Hash
+}
+
+// WatchMempool polls the memory pool of the server every poll interval and
+// delivers an event over the returned channel for every transaction which was
+// added or removed since the previous poll.  The first poll reports every
+// transaction in the memory pool as added.  Only the set of transaction hashes
+// is retained between polls.
+//
+// Polls which fail are logged and retried at the next interval.  The channel
+// is closed once the passed context is done or the client is shut down.  An
+// error is returned when the first poll fails.
+func (c *Client) WatchMempool(ctx context.Context, poll time.Duration) (<-chan MempoolEvent, error) {
+	if poll <= 0 {
+		return nil, errors.New("poll interval must be positive")
+	}
+
+	txids, err := c.GetRawMempool()
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan MempoolEvent)
+	go func() {
+		defer close(events)
+
+		ticker := time.NewTicker(poll)
+		defer ticker.Stop()
+
+		var known []*chainhash.Hash
+		for {
+			// Diff the current snapshot against the previous one
+			// and replace it.
+			added, removed := MempoolDiff(known, txids)
+			changes := make([]MempoolEvent, 0, len(added)+len(removed))
+			for _, txid := range added {
+				changes = append(changes, MempoolEvent{
+					Type: MempoolTxAdded,
+					TxID: *txid,
+				})
+			}
+			for _, txid := range removed {
+				changes = append(changes, MempoolEvent{
+					Type: MempoolTxRemoved,
+					TxID: *txid,
+				})
+			}
+			known = txids
+
+			for _, event := range changes {
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				case <-c.shutdown:
+					return
+				}
+			}
+
+			// Wait for the next poll, skipping over failures.
+			for {
+				select {
+				case <-ticker.C:
+				case <-ctx.Done():
+					return
+				case <-c.shutdown:
+					return
+				}
+
+				txids, err = c.GetRawMempool()
+				if err == nil {
+					break
+				}
+				c.logger().Warnf("Unable to poll memory pool of %s: %v",
+					c.config.Host, err)
+			}
+		}
+	}()
+	return events, nil
+}
+
 // FutureGetRawMempoolVerboseResult is a future promise to deliver the result of
 // a GetRawMempoolVerboseAsync RPC invocation (or an applicable error).
 type FutureGetRawMempoolVerboseResult chan *response
@@ -742,6 +1622,362 @@ func (c *Client) GetTxOut(txHash *chainhash.Hash, index uint32, mempool bool) (*
 	return c.GetTxOutAsync(txHash, index, mempool).Receive()
 }
 
+// FutureGetTxOutSetInfoResult is a future promise to deliver the result of a
+// GetTxOutSetInfoAsync RPC invocation (or an applicable error).
+type FutureGetTxOutSetInfoResult chan *response
+
+// Receive waits for the response promised by the future and returns the
+// statistics about the unspent transaction output set.  An error matching
+// ErrCoinStatsIndexUnavailable with errors.Is is returned when the
+// coinstatsindex was required but can not be used.
+func (r FutureGetTxOutSetInfoResult) Receive() (*sebtcjson.GetTxOutSetInfoResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, newCoinStatsIndexError(err)
+	}
+
+	var info sebtcjson.GetTxOutSetInfoResult
+	err = json.Unmarshal(res, &info)
+	if err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// GetTxOutSetInfoAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GetTxOutSetInfo for the blocking version and more details.
+func (c *Client) GetTxOutSetInfoAsync(useIndex *bool) FutureGetTxOutSetInfoResult {
+	cmd := sebtcjson.NewGetTxOutSetInfoCmd()
+	cmd.UseIndex = useIndex
+	return c.sendCmd(cmd)
+}
+
+// GetTxOutSetInfo returns statistics about the unspent transaction output set
+// at the best block.  The server computes them with its coinstatsindex, which
+// is instant, when the index is enabled and useIndex is nil or true, and by
+// scanning the whole set, which may take minutes, otherwise.
+func (c *Client) GetTxOutSetInfo(useIndex *bool) (*sebtcjson.GetTxOutSetInfoResult, error) {
+	return c.GetTxOutSetInfoAsync(useIndex).Receive()
+}
+
+// ErrCoinStatsIndexUnavailable is returned when the coinstatsindex of the
+// server was required but can not be used, such as while it is still syncing.
+// Callers may retry later or fall back to scanning the unspent transaction
+// output set.  The errors returned for this condition match it with errors.Is
+// and still unwrap to the *sebtcjson.RPCError of the server.
+var ErrCoinStatsIndexUnavailable = errors.New("coinstatsindex is not " +
+	"available")
+
+// coinStatsIndexError wraps the error returned by the server when its
+// coinstatsindex can not be used.
+type coinStatsIndexError struct {
+	*sebtcjson.RPCError
+}
+
+// Is returns whether the target is ErrCoinStatsIndexUnavailable.
+func (e *coinStatsIndexError) Is(target error) bool {
+	return target == ErrCoinStatsIndexUnavailable
+}
+
+// Unwrap returns the error returned by the server.
+func (e *coinStatsIndexError) Unwrap() error {
+	return e.RPCError
+}
+
+// newCoinStatsIndexError returns a coinStatsIndexError for the passed error
+// when the server reports that its coinstatsindex can not be used, and the
+// error itself otherwise.  Bitcoin Core reports this with a generic error
+// code, so the message is matched.
+func newCoinStatsIndexError(err error) error {
+	rpcErr, ok := err.(*sebtcjson.RPCError)
+	if !ok || !strings.Contains(rpcErr.Message, "coinstatsindex") {
+		return err
+	}
+	return &coinStatsIndexError{rpcErr}
+}
+
+// CoinSupply houses the total amount of coins in the unspent transaction output
+// set as of a block.
+type CoinSupply struct {
+	// Height is the height of the block the supply was computed at.
+	Height int64
+
+	// BestBlock is the hash of the block the supply was computed at.
+	BestBlock string
+
+	// TotalAmount is the sum of all unspent transaction outputs.
+	TotalAmount btcutil.Amount
+}
+
+// SupplyInfo returns the total amount of coins in existence as of the best
+// block, as reported by gettxoutsetinfo.  When useIndex is true the
+// coinstatsindex of the server is used, which makes the request instant when
+// the index is enabled, and an error matching ErrCoinStatsIndexUnavailable is
+// returned when the index can not be used yet.  Otherwise the whole unspent
+// transaction output set is scanned.
+func (c *Client) SupplyInfo(useIndex bool) (*CoinSupply, error) {
+	info, err := c.GetTxOutSetInfo(&useIndex)
+	if err != nil {
+		return nil, err
+	}
+	total, err := btcutil.NewAmount(info.TotalAmount)
+	if err != nil {
+		return nil, err
+	}
+	return &CoinSupply{
+		Height:      info.Height,
+		BestBlock:   info.BestBlock,
+		TotalAmount: total,
+	}, nil
+}
+
+// FutureGetAddressUTXOsResult is a future promise to deliver the result of a
+// GetAddressUTXOsAsync RPC invocation (or an applicable error).
+type FutureGetAddressUTXOsResult chan *response
+
+// Receive waits for the response promised by the future and returns the
+// unspent outputs of the requested addresses.
+func (r FutureGetAddressUTXOsResult) Receive() ([]sebtcjson.AddressUtxo, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var utxos []sebtcjson.AddressUtxo
+	err = json.Unmarshal(res, &utxos)
+	if err != nil {
+		return nil, err
+	}
+	return utxos, nil
+}
+
+// GetAddressUTXOsAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GetAddressUTXOs for the blocking version and more details.
+func (c *Client) GetAddressUTXOsAsync(addresses []btcutil.Address) FutureGetAddressUTXOsResult {
+	cmd := sebtcjson.NewGetAddressUtxosCmd(encodeAddresses(addresses), false)
+	return c.sendCmd(cmd)
+}
+
+// FutureGetAddressUTXOsChainInfoResult is a future promise to deliver the
+// result of a GetAddressUTXOsChainInfoAsync RPC invocation (or an applicable
+// error).
+type FutureGetAddressUTXOsChainInfoResult chan *response
+
+// Receive waits for the response promised by the future and returns the
+// unspent outputs of the requested addresses along with the chain tip they were
+// read at.
+func (r FutureGetAddressUTXOsChainInfoResult) Receive() (*sebtcjson.GetAddressUtxosChainInfoResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var result sebtcjson.GetAddressUtxosChainInfoResult
+	err = json.Unmarshal(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetAddressUTXOsChainInfoAsync returns an instance of a type that can be used
+// to get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See GetAddressUTXOs for the blocking version and more details.
+func (c *Client) GetAddressUTXOsChainInfoAsync(addresses []btcutil.Address) FutureGetAddressUTXOsChainInfoResult {
+	cmd := sebtcjson.NewGetAddressUtxosCmd(encodeAddresses(addresses), true)
+	return c.sendCmd(cmd)
+}
+
+// GetAddressUTXOs returns the unspent outputs paying to the passed addresses
+// from the address index of the server.  When chainInfo is true, the hash and
+// height of the chain tip the outputs were read at are returned along with
+// them, so they can be reconciled with later blocks.  Otherwise the hash and
+// height of the result are left empty.
+//
+// NOTE: This is an extension of the bitcore fork of Bitcoin Core which requires
+// the address index to be enabled with -addressindex.
+func (c *Client) GetAddressUTXOs(addresses []btcutil.Address, chainInfo bool) (*sebtcjson.GetAddressUtxosChainInfoResult, error) {
+	if chainInfo {
+		return c.GetAddressUTXOsChainInfoAsync(addresses).Receive()
+	}
+
+	utxos, err := c.GetAddressUTXOsAsync(addresses).Receive()
+	if err != nil {
+		return nil, err
+	}
+	return &sebtcjson.GetAddressUtxosChainInfoResult{Utxos: utxos}, nil
+}
+
+// AddressBalance houses the balance of a set of addresses as reported by the
+// address index of the server.
+type AddressBalance struct {
+	// Balance is the confirmed balance of the addresses.
+	Balance btcutil.Amount
+
+	// Received is the total amount ever received by the addresses in
+	// confirmed transactions.
+	Received btcutil.Amount
+
+	// MempoolBalance is the change to the balance by unconfirmed
+	// transactions in the memory pool, which may be negative.  It is only
+	// set when the unconfirmed balance was requested.
+	MempoolBalance btcutil.Amount
+}
+
+// FutureGetAddressBalanceResult is a future promise to deliver the result of a
+// GetAddressBalanceAsync RPC invocation (or an applicable error).
+type FutureGetAddressBalanceResult chan *response
+
+// Receive waits for the response promised by the future and returns the
+// balance of the requested addresses.
+func (r FutureGetAddressBalanceResult) Receive() (*AddressBalance, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var result sebtcjson.GetAddressBalanceResult
+	err = json.Unmarshal(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &AddressBalance{
+		Balance:        btcutil.Amount(result.Balance),
+		Received:       btcutil.Amount(result.Received),
+		MempoolBalance: btcutil.Amount(result.MempoolBalance),
+	}, nil
+}
+
+// GetAddressBalanceAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GetAddressBalance for the blocking version and more details.
+func (c *Client) GetAddressBalanceAsync(addresses []btcutil.Address, includeMempool bool) FutureGetAddressBalanceResult {
+	cmd := sebtcjson.NewGetAddressBalanceCmd(encodeAddresses(addresses),
+		includeMempool)
+	return c.sendCmd(cmd)
+}
+
+// GetAddressBalance returns the combined balance of the passed addresses and
+// the total amount they received from the address index of the server.  When
+// includeMempool is true, the change to the balance by unconfirmed
+// transactions is returned as well.  Otherwise the mempool balance of the
+// result is left zero.
+//
+// NOTE: This is an extension of the bitcore fork of Bitcoin Core which requires
+// the address index to be enabled with -addressindex.
+func (c *Client) GetAddressBalance(addresses []btcutil.Address, includeMempool bool) (*AddressBalance, error) {
+	return c.GetAddressBalanceAsync(addresses, includeMempool).Receive()
+}
+
+// encodeAddresses returns the string encoding of each of the passed addresses.
+func encodeAddresses(addresses []btcutil.Address) []string {
+	addrs := make([]string, 0, len(addresses))
+	for _, addr := range addresses {
+		addrs = append(addrs, addr.EncodeAddress())
+	}
+	return addrs
+}
+
+// maxUnspentOutputsAttempts is the number of times GetUnspentOutputs queries
+// the outputs before giving up on getting a snapshot within a single chain tip.
+const maxUnspentOutputsAttempts = 3
+
+// ErrUnspentOutputsTipChanged is returned by GetUnspentOutputs when the chain
+// tip kept changing while the outputs were being queried.
+var ErrUnspentOutputsTipChanged = errors.New("chain tip changed while " +
+	"querying unspent outputs")
+
+// UnspentOutput houses the gettxout result for an outpoint along with whether
+// it was found in the mempool rather than the chain.
+type UnspentOutput struct {
+	sebtcjson.GetTxOutResult
+
+	// OutPoint is the outpoint the result is for.
+	OutPoint wire.OutPoint
+
+	// InMempool is true when the output was created by a mempool
+	// transaction and has no confirmations yet.
+	InMempool bool
+}
+
+// GetUnspentOutputs returns the unspent output info for each of the passed
+// outpoints.  The returned slice has one entry per outpoint, in the same order,
+// and the entry is nil when the output is spent or does not exist.  When
+// includeMempool is true, outputs created by mempool transactions are included
+// and outputs spent by mempool transactions are reported as spent.
+//
+// All of the gettxout requests are sent before any of the replies are waited
+// on.  The best block hash is fetched before and after them, and the requests
+// are repeated when it changed, so every result is relative to the same chain
+// tip.  ErrUnspentOutputsTipChanged is returned when no such snapshot could be
+// taken.  The mempool can not be held still, so transactions entering or
+// leaving it while the requests are served may still be seen by only some of
+// the results.
+func (c *Client) GetUnspentOutputs(outpoints []wire.OutPoint, includeMempool bool) ([]*UnspentOutput, error) {
+	for attempt := 0; attempt < maxUnspentOutputsAttempts; attempt++ {
+		bestFuture := c.GetBestBlockHashAsync()
+		futures := make([]FutureGetTxOutResult, len(outpoints))
+		for i := range outpoints {
+			futures[i] = c.GetTxOutAsync(&outpoints[i].Hash,
+				outpoints[i].Index, includeMempool)
+		}
+		afterFuture := c.GetBestBlockHashAsync()
+
+		// Every future is received, even after an error, so none of
+		// the replies are left behind.
+		var firstErr error
+		before, err := bestFuture.Receive()
+		if err != nil {
+			firstErr = err
+		}
+		results := make([]*UnspentOutput, len(outpoints))
+		consistent := true
+		for i, future := range futures {
+			txOut, err := future.Receive()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				continue
+			}
+			if txOut == nil {
+				continue
+			}
+			if before != nil && txOut.BestBlock != before.String() {
+				consistent = false
+			}
+			results[i] = &UnspentOutput{
+				GetTxOutResult: *txOut,
+				OutPoint:       outpoints[i],
+				InMempool:      txOut.Confirmations == 0,
+			}
+		}
+		after, err := afterFuture.Receive()
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		if firstErr != nil {
+			return nil, firstErr
+		}
+
+		if consistent && before.IsEqual(after) {
+			return results, nil
+		}
+	}
+
+	return nil, ErrUnspentOutputsTipChanged
+}
+
 // FutureRescanBlocksResult is a future promise to deliver the result of a
 // RescanBlocksAsync RPC invocation (or an applicable error).
 //
@@ -828,3 +2064,58 @@ func (c *Client) InvalidateBlockAsync(blockHash *chainhash.Hash) FutureInvalidat
 func (c *Client) InvalidateBlock(blockHash *chainhash.Hash) error {
 	return c.InvalidateBlockAsync(blockHash).Receive()
 }
+
+// FutureScanTxOutSetResult is a future promise to deliver the result of a
+// ScanTxOutSetAsync RPC invocation (or an applicable error).
+type FutureScanTxOutSetResult chan *response
+
+// Receive waits for the response promised by the future and returns the
+// result of the scan.  The abort action only reports whether a scan was
+// aborted, through Success, and the status action returns nil when no scan is
+// in progress.
+func (r FutureScanTxOutSetResult) Receive() (*sebtcjson.ScanTxOutSetResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a scantxoutset result object, which is null when
+	// the status of a scan is requested while none is in progress.
+	var result *sebtcjson.ScanTxOutSetResult
+	if err := json.Unmarshal(res, &result); err == nil {
+		return result, nil
+	}
+
+	// The abort action returns a bare boolean.
+	var aborted bool
+	err = json.Unmarshal(res, &aborted)
+	if err != nil {
+		return nil, err
+	}
+	return &sebtcjson.ScanTxOutSetResult{Success: aborted}, nil
+}
+
+// ScanTxOutSetAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See ScanTxOutSet for the blocking version and more details.
+func (c *Client) ScanTxOutSetAsync(action string, scanObjects []sebtcjson.ScanObject) FutureScanTxOutSetResult {
+	var objects *[]sebtcjson.ScanObject
+	if scanObjects != nil {
+		objects = &scanObjects
+	}
+	cmd := sebtcjson.NewScanTxOutSetCmd(action, objects)
+	return c.sendCmd(cmd)
+}
+
+// ScanTxOutSet scans the unspent transaction output set for the outputs the
+// passed scan objects describe, which allows to audit the balance of
+// descriptors, such as those of cold storage, without importing them into a
+// wallet.  The action is "start" to run a scan, which blocks until it is
+// complete, "status" to report the progress of the running scan, or "abort" to
+// abort it.  Only the start action takes scan objects and they should be nil
+// for the others.
+func (c *Client) ScanTxOutSet(action string, scanObjects []sebtcjson.ScanObject) (*sebtcjson.ScanTxOutSetResult, error) {
+	return c.ScanTxOutSetAsync(action, scanObjects).Receive()
+}
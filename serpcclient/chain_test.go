@@ -0,0 +1,1719 @@
+// Copyright (c) 2018 The box developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package serpcclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/gcs"
+	"github.com/btcsuite/btcutil/gcs/builder"
+	"github.com/zzpu/lib-bitcore/sebtcjson"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestGetBlockVerboseCached ensures cached blocks are served without issuing
+// any RPC, that the fields which change as the chain grows are omitted, and
+// that an elapsed ttl forces a refetch.
+func TestGetBlockVerboseCached(t *testing.T) {
+	t.Parallel()
+
+	hash, next := chainhash.Hash{0x01}, chainhash.Hash{0x02}
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		if method != "getblock" {
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		}
+		return &sebtcjson.GetBlockVerboseResult{
+			Hash:          hash.String(),
+			Confirmations: 11,
+			Height:        90,
+			NextHash:      next.String(),
+		}, nil
+	})
+	defer closeTestClient(client, server)
+
+	for i := 0; i < 3; i++ {
+		block, err := client.GetBlockVerboseCached(&hash, 0)
+		if err != nil {
+			t.Fatalf("GetBlockVerboseCached #%d: unexpected error: %v",
+				i, err)
+		}
+		if block.Hash != hash.String() || block.Height != 90 ||
+			block.Confirmations != 0 || block.NextHash != "" {
+
+			t.Errorf("GetBlockVerboseCached #%d: unexpected block - "+
+				"got %+v", i, block)
+		}
+	}
+	for _, method := range []string{"getblockcount", "getblockhash"} {
+		if n := server.calls(method); n != 0 {
+			t.Errorf("unexpected number of %s calls - got %d, "+
+				"want 0", method, n)
+		}
+	}
+	if n := server.calls("getblock"); n != 1 {
+		t.Errorf("unexpected number of getblock calls - got %d, want %d",
+			n, 1)
+	}
+
+	// An elapsed ttl must force the block to be fetched again.
+	time.Sleep(10 * time.Millisecond)
+	if _, err := client.GetBlockVerboseCached(&hash, time.Millisecond); err != nil {
+		t.Fatalf("GetBlockVerboseCached: unexpected error: %v", err)
+	}
+	if n := server.calls("getblock"); n != 2 {
+		t.Errorf("unexpected number of getblock calls - got %d, want %d",
+			n, 2)
+	}
+}
+
+// TestGetBlockVerboseTx ensures getblock is requested with a verbosity of 2 and
+// that the block header fields and the transactions decoded by the server are
+// unmarshalled.
+func TestGetBlockVerboseTx(t *testing.T) {
+	t.Parallel()
+
+	hash := chainhash.Hash{0x01}
+	txid := chainhash.Hash{0x02}.String()
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		if method != "getblock" {
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		}
+		return json.RawMessage(`{"hash":"` + hash.String() + `",` +
+			`"confirmations":3,"strippedsize":200,"size":250,` +
+			`"weight":850,"height":700000,"version":536870912,` +
+			`"merkleroot":"` + txid + `","time":1600000000,` +
+			`"nonce":7,"bits":"170e1ef9","difficulty":1.5,` +
+			`"tx":[{"txid":"` + txid + `","hash":"` + txid + `",` +
+			`"version":2,"size":150,"vsize":120,"locktime":0,` +
+			`"vin":[{"coinbase":"03a0ae0a","sequence":4294967295}],` +
+			`"vout":[{"value":6.25,"n":0,"scriptPubKey":{"asm":"",` +
+			`"hex":"51","type":"nonstandard"}}],"hex":"00"}]}`), nil
+	})
+	defer closeTestClient(client, server)
+
+	block, err := client.GetBlockVerboseTx(&hash)
+	if err != nil {
+		t.Fatalf("GetBlockVerboseTx: unexpected error: %v", err)
+	}
+	params := marshalParams(t, server.lastRequest("getblock"))
+	if want := `["` + hash.String() + `",2]`; params != want {
+		t.Errorf("unexpected params - got %s, want %s", params, want)
+	}
+
+	if block.Hash != hash.String() || block.Height != 700000 ||
+		block.Size != 250 || block.Weight != 850 {
+
+		t.Errorf("unexpected block fields - got %+v", block)
+	}
+	if len(block.Tx) != 1 {
+		t.Fatalf("unexpected number of transactions - got %d, want 1",
+			len(block.Tx))
+	}
+	tx := block.Tx[0]
+	if tx.Txid != txid || tx.Vsize != 120 || len(tx.Vin) != 1 ||
+		!tx.Vin[0].IsCoinBase() || len(tx.Vout) != 1 ||
+		tx.Vout[0].Value != 6.25 {
+
+		t.Errorf("unexpected transaction - got %+v", tx)
+	}
+}
+
+// TestBlockCacheEviction ensures the block cache evicts the least recently
+// used entry once it is full.
+func TestBlockCacheEviction(t *testing.T) {
+	t.Parallel()
+
+	cache := newBlockCache(2)
+	a, b, c := chainhash.Hash{0x0a}, chainhash.Hash{0x0b}, chainhash.Hash{0x0c}
+	cache.Add(&a, 1)
+	cache.Add(&b, 2)
+
+	// Touch a so that b becomes the least recently used entry.
+	if _, ok := cache.Get(&a, 0); !ok {
+		t.Fatalf("expected entry for %v", a)
+	}
+	cache.Add(&c, 3)
+
+	if _, ok := cache.Get(&b, 0); ok {
+		t.Errorf("expected entry for %v to be evicted", b)
+	}
+	if _, ok := cache.Get(&a, 0); !ok {
+		t.Errorf("expected entry for %v to be retained", a)
+	}
+	if _, ok := cache.Get(&c, 0); !ok {
+		t.Errorf("expected entry for %v to be retained", c)
+	}
+	if n := cache.Len(); n != 2 {
+		t.Errorf("unexpected cache size - got %d, want %d", n, 2)
+	}
+}
+
+// TestGetBlockHashRelative ensures negative heights are resolved against the
+// current block count and that heights before genesis are rejected.
+func TestGetBlockHashRelative(t *testing.T) {
+	t.Parallel()
+
+	const tip = 100
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		switch method {
+		case "getblockcount":
+			return tip, nil
+		case "getblockhash":
+			var height int64
+			if err := json.Unmarshal(params[0], &height); err != nil {
+				return nil, sebtcjson.ErrRPCInvalidParams
+			}
+			return chainhash.Hash{byte(height)}.String(), nil
+		}
+		return nil, sebtcjson.ErrRPCMethodNotFound
+	})
+	defer closeTestClient(client, server)
+
+	tests := []struct {
+		height int64
+		want   int64
+	}{
+		{height: 5, want: 5},
+		{height: -1, want: tip},
+		{height: -6, want: tip - 5},
+		{height: -(tip + 1), want: 0},
+	}
+
+	for i, test := range tests {
+		hash, err := client.GetBlockHashRelative(test.height)
+		if err != nil {
+			t.Errorf("Test #%d (%d) unexpected error: %v", i,
+				test.height, err)
+			continue
+		}
+		want := chainhash.Hash{byte(test.want)}
+		if *hash != want {
+			t.Errorf("Test #%d (%d) unexpected hash - got %v, want %v",
+				i, test.height, hash, want)
+		}
+	}
+
+	calls := server.calls("getblockhash")
+	if _, err := client.GetBlockHashRelative(-(tip + 2)); err == nil {
+		t.Errorf("expected error for height before genesis")
+	}
+	if n := server.calls("getblockhash"); n != calls {
+		t.Errorf("unexpected getblockhash call for invalid height")
+	}
+}
+
+// TestGetChainTips ensures the known tips of the block tree are requested
+// without parameters and decoded.
+func TestGetChainTips(t *testing.T) {
+	t.Parallel()
+
+	active, fork := chainhash.Hash{0xaa}, chainhash.Hash{0xbb}
+	payload := json.RawMessage(`[
+		{"height":600000,"hash":"` + active.String() + `","branchlen":0,"status":"active"},
+		{"height":599998,"hash":"` + fork.String() + `","branchlen":1,"status":"valid-fork"}
+	]`)
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		if method != "getchaintips" {
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		}
+		return payload, nil
+	})
+	defer closeTestClient(client, server)
+
+	tips, err := client.GetChainTips()
+	if err != nil {
+		t.Fatalf("GetChainTips: unexpected error: %v", err)
+	}
+	want := []sebtcjson.GetChainTipsResult{
+		{Height: 600000, Hash: active.String(), Status: "active"},
+		{Height: 599998, Hash: fork.String(), BranchLen: 1, Status: "valid-fork"},
+	}
+	if !reflect.DeepEqual(tips, want) {
+		t.Errorf("GetChainTips: unexpected tips - got %+v, want %+v",
+			tips, want)
+	}
+	if params := marshalParams(t, server.lastRequest("getchaintips")); params != `[]` {
+		t.Errorf("unexpected params - got %s, want []", params)
+	}
+}
+
+// TestDeepestForkDepth ensures the largest branch length among the non-active
+// chain tips is returned.
+func TestDeepestForkDepth(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		tips []sebtcjson.GetChainTipsResult
+		want int32
+	}{
+		{
+			name: "no forks",
+			tips: []sebtcjson.GetChainTipsResult{
+				{Height: 100, Hash: "a", BranchLen: 0, Status: "active"},
+			},
+			want: 0,
+		},
+		{
+			name: "single fork",
+			tips: []sebtcjson.GetChainTipsResult{
+				{Height: 100, Hash: "a", BranchLen: 0, Status: "active"},
+				{Height: 98, Hash: "b", BranchLen: 2, Status: "valid-fork"},
+			},
+			want: 2,
+		},
+		{
+			name: "multiple forks",
+			tips: []sebtcjson.GetChainTipsResult{
+				{Height: 100, Hash: "a", BranchLen: 0, Status: "active"},
+				{Height: 99, Hash: "b", BranchLen: 1, Status: "valid-fork"},
+				{Height: 97, Hash: "c", BranchLen: 5, Status: "headers-only"},
+				{Height: 95, Hash: "d", BranchLen: 3, Status: "invalid"},
+			},
+			want: 5,
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		tips := test.tips
+		client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+			if method != "getchaintips" {
+				return nil, sebtcjson.ErrRPCMethodNotFound
+			}
+			return tips, nil
+		})
+
+		depth, err := client.DeepestForkDepth()
+		closeTestClient(client, server)
+		if err != nil {
+			t.Errorf("Test #%d (%s) unexpected error: %v", i,
+				test.name, err)
+			continue
+		}
+		if depth != test.want {
+			t.Errorf("Test #%d (%s) unexpected depth - got %d, "+
+				"want %d", i, test.name, depth, test.want)
+		}
+	}
+}
+
+// TestGetChainTxStats ensures the optional parameters of getchaintxstats are
+// only sent when passed and that the statistics are decoded.
+func TestGetChainTxStats(t *testing.T) {
+	t.Parallel()
+
+	want := &sebtcjson.GetChainTxStatsResult{
+		Time:                   1550000000,
+		TxCount:                400000000,
+		WindowFinalBlockHash:   chainhash.Hash{0xaa}.String(),
+		WindowFinalBlockHeight: 560000,
+		WindowBlockCount:       4320,
+		WindowTxCount:          8640000,
+		WindowInterval:         2592000,
+		TxRate:                 3.333,
+	}
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		if method != "getchaintxstats" {
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		}
+		return want, nil
+	})
+	defer closeTestClient(client, server)
+
+	nBlocks := int32(4320)
+	hash := chainhash.Hash{0xaa}
+	tests := []struct {
+		name      string
+		nBlocks   *int32
+		blockHash *chainhash.Hash
+		params    string
+	}{
+		{
+			name:   "defaults",
+			params: `[]`,
+		},
+		{
+			name:    "nblocks",
+			nBlocks: &nBlocks,
+			params:  `[4320]`,
+		},
+		{
+			name:      "blockhash",
+			blockHash: &hash,
+			params:    `[null,"` + hash.String() + `"]`,
+		},
+		{
+			name:      "nblocks and blockhash",
+			nBlocks:   &nBlocks,
+			blockHash: &hash,
+			params:    `[4320,"` + hash.String() + `"]`,
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		stats, err := client.GetChainTxStats(test.nBlocks, test.blockHash)
+		if err != nil {
+			t.Errorf("Test #%d (%s) unexpected error: %v", i,
+				test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(stats, want) {
+			t.Errorf("Test #%d (%s) unexpected result - got %+v, "+
+				"want %+v", i, test.name, stats, want)
+		}
+		params := marshalParams(t, server.lastRequest("getchaintxstats"))
+		if params != test.params {
+			t.Errorf("Test #%d (%s) unexpected params - got %s, "+
+				"want %s", i, test.name, params, test.params)
+		}
+	}
+}
+
+// TestCheckFeeRate ensures the minimum relay fee rate is the higher of the
+// mempool and relay minimums rounded up to whole satoshi per virtual byte and
+// that fee rates are checked against it inclusively.
+func TestCheckFeeRate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mempoolMinFee float64
+		minRelayTxFee float64
+		feeRate       int64
+		floor         int64
+		ok            bool
+	}{
+		{
+			name:          "at floor",
+			mempoolMinFee: 0.00001,
+			minRelayTxFee: 0.00001,
+			feeRate:       1,
+			floor:         1,
+			ok:            true,
+		},
+		{
+			name:          "below floor",
+			mempoolMinFee: 0.00001,
+			minRelayTxFee: 0.00001,
+			feeRate:       0,
+			floor:         1,
+			ok:            false,
+		},
+		{
+			name:          "mempool minimum rounded up",
+			mempoolMinFee: 0.00001001,
+			minRelayTxFee: 0.00001,
+			feeRate:       1,
+			floor:         2,
+			ok:            false,
+		},
+		{
+			name:          "relay minimum higher",
+			mempoolMinFee: 0.00001,
+			minRelayTxFee: 0.00003,
+			feeRate:       3,
+			floor:         3,
+			ok:            true,
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		info := &sebtcjson.GetMempoolInfoResult{
+			MempoolMinFee: test.mempoolMinFee,
+			MinRelayTxFee: test.minRelayTxFee,
+		}
+		client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+			if method != "getmempoolinfo" {
+				return nil, sebtcjson.ErrRPCMethodNotFound
+			}
+			return info, nil
+		})
+
+		ok, floor, err := client.CheckFeeRate(test.feeRate)
+		closeTestClient(client, server)
+		if err != nil {
+			t.Errorf("Test #%d (%s) unexpected error: %v", i,
+				test.name, err)
+			continue
+		}
+		if ok != test.ok || floor != test.floor {
+			t.Errorf("Test #%d (%s) unexpected result - got %v "+
+				"(floor %d), want %v (floor %d)", i, test.name,
+				ok, floor, test.ok, test.floor)
+		}
+	}
+}
+
+// TestGetBlockStatsCache ensures getblockstats results are served from the
+// cache once fetched, that partial results are not cached and that purged
+// entries are fetched again.
+func TestGetBlockStatsCache(t *testing.T) {
+	t.Parallel()
+
+	hash := chainhash.Hash{0x02}
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		if method != "getblockstats" {
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		}
+		return &sebtcjson.GetBlockStatsResult{
+			Hash:               hash.String(),
+			Height:             90,
+			AverageFeeRate:     12,
+			FeeRatePercentiles: []int64{1, 2, 3, 4, 5},
+		}, nil
+	})
+	defer closeTestClient(client, server)
+
+	// Recreate the client with the cache enabled through the connection
+	// configuration.
+	config := *client.config
+	config.BlockStatsCacheSize = 10
+	client.Shutdown()
+	client, err := New(&config, nil)
+	if err != nil {
+		t.Fatalf("New: unexpected error: %v", err)
+	}
+	defer func() {
+		client.Shutdown()
+		client.WaitForShutdown()
+	}()
+
+	// The first request by height must populate the cache keyed by the
+	// returned block hash.
+	if _, err := client.GetBlockStats(90, nil); err != nil {
+		t.Fatalf("GetBlockStats: unexpected error: %v", err)
+	}
+	stats, err := client.GetBlockStats(&hash, nil)
+	if err != nil {
+		t.Fatalf("GetBlockStats: unexpected error: %v", err)
+	}
+	if stats.AverageFeeRate != 12 {
+		t.Errorf("unexpected average fee rate - got %d, want %d",
+			stats.AverageFeeRate, 12)
+	}
+
+	// Modifying a returned result must not modify the cached entry.
+	stats.FeeRatePercentiles[0] = 100
+	stats, err = client.GetBlockStats(&hash, nil)
+	if err != nil {
+		t.Fatalf("GetBlockStats: unexpected error: %v", err)
+	}
+	if stats.FeeRatePercentiles[0] != 1 {
+		t.Errorf("cached fee rate percentiles were modified - got %d, "+
+			"want %d", stats.FeeRatePercentiles[0], 1)
+	}
+	if _, err := client.GetBlockStats(hash.String(), nil); err != nil {
+		t.Fatalf("GetBlockStats: unexpected error: %v", err)
+	}
+	if n := server.calls("getblockstats"); n != 1 {
+		t.Errorf("unexpected number of getblockstats calls - got %d, "+
+			"want %d", n, 1)
+	}
+
+	// Requests for a subset of the stats always go to the server.
+	if _, err := client.GetBlockStats(&hash, &[]string{"avgfeerate"}); err != nil {
+		t.Fatalf("GetBlockStats: unexpected error: %v", err)
+	}
+	if n := server.calls("getblockstats"); n != 2 {
+		t.Errorf("unexpected number of getblockstats calls - got %d, "+
+			"want %d", n, 2)
+	}
+
+	client.PurgeBlockStats(&hash)
+	if _, err := client.GetBlockStats(&hash, nil); err != nil {
+		t.Fatalf("GetBlockStats: unexpected error: %v", err)
+	}
+	if n := server.calls("getblockstats"); n != 3 {
+		t.Errorf("unexpected number of getblockstats calls - got %d, "+
+			"want %d", n, 3)
+	}
+	if got, want := marshalParams(t, server.lastRequest("getblockstats")),
+		`["`+hash.String()+`"]`; got != want {
+		t.Errorf("unexpected params - got %s, want %s", got, want)
+	}
+
+	if _, err := client.GetBlockStats(1.5, nil); err == nil {
+		t.Errorf("expected error for unsupported hash or height type")
+	}
+}
+
+// TestWatchMempool ensures successive memory pool snapshots are turned into
+// the expected added and removed events and that the channel is closed once
+// the context is cancelled.
+func TestWatchMempool(t *testing.T) {
+	t.Parallel()
+
+	a, b, c := chainhash.Hash{0x0a}, chainhash.Hash{0x0b}, chainhash.Hash{0x0c}
+	snapshots := [][]string{
+		{a.String(), b.String()},
+		{b.String(), c.String()},
+	}
+	var polls int32
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		if method != "getrawmempool" {
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		}
+		n := int(atomic.AddInt32(&polls, 1)) - 1
+		if n >= len(snapshots) {
+			n = len(snapshots) - 1
+		}
+		return snapshots[n], nil
+	})
+	defer closeTestClient(client, server)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := client.WatchMempool(ctx, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("WatchMempool: unexpected error: %v", err)
+	}
+
+	want := []MempoolEvent{
+		{Type: MempoolTxAdded, TxID: a},
+		{Type: MempoolTxAdded, TxID: b},
+		{Type: MempoolTxAdded, TxID: c},
+		{Type: MempoolTxRemoved, TxID: a},
+	}
+	for i, w := range want {
+		select {
+		case event := <-events:
+			if event != w {
+				t.Errorf("Test #%d unexpected event - got %s %v, "+
+					"want %s %v", i, event.Type, event.TxID,
+					w.Type, w.TxID)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Test #%d timed out waiting for event", i)
+		}
+	}
+
+	cancel()
+	select {
+	case event, ok := <-events:
+		if ok {
+			t.Errorf("unexpected event after cancel - got %s %v",
+				event.Type, event.TxID)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("event channel was not closed after cancel")
+	}
+}
+
+// TestStartMinFeeRefresher ensures the fee floor follows the minimum relay fee
+// rate of the server, keeps rates below one satoshi per virtual byte exactly,
+// and stops being refreshed once the context is done.
+func TestStartMinFeeRefresher(t *testing.T) {
+	t.Parallel()
+
+	// The first snapshot is bounded by minrelaytxfee at 0.15 sat/vB and
+	// the second by mempoolminfee.
+	snapshots := []sebtcjson.GetMempoolInfoResult{
+		{MempoolMinFee: 0.000001, MinRelayTxFee: 0.0000015},
+		{MempoolMinFee: 0.00005, MinRelayTxFee: 0.00001},
+	}
+	var polls int32
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		if method != "getmempoolinfo" {
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		}
+		n := int(atomic.AddInt32(&polls, 1)) - 1
+		if n >= len(snapshots) {
+			n = len(snapshots) - 1
+		}
+		return &snapshots[n], nil
+	})
+	defer closeTestClient(client, server)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	floor, err := client.StartMinFeeRefresher(ctx, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("StartMinFeeRefresher: unexpected error: %v", err)
+	}
+	if got := floor.RatePerKvB(); got != 150 {
+		t.Errorf("StartMinFeeRefresher: unexpected initial rate - got "+
+			"%d sat/kvB, want 150", got)
+	}
+	if got := floor.Rate(); got != 1 {
+		t.Errorf("StartMinFeeRefresher: unexpected initial rate - got "+
+			"%d sat/vB, want 1", got)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for floor.RatePerKvB() != 5000 {
+		if time.Now().After(deadline) {
+			t.Fatalf("StartMinFeeRefresher: timed out waiting for "+
+				"rate 5000, got %d", floor.RatePerKvB())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := floor.Rate(); got != 5 {
+		t.Errorf("StartMinFeeRefresher: unexpected rate - got %d "+
+			"sat/vB, want 5", got)
+	}
+
+	cancel()
+	time.Sleep(20 * time.Millisecond)
+	stopped := server.calls("getmempoolinfo")
+	time.Sleep(50 * time.Millisecond)
+	if got := server.calls("getmempoolinfo"); got != stopped {
+		t.Errorf("StartMinFeeRefresher: polled %d times after cancel",
+			got-stopped)
+	}
+}
+
+// TestGetUnspentOutputs ensures the gettxout results are returned in outpoint
+// order with nil for spent outputs, that mempool outputs are flagged, and that
+// the outputs are queried again when the chain tip changes underneath them.
+func TestGetUnspentOutputs(t *testing.T) {
+	t.Parallel()
+
+	tipA, tipB := chainhash.Hash{0xaa}, chainhash.Hash{0xbb}
+	confirmed := chainhash.Hash{0x01}
+	unconfirmed := chainhash.Hash{0x02}
+	spent := chainhash.Hash{0x03}
+
+	var mtx sync.Mutex
+	tip := tipA
+	bestCalls := 0
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		mtx.Lock()
+		defer mtx.Unlock()
+
+		switch method {
+		case "getbestblockhash":
+			bestCalls++
+			if bestCalls == 2 {
+				tip = tipB
+			}
+			return tip.String(), nil
+
+		case "gettxout":
+			var txid string
+			var includeMempool bool
+			if err := json.Unmarshal(params[0], &txid); err != nil {
+				return nil, sebtcjson.NewRPCError(sebtcjson.ErrRPCInvalidParameter, "invalid parameter")
+			}
+			if err := json.Unmarshal(params[2], &includeMempool); err != nil || !includeMempool {
+				return nil, sebtcjson.NewRPCError(sebtcjson.ErrRPCInvalidParameter, "invalid parameter")
+			}
+			switch txid {
+			case confirmed.String():
+				return &sebtcjson.GetTxOutResult{
+					BestBlock:     tip.String(),
+					Confirmations: 3,
+					Value:         1,
+				}, nil
+			case unconfirmed.String():
+				return &sebtcjson.GetTxOutResult{
+					BestBlock: tip.String(),
+					Value:     2,
+				}, nil
+			}
+			return nil, nil
+		}
+		return nil, sebtcjson.ErrRPCMethodNotFound
+	})
+	defer closeTestClient(client, server)
+
+	outpoints := []wire.OutPoint{
+		{Hash: confirmed, Index: 0},
+		{Hash: spent, Index: 1},
+		{Hash: unconfirmed, Index: 2},
+	}
+	results, err := client.GetUnspentOutputs(outpoints, true)
+	if err != nil {
+		t.Fatalf("GetUnspentOutputs: unexpected error: %v", err)
+	}
+	if len(results) != len(outpoints) {
+		t.Fatalf("GetUnspentOutputs: got %d results, want %d",
+			len(results), len(outpoints))
+	}
+	if results[1] != nil {
+		t.Errorf("GetUnspentOutputs: spent output got %+v, want nil",
+			results[1])
+	}
+	for _, i := range []int{0, 2} {
+		result := results[i]
+		if result == nil {
+			t.Fatalf("Test #%d unexpected nil result", i)
+		}
+		if result.OutPoint != outpoints[i] {
+			t.Errorf("Test #%d unexpected outpoint - got %v, want %v",
+				i, result.OutPoint, outpoints[i])
+		}
+		if result.BestBlock != tipB.String() {
+			t.Errorf("Test #%d unexpected best block - got %s, "+
+				"want %s", i, result.BestBlock, tipB)
+		}
+		if result.InMempool != (i == 2) {
+			t.Errorf("Test #%d unexpected mempool flag - got %v",
+				i, result.InMempool)
+		}
+	}
+	if got := server.calls("gettxout"); got != 2*len(outpoints) {
+		t.Errorf("GetUnspentOutputs: got %d gettxout calls, want %d",
+			got, 2*len(outpoints))
+	}
+}
+
+// TestBlockMatchesScripts ensures block filters are matched against the passed
+// scripts and that servers which do not serve filters are reported as such.
+func TestBlockMatchesScripts(t *testing.T) {
+	t.Parallel()
+
+	blockHash := chainhash.Hash{0x01, 0x02, 0x03}
+	emptyHash := chainhash.Hash{0x04}
+	noIndexHash := chainhash.Hash{0x05}
+	unknownHash := chainhash.Hash{0x06}
+	matching := []byte{0x00, 0x14, 0x01}
+	other := []byte{0x00, 0x14, 0x02}
+
+	filter, err := gcs.BuildGCSFilter(builder.DefaultP, builder.DefaultM,
+		builder.DeriveKey(&blockHash), [][]byte{matching})
+	if err != nil {
+		t.Fatalf("BuildGCSFilter: unexpected error: %v", err)
+	}
+	filterBytes, err := filter.NBytes()
+	if err != nil {
+		t.Fatalf("NBytes: unexpected error: %v", err)
+	}
+
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		if method != "getblockfilter" {
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		}
+		var hash string
+		if err := json.Unmarshal(params[0], &hash); err != nil {
+			return nil, sebtcjson.NewRPCError(sebtcjson.ErrRPCInvalidParameter,
+				"invalid parameter")
+		}
+		switch hash {
+		case blockHash.String():
+			return &sebtcjson.GetBlockFilterResult{
+				Filter: hex.EncodeToString(filterBytes),
+			}, nil
+		case emptyHash.String():
+			return &sebtcjson.GetBlockFilterResult{Filter: "00"}, nil
+		case noIndexHash.String():
+			return nil, sebtcjson.NewRPCError(sebtcjson.ErrRPCMisc,
+				"Index is not enabled for filtertype basic")
+		}
+		return nil, sebtcjson.ErrRPCMethodNotFound
+	})
+	defer closeTestClient(client, server)
+
+	tests := []struct {
+		name    string
+		hash    chainhash.Hash
+		scripts [][]byte
+		match   bool
+		err     error
+	}{
+		{
+			name:    "match",
+			hash:    blockHash,
+			scripts: [][]byte{other, matching},
+			match:   true,
+		},
+		{
+			name:    "no match",
+			hash:    blockHash,
+			scripts: [][]byte{other},
+		},
+		{
+			name: "no scripts",
+			hash: blockHash,
+		},
+		{
+			name:    "empty filter",
+			hash:    emptyHash,
+			scripts: [][]byte{matching},
+		},
+		{
+			name:    "index not enabled",
+			hash:    noIndexHash,
+			scripts: [][]byte{matching},
+			err:     ErrBlockFiltersUnsupported,
+		},
+		{
+			name:    "method not found",
+			hash:    unknownHash,
+			scripts: [][]byte{matching},
+			err:     ErrBlockFiltersUnsupported,
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		match, err := client.BlockMatchesScripts(&test.hash, test.scripts)
+		if err != test.err {
+			t.Errorf("Test #%d (%s) unexpected error - got %v, "+
+				"want %v", i, test.name, err, test.err)
+			continue
+		}
+		if match != test.match {
+			t.Errorf("Test #%d (%s) unexpected match - got %v, "+
+				"want %v", i, test.name, match, test.match)
+		}
+	}
+}
+
+// TestGetAddressUTXOs ensures both response shapes of getaddressutxos are
+// decoded according to whether chain info was requested.
+func TestGetAddressUTXOs(t *testing.T) {
+	t.Parallel()
+
+	addr, err := btcutil.NewAddressPubKeyHash(make([]byte, 20),
+		&chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatalf("NewAddressPubKeyHash: unexpected error: %v", err)
+	}
+	utxos := []sebtcjson.AddressUtxo{{
+		Address:     addr.EncodeAddress(),
+		TxID:        chainhash.Hash{0x01}.String(),
+		OutputIndex: 1,
+		Script:      "76a914000000000000000000000000000000000000000088ac",
+		Satoshis:    5000,
+		Height:      120,
+	}}
+
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		if method != "getaddressutxos" {
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		}
+		var request sebtcjson.GetAddressUtxosRequest
+		if err := json.Unmarshal(params[0], &request); err != nil {
+			return nil, sebtcjson.NewRPCError(sebtcjson.ErrRPCInvalidParameter,
+				"invalid request")
+		}
+		if !request.ChainInfo {
+			return utxos, nil
+		}
+		return &sebtcjson.GetAddressUtxosChainInfoResult{
+			Utxos:  utxos,
+			Hash:   chainhash.Hash{0xaa}.String(),
+			Height: 150,
+		}, nil
+	})
+	defer closeTestClient(client, server)
+
+	tests := []struct {
+		chainInfo bool
+		params    string
+		want      *sebtcjson.GetAddressUtxosChainInfoResult
+	}{
+		{
+			chainInfo: false,
+			params:    `{"addresses":["` + addr.EncodeAddress() + `"]}`,
+			want:      &sebtcjson.GetAddressUtxosChainInfoResult{Utxos: utxos},
+		},
+		{
+			chainInfo: true,
+			params: `{"addresses":["` + addr.EncodeAddress() +
+				`"],"chainInfo":true}`,
+			want: &sebtcjson.GetAddressUtxosChainInfoResult{
+				Utxos:  utxos,
+				Hash:   chainhash.Hash{0xaa}.String(),
+				Height: 150,
+			},
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		result, err := client.GetAddressUTXOs([]btcutil.Address{addr},
+			test.chainInfo)
+		if err != nil {
+			t.Errorf("Test #%d unexpected error: %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(result, test.want) {
+			t.Errorf("Test #%d unexpected result - got %+v, want %+v",
+				i, result, test.want)
+		}
+		params := server.lastRequest("getaddressutxos").Params
+		if len(params) != 1 || string(params[0]) != test.params {
+			t.Errorf("Test #%d unexpected params %s", i, params)
+		}
+	}
+}
+
+// TestGetAddressBalance ensures the addresses are grouped under the addresses
+// key of the request and the mempool balance is only requested and populated
+// when asked for.
+func TestGetAddressBalance(t *testing.T) {
+	t.Parallel()
+
+	addr, err := btcutil.NewAddressPubKeyHash(make([]byte, 20),
+		&chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatalf("NewAddressPubKeyHash: unexpected error: %v", err)
+	}
+
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		if method != "getaddressbalance" {
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		}
+		var request sebtcjson.GetAddressBalanceRequest
+		if err := json.Unmarshal(params[0], &request); err != nil {
+			return nil, sebtcjson.NewRPCError(sebtcjson.ErrRPCInvalidParameter,
+				"invalid request")
+		}
+		result := map[string]int64{"balance": 5000, "received": 12000}
+		if request.Mempool {
+			result["mempoolBalance"] = -1500
+		}
+		return result, nil
+	})
+	defer closeTestClient(client, server)
+
+	tests := []struct {
+		includeMempool bool
+		params         string
+		want           AddressBalance
+	}{
+		{
+			includeMempool: false,
+			params:         `{"addresses":["` + addr.EncodeAddress() + `"]}`,
+			want:           AddressBalance{Balance: 5000, Received: 12000},
+		},
+		{
+			includeMempool: true,
+			params: `{"addresses":["` + addr.EncodeAddress() +
+				`"],"mempool":true}`,
+			want: AddressBalance{
+				Balance:        5000,
+				Received:       12000,
+				MempoolBalance: -1500,
+			},
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		balance, err := client.GetAddressBalance([]btcutil.Address{addr},
+			test.includeMempool)
+		if err != nil {
+			t.Errorf("Test #%d unexpected error: %v", i, err)
+			continue
+		}
+		if *balance != test.want {
+			t.Errorf("Test #%d unexpected balance - got %+v, want %+v",
+				i, *balance, test.want)
+		}
+		params := server.lastRequest("getaddressbalance").Params
+		if len(params) != 1 || string(params[0]) != test.params {
+			t.Errorf("Test #%d unexpected params %s", i, params)
+		}
+	}
+}
+
+// TestGetMempoolEntry ensures the entry of a memory pool transaction is
+// requested for the passed hash and that its fees, reported in BTC, and its
+// ancestor and descendant statistics are decoded.
+func TestGetMempoolEntry(t *testing.T) {
+	t.Parallel()
+
+	txid := chainhash.Hash{0x01}.String()
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		if method != "getmempoolentry" {
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		}
+		return json.RawMessage(`{"size":225,"fee":0.0000452,` +
+			`"modifiedfee":0.0001452,"time":1600000000,` +
+			`"height":650000,"descendantcount":2,` +
+			`"descendantsize":450,"descendantfees":9040,` +
+			`"ancestorcount":3,"ancestorsize":675,` +
+			`"ancestorfees":13560,"depends":["` + txid + `"]}`), nil
+	})
+	defer closeTestClient(client, server)
+
+	entry, err := client.GetMempoolEntry(txid)
+	if err != nil {
+		t.Fatalf("GetMempoolEntry: unexpected error: %v", err)
+	}
+	want := &sebtcjson.GetMempoolEntryResult{
+		Size:            225,
+		Fee:             0.0000452,
+		ModifiedFee:     0.0001452,
+		Time:            1600000000,
+		Height:          650000,
+		DescendantCount: 2,
+		DescendantSize:  450,
+		DescendantFees:  9040,
+		AncestorCount:   3,
+		AncestorSize:    675,
+		AncestorFees:    13560,
+		Depends:         []string{txid},
+	}
+	if !reflect.DeepEqual(entry, want) {
+		t.Errorf("unexpected entry - got %+v, want %+v", entry, want)
+	}
+
+	params := marshalParams(t, server.lastRequest("getmempoolentry"))
+	if want := `["` + txid + `"]`; params != want {
+		t.Errorf("unexpected params - got %s, want %s", params, want)
+	}
+}
+
+// TestGetRawMempoolVerbose ensures getrawmempool is requested with the verbose
+// flag set and that the entries are decoded keyed by transaction hash, whether
+// the server reports the fees flat or nested.
+func TestGetRawMempoolVerbose(t *testing.T) {
+	t.Parallel()
+
+	flat := chainhash.Hash{0x01}.String()
+	nested := chainhash.Hash{0x02}.String()
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		if method != "getrawmempool" {
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		}
+		return json.RawMessage(`{"` + flat + `":{"size":225,"vsize":144,` +
+			`"fee":0.0000452,"modifiedfee":0.0000452,"time":1600000000,` +
+			`"height":650000,"descendantcount":1,"descendantfees":4520,` +
+			`"ancestorcount":1,"ancestorfees":4520,"depends":[]},` +
+			`"` + nested + `":{"size":225,"vsize":144,` +
+			`"time":1600000000,"height":650000,"descendantcount":1,` +
+			`"ancestorcount":2,"depends":["` + flat + `"],` +
+			`"fees":{"base":0.0000452,"modified":0.0000452,` +
+			`"ancestor":0.0000904,"descendant":0.0000452}}}`), nil
+	})
+	defer closeTestClient(client, server)
+
+	entries, err := client.GetRawMempoolVerbose()
+	if err != nil {
+		t.Fatalf("GetRawMempoolVerbose: unexpected error: %v", err)
+	}
+	if params := marshalParams(t, server.lastRequest("getrawmempool")); params != "[true]" {
+		t.Errorf("unexpected params - got %s, want [true]", params)
+	}
+
+	want := map[string]sebtcjson.GetRawMempoolVerboseResult{
+		flat: {
+			Size:            225,
+			Vsize:           144,
+			Fee:             0.0000452,
+			ModifiedFee:     0.0000452,
+			Time:            1600000000,
+			Height:          650000,
+			DescendantCount: 1,
+			DescendantFees:  4520,
+			AncestorCount:   1,
+			AncestorFees:    4520,
+			Depends:         []string{},
+		},
+		nested: {
+			Size:            225,
+			Vsize:           144,
+			Fee:             0.0000452,
+			ModifiedFee:     0.0000452,
+			Time:            1600000000,
+			Height:          650000,
+			DescendantCount: 1,
+			DescendantFees:  4520,
+			AncestorCount:   2,
+			AncestorFees:    9040,
+			Depends:         []string{flat},
+			Fees: &sebtcjson.GetMempoolEntryFeesResult{
+				Base:       0.0000452,
+				Modified:   0.0000452,
+				Ancestor:   0.0000904,
+				Descendant: 0.0000452,
+			},
+		},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("unexpected entries - got %+v, want %+v", entries, want)
+	}
+}
+
+// TestIsReplaceable ensures transactions are reported as replaceable when any
+// input signals so or the mempool reports an inherited signal, and that
+// confirmed and unknown transactions are reported distinctly, including those
+// which leave the mempool while they are being checked.
+func TestIsReplaceable(t *testing.T) {
+	t.Parallel()
+
+	signaling := chainhash.Hash{0x01}
+	final := chainhash.Hash{0x02}
+	inherited := chainhash.Hash{0x03}
+	confirmed := chainhash.Hash{0x04}
+	unknown := chainhash.Hash{0x05}
+	minedIndexed := chainhash.Hash{0x06}
+	mined := chainhash.Hash{0x07}
+	evicted := chainhash.Hash{0x08}
+
+	replaceable := true
+	rawTxs := map[string]*sebtcjson.TxRawResult{
+		signaling.String(): {
+			Vin: []sebtcjson.Vin{
+				{Sequence: wire.MaxTxInSequenceNum},
+				{Sequence: wire.MaxTxInSequenceNum - 2},
+			},
+		},
+		final.String(): {
+			Vin: []sebtcjson.Vin{{Sequence: wire.MaxTxInSequenceNum - 1}},
+		},
+		inherited.String(): {
+			Vin: []sebtcjson.Vin{{Sequence: wire.MaxTxInSequenceNum}},
+		},
+		confirmed.String(): {
+			Vin:           []sebtcjson.Vin{{Sequence: 0}},
+			BlockHash:     chainhash.Hash{0xaa}.String(),
+			Confirmations: 1,
+		},
+	}
+
+	// These transactions leave the mempool once they were fetched.  The
+	// first is found again through the transaction index, while the others
+	// are only found in the UTXO set when they were mined.
+	left := []chainhash.Hash{minedIndexed, mined, evicted}
+	for _, hash := range left {
+		rawTxs[hash.String()] = &sebtcjson.TxRawResult{
+			Vin:  []sebtcjson.Vin{{Sequence: wire.MaxTxInSequenceNum}},
+			Vout: []sebtcjson.Vout{{N: 0}, {N: 1}},
+		}
+	}
+	indexed := &sebtcjson.TxRawResult{
+		BlockHash:     chainhash.Hash{0xbb}.String(),
+		Confirmations: 1,
+	}
+	var fetchedMtx sync.Mutex
+	fetched := make(map[string]bool)
+	entries := map[string]*sebtcjson.GetMempoolEntryResult{
+		final.String():     {},
+		inherited.String(): {BIP125Replaceable: &replaceable},
+	}
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		var txid string
+		if err := json.Unmarshal(params[0], &txid); err != nil {
+			return nil, sebtcjson.NewRPCError(sebtcjson.ErrRPCInvalidParameter, "invalid parameter")
+		}
+
+		switch method {
+		case "getrawtransaction":
+			fetchedMtx.Lock()
+			again := fetched[txid]
+			fetched[txid] = true
+			fetchedMtx.Unlock()
+			switch {
+			case again && txid == minedIndexed.String():
+				return indexed, nil
+			case again && (txid == mined.String() || txid == evicted.String()):
+				return nil, sebtcjson.NewRPCError(sebtcjson.ErrRPCNoTxInfo,
+					"No such mempool transaction")
+			}
+			if tx, ok := rawTxs[txid]; ok {
+				return tx, nil
+			}
+			return nil, sebtcjson.NewRPCError(sebtcjson.ErrRPCNoTxInfo,
+				"No such mempool or blockchain transaction")
+
+		case "getmempoolentry":
+			if entry, ok := entries[txid]; ok {
+				return entry, nil
+			}
+			return nil, sebtcjson.NewRPCError(sebtcjson.ErrRPCNoTxInfo,
+				"Transaction not in mempool")
+
+		case "gettxout":
+			var index uint32
+			if err := json.Unmarshal(params[1], &index); err != nil {
+				return nil, sebtcjson.ErrRPCInvalidParams
+			}
+			if txid == mined.String() && index == 1 {
+				return &sebtcjson.GetTxOutResult{Confirmations: 1}, nil
+			}
+			return nil, nil
+		}
+		return nil, sebtcjson.ErrRPCMethodNotFound
+	})
+	defer closeTestClient(client, server)
+
+	tests := []struct {
+		name string
+		hash chainhash.Hash
+		want bool
+		err  error
+	}{
+		{name: "signaling input", hash: signaling, want: true},
+		{name: "final inputs", hash: final, want: false},
+		{name: "inherited signal", hash: inherited, want: true},
+		{name: "confirmed", hash: confirmed, err: ErrTxConfirmed},
+		{name: "unknown", hash: unknown, err: ErrTxNotFound},
+		{name: "mined with index", hash: minedIndexed, err: ErrTxConfirmed},
+		{name: "mined", hash: mined, err: ErrTxConfirmed},
+		{name: "evicted", hash: evicted, err: ErrTxNotFound},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		got, err := client.IsReplaceable(&test.hash)
+		if err != test.err {
+			t.Errorf("Test #%d (%s) unexpected error - got %v, want %v",
+				i, test.name, err, test.err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("Test #%d (%s) unexpected result - got %v, want %v",
+				i, test.name, got, test.want)
+		}
+	}
+
+	// Transactions which signal themselves need no mempool lookup.
+	if got := server.calls("getmempoolentry"); got != 5 {
+		t.Errorf("IsReplaceable: got %d getmempoolentry calls, want 5",
+			got)
+	}
+
+	// The outputs of the transaction found through the index are not
+	// looked up, while those of the evicted one are exhausted.
+	if got := server.calls("gettxout"); got != 4 {
+		t.Errorf("IsReplaceable: got %d gettxout calls, want 4", got)
+	}
+}
+
+// TestGetBlockByHeightPruned ensures blocks below the prune height of a pruned
+// server are rejected with ErrBlockPruned without being requested, that the
+// prune height is cached, and that the check is disabled for servers which are
+// not pruned.
+func TestGetBlockByHeightPruned(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := chaincfg.MainNetParams.GenesisBlock.Serialize(&buf); err != nil {
+		t.Fatalf("Serialize: unexpected error: %v", err)
+	}
+	blockHex := hex.EncodeToString(buf.Bytes())
+
+	tests := []struct {
+		name      string
+		chainInfo string
+		height    int64
+		pruned    bool
+	}{
+		{
+			name:      "pruned below prune height",
+			chainInfo: `{"chain":"main","blocks":2000,"pruned":true,"pruneheight":1000}`,
+			height:    999,
+			pruned:    true,
+		},
+		{
+			name:      "pruned at prune height",
+			chainInfo: `{"chain":"main","blocks":2000,"pruned":true,"pruneheight":1000}`,
+			height:    1000,
+		},
+		{
+			name:      "not pruned",
+			chainInfo: `{"chain":"main","blocks":2000,"pruned":false}`,
+			height:    0,
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		test := test
+		client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+			switch method {
+			case "getblockchaininfo":
+				return json.RawMessage(test.chainInfo), nil
+			case "getblockhash":
+				return chaincfg.MainNetParams.GenesisHash.String(), nil
+			case "getblock":
+				return blockHex, nil
+			}
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		})
+
+		for j := 0; j < 2; j++ {
+			_, err := client.GetBlockByHeight(test.height)
+			if test.pruned && err != ErrBlockPruned {
+				t.Errorf("Test #%d (%s) call #%d: got %v, want %v", i,
+					test.name, j, err, ErrBlockPruned)
+			}
+			if !test.pruned && err != nil {
+				t.Errorf("Test #%d (%s) call #%d: unexpected error: %v",
+					i, test.name, j, err)
+			}
+		}
+		wantBlocks := 2
+		if test.pruned {
+			wantBlocks = 0
+		}
+		if got := server.calls("getblock"); got != wantBlocks {
+			t.Errorf("Test #%d (%s) got %d getblock calls, want %d", i,
+				test.name, got, wantBlocks)
+		}
+		if got := server.calls("getblockchaininfo"); got != 1 {
+			t.Errorf("Test #%d (%s) got %d getblockchaininfo calls, "+
+				"want 1", i, test.name, got)
+		}
+		closeTestClient(client, server)
+	}
+}
+
+// TestGetBlockPrunedError ensures the error a pruned server returns for a block
+// it no longer stores matches ErrBlockPruned while still unwrapping to the
+// server error.
+func TestGetBlockPrunedError(t *testing.T) {
+	t.Parallel()
+
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		if method != "getblock" {
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		}
+		return nil, sebtcjson.NewRPCError(sebtcjson.ErrRPCMisc,
+			"Block not available (pruned data)")
+	})
+	defer closeTestClient(client, server)
+
+	_, err := client.GetBlock(chaincfg.MainNetParams.GenesisHash)
+	if !errors.Is(err, ErrBlockPruned) {
+		t.Errorf("GetBlock: got %v, want %v", err, ErrBlockPruned)
+	}
+	var rpcErr *sebtcjson.RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != sebtcjson.ErrRPCMisc {
+		t.Errorf("GetBlock: error %v does not unwrap to the server "+
+			"error", err)
+	}
+}
+
+// TestLowestStoredBlock ensures the prune fields of getblockchaininfo are only
+// set for pruned servers and that LowestStoredBlock reports the prune height,
+// or zero when the server is not pruned.
+func TestLowestStoredBlock(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		chainInfo  string
+		want       int32
+		automatic  *bool
+		targetSize *int64
+	}{
+		{
+			name: "pruned",
+			chainInfo: `{"chain":"main","blocks":2000,"pruned":true,` +
+				`"pruneheight":1200,"automatic_pruning":true,` +
+				`"prune_target_size":576716800}`,
+			want:       1200,
+			automatic:  sebtcjson.Bool(true),
+			targetSize: sebtcjson.Int64(576716800),
+		},
+		{
+			name: "manually pruned",
+			chainInfo: `{"chain":"main","blocks":2000,"pruned":true,` +
+				`"pruneheight":300,"automatic_pruning":false}`,
+			want:      300,
+			automatic: sebtcjson.Bool(false),
+		},
+		{
+			name:      "not pruned",
+			chainInfo: `{"chain":"main","blocks":2000,"pruned":false}`,
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		test := test
+		client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+			if method != "getblockchaininfo" {
+				return nil, sebtcjson.ErrRPCMethodNotFound
+			}
+			return json.RawMessage(test.chainInfo), nil
+		})
+
+		info, err := client.GetBlockChainInfo()
+		if err != nil {
+			t.Errorf("Test #%d (%s) GetBlockChainInfo: unexpected "+
+				"error: %v", i, test.name, err)
+			closeTestClient(client, server)
+			continue
+		}
+		if !reflect.DeepEqual(info.AutomaticPruning, test.automatic) {
+			t.Errorf("Test #%d (%s) unexpected automatic pruning - "+
+				"got %v, want %v", i, test.name,
+				info.AutomaticPruning, test.automatic)
+		}
+		if !reflect.DeepEqual(info.PruneTargetSize, test.targetSize) {
+			t.Errorf("Test #%d (%s) unexpected prune target size - "+
+				"got %v, want %v", i, test.name,
+				info.PruneTargetSize, test.targetSize)
+		}
+
+		height, err := client.LowestStoredBlock()
+		closeTestClient(client, server)
+		if err != nil {
+			t.Errorf("Test #%d (%s) LowestStoredBlock: unexpected "+
+				"error: %v", i, test.name, err)
+			continue
+		}
+		if height != test.want {
+			t.Errorf("Test #%d (%s) unexpected height - got %d, "+
+				"want %d", i, test.name, height, test.want)
+		}
+	}
+}
+
+// TestSupplyInfo ensures SupplyInfo requests the coinstatsindex as asked and
+// reports an index which can not be used with ErrCoinStatsIndexUnavailable.
+func TestSupplyInfo(t *testing.T) {
+	t.Parallel()
+
+	var syncing int32
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		if method != "gettxoutsetinfo" {
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		}
+		if atomic.LoadInt32(&syncing) != 0 {
+			return nil, sebtcjson.NewRPCError(sebtcjson.ErrRPCMisc,
+				"Unable to get data because coinstatsindex is "+
+					"still syncing. Current height: 1000")
+		}
+		return json.RawMessage(`{"height":800000,"bestblock":"00ff",` +
+			`"txouts":111,"bogosize":222,"muhash":"abcd",` +
+			`"total_amount":19400000.12345678,` +
+			`"total_unspendable_amount":220.1}`), nil
+	})
+	defer closeTestClient(client, server)
+
+	supply, err := client.SupplyInfo(true)
+	if err != nil {
+		t.Fatalf("SupplyInfo: unexpected error: %v", err)
+	}
+	want := &CoinSupply{
+		Height:      800000,
+		BestBlock:   "00ff",
+		TotalAmount: 1940000012345678,
+	}
+	if !reflect.DeepEqual(supply, want) {
+		t.Errorf("SupplyInfo: got %+v, want %+v", supply, want)
+	}
+	if got := marshalParams(t, server.lastRequest("gettxoutsetinfo")); got != "[null,null,true]" {
+		t.Errorf("gettxoutsetinfo params: got %s, want [null,null,true]",
+			got)
+	}
+
+	atomic.StoreInt32(&syncing, 1)
+	_, err = client.SupplyInfo(true)
+	if !errors.Is(err, ErrCoinStatsIndexUnavailable) {
+		t.Errorf("SupplyInfo while syncing: got %v, want %v", err,
+			ErrCoinStatsIndexUnavailable)
+	}
+	if _, ok := errors.Unwrap(err).(*sebtcjson.RPCError); !ok {
+		t.Errorf("SupplyInfo while syncing: error %v does not unwrap "+
+			"to the server error", err)
+	}
+}
+
+// TestGetMedianTimePast ensures the median time past is computed from the
+// timestamps of the block and its ten predecessors, and from fewer blocks near
+// genesis.
+func TestGetMedianTimePast(t *testing.T) {
+	t.Parallel()
+
+	// Build a chain of 15 headers with timestamps which are not in order.
+	offsets := []int64{0, 600, 300, 1200, 900, 1500, 2400, 1800, 2100,
+		3000, 2700, 3300, 3900, 3600, 4200}
+	base := time.Unix(1600000000, 0)
+	var hashes []chainhash.Hash
+	headers := make(map[string]string)
+	var prev chainhash.Hash
+	for i, offset := range offsets {
+		header := wire.BlockHeader{
+			Version:   1,
+			PrevBlock: prev,
+			Timestamp: base.Add(time.Duration(offset) * time.Second),
+			Nonce:     uint32(i),
+		}
+		var buf bytes.Buffer
+		if err := header.Serialize(&buf); err != nil {
+			t.Fatalf("Serialize: unexpected error: %v", err)
+		}
+		prev = header.BlockHash()
+		hashes = append(hashes, prev)
+		headers[prev.String()] = hex.EncodeToString(buf.Bytes())
+	}
+
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		if method != "getblockheader" {
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		}
+		var hash string
+		if err := json.Unmarshal(params[0], &hash); err != nil {
+			return nil, sebtcjson.NewRPCError(sebtcjson.ErrRPCInvalidParameter,
+				"invalid hash")
+		}
+		header, ok := headers[hash]
+		if !ok {
+			return nil, sebtcjson.NewRPCError(sebtcjson.ErrRPCBlockNotFound,
+				"Block not found")
+		}
+		return header, nil
+	})
+	defer closeTestClient(client, server)
+
+	tests := []struct {
+		height int
+		calls  int
+		want   int64
+	}{
+		// Blocks 4 through 14, whose sorted offsets put 2700 in the
+		// middle.
+		{height: 14, calls: 11, want: 2700},
+		// Blocks 0 through 10 of the chain.
+		{height: 10, calls: 11, want: 1500},
+		// Only blocks 0 through 2 exist.
+		{height: 2, calls: 3, want: 300},
+		{height: 0, calls: 1, want: 0},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	calls := 0
+	for i, test := range tests {
+		mtp, err := client.GetMedianTimePast(&hashes[test.height])
+		if err != nil {
+			t.Errorf("Test #%d unexpected error: %v", i, err)
+			continue
+		}
+		want := base.Add(time.Duration(test.want) * time.Second)
+		if !mtp.Equal(want) {
+			t.Errorf("Test #%d unexpected median time past - got %v, "+
+				"want %v", i, mtp, want)
+		}
+		n := server.calls("getblockheader")
+		if n-calls != test.calls {
+			t.Errorf("Test #%d unexpected number of headers fetched "+
+				"- got %d, want %d", i, n-calls, test.calls)
+		}
+		calls = n
+	}
+
+	if _, err := client.GetMedianTimePast(&chainhash.Hash{0x01}); err == nil {
+		t.Errorf("expected error for unknown block")
+	}
+}
+
+// TestScanTxOutSet ensures several descriptors are scanned for in a single
+// request and that the results of the start, status and abort actions are
+// decoded.
+func TestScanTxOutSet(t *testing.T) {
+	t.Parallel()
+
+	want := &sebtcjson.ScanTxOutSetResult{
+		Success:   true,
+		TxOuts:    9000,
+		Height:    200,
+		BestBlock: chainhash.Hash{0xaa}.String(),
+		Unspents: []sebtcjson.ScanTxOutSetUnspent{
+			{
+				TxID:         chainhash.Hash{0x01}.String(),
+				Vout:         1,
+				ScriptPubKey: "76a914389ffce9cd9ae88dcc0631e88a821ffdbe9bfe2688ac",
+				Descriptor:   "addr(mkmZxiEcEd8ZqjQWVZuC6so5dFMKEFpN2j)#02wpgw69",
+				Amount:       0.5,
+				Height:       150,
+			},
+			{
+				TxID:         chainhash.Hash{0x02}.String(),
+				ScriptPubKey: "0014751e76e8199196d454941c45d1b3a323f1433bd6",
+				Amount:       1.25,
+				Height:       199,
+			},
+		},
+		TotalAmount: 1.75,
+	}
+	var mtx sync.Mutex
+	statusCalls := 0
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		if method != "scantxoutset" {
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		}
+		var action string
+		if err := json.Unmarshal(params[0], &action); err != nil {
+			return nil, sebtcjson.NewRPCError(sebtcjson.ErrRPCInvalidParameter, "invalid action")
+		}
+		switch action {
+		case "start":
+			return want, nil
+
+		case "status":
+			mtx.Lock()
+			defer mtx.Unlock()
+			statusCalls++
+			if statusCalls == 1 {
+				return json.RawMessage(`{"progress":42}`), nil
+			}
+			return json.RawMessage(`null`), nil
+
+		case "abort":
+			return false, nil
+		}
+		return nil, sebtcjson.NewRPCError(sebtcjson.ErrRPCInvalidParameter, "invalid action")
+	})
+	defer closeTestClient(client, server)
+
+	result, err := client.ScanTxOutSet("start", []sebtcjson.ScanObject{
+		{Descriptor: "addr(mkmZxiEcEd8ZqjQWVZuC6so5dFMKEFpN2j)#02wpgw69"},
+		{Descriptor: "wpkh(tpub/0/*)", Range: &sebtcjson.DescriptorRange{End: 999}},
+	})
+	if err != nil {
+		t.Fatalf("ScanTxOutSet start: unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("ScanTxOutSet start: unexpected result - got %+v, want %+v",
+			result, want)
+	}
+	params := marshalParams(t, server.lastRequest("scantxoutset"))
+	wantParams := `["start",["addr(mkmZxiEcEd8ZqjQWVZuC6so5dFMKEFpN2j)#02wpgw69",` +
+		`{"desc":"wpkh(tpub/0/*)","range":999}]]`
+	if params != wantParams {
+		t.Errorf("unexpected params - got %s, want %s", params, wantParams)
+	}
+
+	// The status action reports the progress of the running scan and nil
+	// once none is in progress.
+	result, err = client.ScanTxOutSet("status", nil)
+	if err != nil {
+		t.Fatalf("ScanTxOutSet status: unexpected error: %v", err)
+	}
+	if want := (&sebtcjson.ScanTxOutSetResult{Progress: 42}); !reflect.DeepEqual(result, want) {
+		t.Errorf("ScanTxOutSet status: unexpected result - got %+v, "+
+			"want %+v", result, want)
+	}
+	params = marshalParams(t, server.lastRequest("scantxoutset"))
+	if want := `["status"]`; params != want {
+		t.Errorf("unexpected params - got %s, want %s", params, want)
+	}
+	result, err = client.ScanTxOutSet("status", nil)
+	if err != nil || result != nil {
+		t.Errorf("ScanTxOutSet status: unexpected result - got %+v, %v, "+
+			"want nil", result, err)
+	}
+
+	result, err = client.ScanTxOutSet("abort", nil)
+	if err != nil {
+		t.Fatalf("ScanTxOutSet abort: unexpected error: %v", err)
+	}
+	if result.Success {
+		t.Errorf("ScanTxOutSet abort: unexpected success without a " +
+			"running scan")
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2018 The box developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package serpcclient
+
+import (
+	"time"
+)
+
+// ClientOption overrides a setting of the connection configuration of a client
+// returned by Clone.
+type ClientOption func(config *ConnConfig)
+
+// WithTimeout overrides the Timeout field of the connection configuration.  In
+// websocket mode the timeout of the connection is kept, as it is shared.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(config *ConnConfig) {
+		config.Timeout = timeout
+	}
+}
+
+// WithRequestTimeout overrides the RequestTimeout field of the connection
+// configuration, which bounds every request of the clone.
+func WithRequestTimeout(timeout time.Duration) ClientOption {
+	return func(config *ConnConfig) {
+		config.RequestTimeout = timeout
+	}
+}
+
+// WithWalletName overrides the WalletName field of the connection
+// configuration, directing the requests of the clone to another wallet.
+func WithWalletName(name string) ClientOption {
+	return func(config *ConnConfig) {
+		config.WalletName = name
+	}
+}
+
+// WithDefaultAddressType overrides the DefaultAddressType field of the
+// connection configuration.
+func WithDefaultAddressType(addressType string) ClientOption {
+	return func(config *ConnConfig) {
+		config.DefaultAddressType = addressType
+	}
+}
+
+// Clone returns a client which issues its requests over the connection of the
+// client it is called on, using a copy of its connection configuration with
+// the passed options applied.  The settings of the original client are not
+// affected.
+//
+// The connection remains owned by the original client: shutting down a clone
+// has no effect, while shutting down the original client also stops its
+// clones.
+func (c *Client) Clone(opts ...ClientOption) *Client {
+	config := *c.config
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	root := c
+	if c.root != nil {
+		root = c.root
+	}
+
+	// Requests in HTTP POST mode are performed with the HTTP client of
+	// the client issuing them, sharing the transport of the original one.
+	httpClient := c.httpClient
+	if httpClient != nil && httpClient.Timeout != config.Timeout {
+		hc := *httpClient
+		hc.Timeout = config.Timeout
+		httpClient = &hc
+	}
+
+	return &Client{
+		config:          &config,
+		httpClient:      httpClient,
+		ntfnHandlers:    c.ntfnHandlers,
+		sendPostChan:    c.sendPostChan,
+		shutdown:        c.shutdown,
+		blockCache:      c.blockCache,
+		blockStatsCache: c.blockStatsCache,
+		prune:           c.prune,
+		cookie:          c.cookie,
+		root:            root,
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2018 The box developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package serpcclient
+
+import (
+	"encoding/json"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil"
+	"github.com/zzpu/lib-bitcore/sebtcjson"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestClone ensures the overrides of a clone apply to its own requests only and
+// that shutting down a clone leaves the shared connection usable.
+func TestClone(t *testing.T) {
+	t.Parallel()
+
+	addr, err := btcutil.NewAddressPubKeyHash(make([]byte, 20),
+		&chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("NewAddressPubKeyHash: unexpected error: %v", err)
+	}
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		switch method {
+		case "getblockcount":
+			return 1, nil
+		case "getnewaddress":
+			return addr.EncodeAddress(), nil
+		}
+		return nil, sebtcjson.ErrRPCMethodNotFound
+	})
+	defer closeTestClient(client, server)
+
+	clone := client.Clone(WithWalletName("cold"),
+		WithDefaultAddressType("bech32"), WithTimeout(time.Minute))
+	if client.config.WalletName != "" || client.config.DefaultAddressType != "" ||
+		client.config.Timeout != 0 {
+
+		t.Fatalf("Clone modified the original configuration: %+v",
+			client.config)
+	}
+	if clone.httpClient.Timeout != time.Minute || client.httpClient.Timeout != 0 {
+		t.Errorf("unexpected timeouts - got clone %v, original %v",
+			clone.httpClient.Timeout, client.httpClient.Timeout)
+	}
+	if clone.httpClient.Transport != client.httpClient.Transport {
+		t.Errorf("clone does not share the HTTP transport")
+	}
+
+	if _, err := clone.GetNewAddress(""); err != nil {
+		t.Fatalf("GetNewAddress: unexpected error: %v", err)
+	}
+	if got, want := marshalParams(t, server.lastRequest("getnewaddress")), `["","bech32"]`; got != want {
+		t.Errorf("unexpected getnewaddress params - got %s, want %s",
+			got, want)
+	}
+	if _, err := client.GetNewAddress(""); err != nil {
+		t.Fatalf("GetNewAddress: unexpected error: %v", err)
+	}
+	if got, want := marshalParams(t, server.lastRequest("getnewaddress")), `[""]`; got != want {
+		t.Errorf("unexpected getnewaddress params - got %s, want %s",
+			got, want)
+	}
+
+	// Shutting down the clone must not close the shared connection.
+	clone.Shutdown()
+	clone.WaitForShutdown()
+	if _, err := client.GetBlockCount(); err != nil {
+		t.Fatalf("GetBlockCount after clone shutdown: unexpected "+
+			"error: %v", err)
+	}
+
+	server.mtx.Lock()
+	paths := server.paths
+	server.mtx.Unlock()
+	want := []string{"/wallet/cold", "/", "/"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("unexpected request paths - got %q, want %q", paths,
+			want)
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright (c) 2018 The box developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package serpcclient
+
+import (
+	"github.com/btcsuite/btcutil"
+	"github.com/zzpu/lib-bitcore/sebtcjson"
+	"testing"
+)
+
+// TestSelectCoins ensures each coin selection strategy picks the expected
+// outputs and that targets which can not be covered are rejected.
+func TestSelectCoins(t *testing.T) {
+	t.Parallel()
+
+	utxo := func(txid string, amount float64, spendable bool) sebtcjson.ListUnspentResult {
+		return sebtcjson.ListUnspentResult{
+			TxID:      txid,
+			Amount:    amount,
+			Spendable: spendable,
+		}
+	}
+	utxos := []sebtcjson.ListUnspentResult{
+		utxo("a", 0.5, true),
+		utxo("b", 0.3, true),
+		utxo("c", 0.2, true),
+		utxo("d", 1.0, false),
+		utxo("e", 0.15, true),
+	}
+
+	tests := []struct {
+		name     string
+		target   btcutil.Amount
+		strategy CoinSelectStrategy
+		want     []string
+		total    btcutil.Amount
+		err      error
+	}{
+		{
+			name:     "largest first",
+			target:   btcutil.Amount(60000000),
+			strategy: CoinSelectLargestFirst,
+			want:     []string{"a", "b"},
+			total:    btcutil.Amount(80000000),
+		},
+		{
+			name:     "branch and bound exact match",
+			target:   btcutil.Amount(65000000),
+			strategy: CoinSelectBranchAndBound,
+			want:     []string{"a", "e"},
+			total:    btcutil.Amount(65000000),
+		},
+		{
+			name:     "branch and bound least excess",
+			target:   btcutil.Amount(34000000),
+			strategy: CoinSelectBranchAndBound,
+			want:     []string{"c", "e"},
+			total:    btcutil.Amount(35000000),
+		},
+		{
+			name:     "unspendable outputs ignored",
+			target:   btcutil.Amount(120000000),
+			strategy: CoinSelectLargestFirst,
+			err:      ErrInsufficientFunds,
+		},
+		{
+			name:     "insufficient funds",
+			target:   btcutil.Amount(116000000),
+			strategy: CoinSelectBranchAndBound,
+			err:      ErrInsufficientFunds,
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		selected, total, err := SelectCoins(utxos, test.target,
+			test.strategy)
+		if err != test.err {
+			t.Errorf("Test #%d (%s) unexpected error - got %v, want %v",
+				i, test.name, err, test.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if total != test.total {
+			t.Errorf("Test #%d (%s) unexpected total - got %v, want %v",
+				i, test.name, total, test.total)
+		}
+		var got []string
+		for _, utxo := range selected {
+			got = append(got, utxo.TxID)
+		}
+		if len(got) != len(test.want) {
+			t.Errorf("Test #%d (%s) unexpected selection - got %v, "+
+				"want %v", i, test.name, got, test.want)
+			continue
+		}
+		for j := range got {
+			if got[j] != test.want[j] {
+				t.Errorf("Test #%d (%s) unexpected selection - got "+
+					"%v, want %v", i, test.name, got, test.want)
+				break
+			}
+		}
+	}
+}
+
+// TestSelectCoinsWithFee ensures the fee of each input is paid out of the
+// outputs selected, that outputs costing more to spend than they are worth are
+// never selected, and that the change is reported.
+func TestSelectCoinsWithFee(t *testing.T) {
+	t.Parallel()
+
+	const (
+		p2wpkh = "0014" + "0000000000000000000000000000000000000000"
+		p2pkh  = "76a914" + "0000000000000000000000000000000000000000" + "88ac"
+	)
+	utxo := func(txid string, amount float64, script string) sebtcjson.ListUnspentResult {
+		return sebtcjson.ListUnspentResult{
+			TxID:         txid,
+			Amount:       amount,
+			ScriptPubKey: script,
+			Spendable:    true,
+		}
+	}
+
+	// At 10 satoshi per virtual byte, a is worth 99320 satoshi, b 48520
+	// and c less than nothing.
+	utxos := []sebtcjson.ListUnspentResult{
+		utxo("a", 0.001, p2wpkh),
+		utxo("b", 0.0005, p2pkh),
+		utxo("c", 0.00001, p2pkh),
+	}
+
+	tests := []struct {
+		name     string
+		target   btcutil.Amount
+		strategy CoinSelectStrategy
+		want     []string
+		change   btcutil.Amount
+		err      error
+	}{
+		{
+			name:     "exact match without change",
+			target:   btcutil.Amount(147840),
+			strategy: CoinSelectBranchAndBound,
+			want:     []string{"a", "b"},
+			change:   0,
+		},
+		{
+			name:     "largest first with change",
+			target:   btcutil.Amount(100000),
+			strategy: CoinSelectLargestFirst,
+			want:     []string{"a", "b"},
+			change:   btcutil.Amount(47840),
+		},
+		{
+			name:     "single input with change",
+			target:   btcutil.Amount(90000),
+			strategy: CoinSelectBranchAndBound,
+			want:     []string{"a"},
+			change:   btcutil.Amount(9320),
+		},
+		{
+			name:     "insufficient funds after fees",
+			target:   btcutil.Amount(147841),
+			strategy: CoinSelectBranchAndBound,
+			err:      ErrInsufficientFunds,
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		selected, change, err := SelectCoinsWithFee(utxos, test.target,
+			10, test.strategy)
+		if err != test.err {
+			t.Errorf("Test #%d (%s) unexpected error - got %v, want %v",
+				i, test.name, err, test.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if change != test.change {
+			t.Errorf("Test #%d (%s) unexpected change - got %v, want %v",
+				i, test.name, change, test.change)
+		}
+		var got []string
+		for _, utxo := range selected {
+			got = append(got, utxo.TxID)
+		}
+		if len(got) != len(test.want) {
+			t.Errorf("Test #%d (%s) unexpected selection - got %v, "+
+				"want %v", i, test.name, got, test.want)
+			continue
+		}
+		for j := range got {
+			if got[j] != test.want[j] {
+				t.Errorf("Test #%d (%s) unexpected selection - got "+
+					"%v, want %v", i, test.name, got, test.want)
+				break
+			}
+		}
+	}
+}
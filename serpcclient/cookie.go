@@ -0,0 +1,87 @@
+// Copyright (c) 2018 The box developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package serpcclient
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+)
+
+// readCookieFile returns the username and password stored in the cookie file
+// at the passed path, which bitcoind writes as a single user:password line.
+func readCookieFile(path string) (string, string, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", "", fmt.Errorf("unable to read cookie file: %v", err)
+	}
+	parts := strings.SplitN(strings.TrimSpace(string(contents)), ":", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("cookie file %s is not of the form "+
+			"user:password", path)
+	}
+	return parts[0], parts[1], nil
+}
+
+// cookieAuth caches the credentials read from a cookie file until the server
+// rejects them, which happens once it restarted and wrote a new cookie.
+//
+// The cache is safe for concurrent access.  Invalidating a nil cache has no
+// effect.
+type cookieAuth struct {
+	path string
+
+	mtx    sync.Mutex
+	user   string
+	pass   string
+	loaded bool
+}
+
+// newCookieAuth returns a cache for the cookie file at the passed path, or nil
+// when the path is empty.
+func newCookieAuth(path string) *cookieAuth {
+	if path == "" {
+		return nil
+	}
+	return &cookieAuth{path: path}
+}
+
+// credentials returns the username and password of the cookie file, reading
+// the file when they are not cached.
+func (a *cookieAuth) credentials() (string, string, error) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if !a.loaded {
+		user, pass, err := readCookieFile(a.path)
+		if err != nil {
+			return "", "", err
+		}
+		a.user, a.pass, a.loaded = user, pass, true
+	}
+	return a.user, a.pass, nil
+}
+
+// invalidate forces the cookie file to be read again on next use.
+func (a *cookieAuth) invalidate() {
+	if a == nil {
+		return
+	}
+
+	a.mtx.Lock()
+	a.loaded = false
+	a.mtx.Unlock()
+}
+
+// credentials returns the username and password to authenticate to the RPC
+// server with, which are read from the cookie file when one is configured and
+// taken from the User and Pass fields otherwise.
+func (c *Client) credentials() (string, string, error) {
+	if c.cookie == nil {
+		return c.config.User, c.config.Pass, nil
+	}
+	return c.cookie.credentials()
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2018 The box developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package serpcclient
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"github.com/btcsuite/websocket"
+	"github.com/zzpu/lib-bitcore/sebtcjson"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// basicAuth returns the Authorization header value for the passed login.
+func basicAuth(login string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(login))
+}
+
+// TestCookieAuth ensures requests in HTTP POST mode authenticate with the
+// credentials of the cookie file rather than User and Pass, and that the file
+// is read again once the server rejects a rotated cookie.
+func TestCookieAuth(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "cookie")
+	if err != nil {
+		t.Fatalf("TempDir: unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, ".cookie")
+	if err := ioutil.WriteFile(path, []byte("__cookie__:first\n"), 0600); err != nil {
+		t.Fatalf("WriteFile: unexpected error: %v", err)
+	}
+
+	var mtx sync.Mutex
+	want := basicAuth("__cookie__:first")
+	var headers []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mtx.Lock()
+		auth := r.Header.Get("Authorization")
+		headers = append(headers, auth)
+		ok := auth == want
+		mtx.Unlock()
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var req sebtcjson.Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		reply, err := sebtcjson.MarshalResponse(req.ID, 100, nil)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(reply)
+	}))
+	defer server.Close()
+
+	client, err := New(&ConnConfig{
+		Host:         strings.TrimPrefix(server.URL, "http://"),
+		User:         "user",
+		Pass:         "pass",
+		CookiePath:   path,
+		HTTPPostMode: true,
+		DisableTLS:   true,
+	}, nil)
+	if err != nil {
+		t.Fatalf("New: unexpected error: %v", err)
+	}
+	defer func() {
+		client.Shutdown()
+		client.WaitForShutdown()
+	}()
+
+	if _, err := client.GetBlockCount(); err != nil {
+		t.Fatalf("GetBlockCount: unexpected error: %v", err)
+	}
+
+	// The server restarts with a new cookie.
+	if err := ioutil.WriteFile(path, []byte("__cookie__:second"), 0600); err != nil {
+		t.Fatalf("WriteFile: unexpected error: %v", err)
+	}
+	mtx.Lock()
+	want = basicAuth("__cookie__:second")
+	mtx.Unlock()
+	if _, err := client.GetBlockCount(); err != nil {
+		t.Fatalf("GetBlockCount after rotation: unexpected error: %v", err)
+	}
+
+	mtx.Lock()
+	defer mtx.Unlock()
+	wantHeaders := []string{
+		basicAuth("__cookie__:first"),
+		basicAuth("__cookie__:first"),
+		basicAuth("__cookie__:second"),
+	}
+	if strings.Join(headers, ",") != strings.Join(wantHeaders, ",") {
+		t.Errorf("unexpected Authorization headers - got %q, want %q",
+			headers, wantHeaders)
+	}
+}
+
+// TestCookieAuthWebsocket ensures the websocket handshake authenticates with
+// the credentials of the cookie file.
+func TestCookieAuthWebsocket(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "cookie")
+	if err != nil {
+		t.Fatalf("TempDir: unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, ".cookie")
+	if err := ioutil.WriteFile(path, []byte("__cookie__:secret"), 0600); err != nil {
+		t.Fatalf("WriteFile: unexpected error: %v", err)
+	}
+
+	auth := make(chan string, 1)
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth <- r.Header.Get("Authorization")
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	client, err := New(&ConnConfig{
+		Host:       strings.TrimPrefix(server.URL, "http://"),
+		Endpoint:   "ws",
+		CookiePath: path,
+		DisableTLS: true,
+	}, nil)
+	if err != nil {
+		t.Fatalf("New: unexpected error: %v", err)
+	}
+	client.Shutdown()
+	client.WaitForShutdown()
+
+	if got, want := <-auth, basicAuth("__cookie__:secret"); got != want {
+		t.Errorf("unexpected Authorization header - got %q, want %q",
+			got, want)
+	}
+
+	// A missing cookie file fails the dial.
+	_, err = New(&ConnConfig{
+		Host:       strings.TrimPrefix(server.URL, "http://"),
+		Endpoint:   "ws",
+		CookiePath: filepath.Join(dir, "missing"),
+		DisableTLS: true,
+	}, nil)
+	if err == nil {
+		t.Errorf("New: expected error for missing cookie file")
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2018 The box developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package serpcclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/zzpu/lib-bitcore/sebtcjson"
+	"strings"
+)
+
+const (
+	// descriptorInputCharset is the set of characters which may appear in
+	// an output descriptor, ordered as required by the checksum algorithm.
+	descriptorInputCharset = "0123456789()[],'/*abcdefgh@:$%{}" +
+		"IJKLMNOPQRSTUVWXYZ&+-.;<=>?!^_|~" +
+		"ijklmnopqrstuvwxyzABCDEFGH`#\"\\ "
+
+	// descriptorChecksumCharset is the set of characters the checksum of
+	// an output descriptor is encoded with.
+	descriptorChecksumCharset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+	// descriptorChecksumLen is the number of characters in the checksum of
+	// an output descriptor.
+	descriptorChecksumLen = 8
+)
+
+// descriptorPolyMod updates the checksum state c with the passed symbol of
+// the descriptor as specified by BIP 380.
+func descriptorPolyMod(c uint64, symbol int) uint64 {
+	top := c >> 35
+	c = (c&0x7ffffffff)<<5 ^ uint64(symbol)
+	if top&1 != 0 {
+		c ^= 0xf5dee51989
+	}
+	if top&2 != 0 {
+		c ^= 0xa9fdca3312
+	}
+	if top&4 != 0 {
+		c ^= 0x1bab10e32d
+	}
+	if top&8 != 0 {
+		c ^= 0x3706b1677a
+	}
+	if top&16 != 0 {
+		c ^= 0x644d626ffd
+	}
+	return c
+}
+
+// DescriptorChecksum returns the checksum of the passed output descriptor,
+// which must not already include one, as specified by BIP 380.  This is the
+// checksum getdescriptorinfo reports, computed without a round trip to the
+// server.
+func DescriptorChecksum(desc string) (string, error) {
+	c := uint64(1)
+	class, classCount := 0, 0
+	for i := 0; i < len(desc); i++ {
+		pos := strings.IndexByte(descriptorInputCharset, desc[i])
+		if pos < 0 {
+			return "", fmt.Errorf("invalid character %q in descriptor "+
+				"at position %d", desc[i], i)
+		}
+
+		// Each character contributes its position within its group of
+		// 32, and the groups of every three characters are combined
+		// into one extra symbol.
+		c = descriptorPolyMod(c, pos&31)
+		class = class*3 + pos>>5
+		classCount++
+		if classCount == 3 {
+			c = descriptorPolyMod(c, class)
+			class, classCount = 0, 0
+		}
+	}
+	if classCount > 0 {
+		c = descriptorPolyMod(c, class)
+	}
+	for i := 0; i < descriptorChecksumLen; i++ {
+		c = descriptorPolyMod(c, 0)
+	}
+	c ^= 1
+
+	var checksum [descriptorChecksumLen]byte
+	for i := range checksum {
+		shift := 5 * uint(descriptorChecksumLen-1-i)
+		checksum[i] = descriptorChecksumCharset[(c>>shift)&31]
+	}
+	return string(checksum[:]), nil
+}
+
+// AddDescriptorChecksum returns the passed output descriptor with its checksum
+// appended.  When the descriptor already includes a checksum, it is validated
+// and the descriptor is returned unchanged.
+func AddDescriptorChecksum(desc string) (string, error) {
+	body, checksum := desc, ""
+	if i := strings.LastIndexByte(desc, '#'); i >= 0 {
+		body, checksum = desc[:i], desc[i+1:]
+	}
+
+	want, err := DescriptorChecksum(body)
+	if err != nil {
+		return "", err
+	}
+	if checksum == "" && !strings.HasSuffix(desc, "#") {
+		return body + "#" + want, nil
+	}
+	if checksum != want {
+		return "", fmt.Errorf("invalid checksum %q for descriptor %s, "+
+			"expected %q", checksum, body, want)
+	}
+	return desc, nil
+}
+
+// FutureGetDescriptorInfoResult is a future promise to deliver the result of a
+// GetDescriptorInfoAsync RPC invocation (or an applicable error).
+type FutureGetDescriptorInfoResult chan *response
+
+// Receive waits for the response promised by the future and returns the
+// analysis of the output descriptor.
+func (r FutureGetDescriptorInfoResult) Receive() (*sebtcjson.GetDescriptorInfoResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getdescriptorinfo result object.
+	var info sebtcjson.GetDescriptorInfoResult
+	err = json.Unmarshal(res, &info)
+	if err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
+
+// GetDescriptorInfoAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GetDescriptorInfo for the blocking version and more details.
+func (c *Client) GetDescriptorInfoAsync(descriptor string) FutureGetDescriptorInfoResult {
+	cmd := sebtcjson.NewGetDescriptorInfoCmd(descriptor)
+	return c.sendCmd(cmd)
+}
+
+// GetDescriptorInfo returns the analysis of the passed output descriptor, such
+// as its canonical form, its checksum and whether it is ranged.
+func (c *Client) GetDescriptorInfo(descriptor string) (*sebtcjson.GetDescriptorInfoResult, error) {
+	return c.GetDescriptorInfoAsync(descriptor).Receive()
+}
+
+// FutureDeriveAddressesResult is a future promise to deliver the result of a
+// DeriveAddressesAsync RPC invocation (or an applicable error).
+type FutureDeriveAddressesResult chan *response
+
+// Receive waits for the response promised by the future and returns the
+// addresses derived from the output descriptor.
+func (r FutureDeriveAddressesResult) Receive() ([]string, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as an array of strings.
+	var addresses []string
+	err = json.Unmarshal(res, &addresses)
+	if err != nil {
+		return nil, err
+	}
+
+	return addresses, nil
+}
+
+// DeriveAddressesAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See DeriveAddresses for the blocking version and more details.
+func (c *Client) DeriveAddressesAsync(descriptor string, rangeVals *sebtcjson.DescriptorRange) FutureDeriveAddressesResult {
+	cmd := sebtcjson.NewDeriveAddressesCmd(descriptor, rangeVals)
+	return c.sendCmd(cmd)
+}
+
+// DeriveAddresses returns the addresses the passed output descriptor derives,
+// which must include its checksum.  The range of child indexes is required for
+// ranged descriptors and must be nil otherwise.
+func (c *Client) DeriveAddresses(descriptor string, rangeVals *sebtcjson.DescriptorRange) ([]string, error) {
+	return c.DeriveAddressesAsync(descriptor, rangeVals).Receive()
+}
@@ -58,6 +58,13 @@ the Receive method on the returned instance will either return the result
 immediately if it has already arrived, or block until it has.  This is useful
 since it provides the caller with greater control over concurrency.
 
+Some commonly used commands, such as GetBlockCtx, also have a variant which
+takes a context.  It blocks like the synchronous API, but returns the error of
+the context as soon as the context is done, without waiting for the server.  A
+late response to an abandoned request is discarded.  Callers which do not pass
+contexts may bound every request with the RequestTimeout field of the
+connection configuration instead, after which ErrRequestTimeout is returned.
+
 Notifications
 
 The first important part of notifications is to realize that they will only
@@ -89,10 +96,14 @@ Automatic Reconnection
 By default, when running in websockets mode, this client will automatically
 keep trying to reconnect to the RPC server should the connection be lost.  There
 is a back-off in between each connection attempt until it reaches one try per
-minute.  Once a connection is re-established, all previously registered
+minute, which may be tuned with the ConnectRetryBackoff fields of the connection
+configuration.  Once a connection is re-established, all previously registered
 notifications are automatically re-registered and any in-flight commands are
 re-issued.  This means from the caller's perspective, the request simply takes
-longer to complete.
+longer to complete.  The OnReconnect notification handler is invoked afterwards.
+The client gives up and shuts down after the number of failed attempts set by
+the MaxReconnectAttempts field of the connection configuration, failing the
+outstanding commands with ErrReconnectAttemptsExceeded.
 
 The caller may invoke the Shutdown method on the client to force the client
 to cease reconnect attempts and return ErrClientShutdown for all outstanding
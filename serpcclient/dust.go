@@ -0,0 +1,115 @@
+// Copyright (c) 2018 The box developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package serpcclient
+
+import (
+	"github.com/btcsuite/btcutil"
+)
+
+// AddressType identifies the type of output an address pays to.
+type AddressType int
+
+const (
+	// AddressP2PKH identifies pay-to-pubkey-hash outputs.
+	AddressP2PKH AddressType = iota
+
+	// AddressP2SH identifies pay-to-script-hash outputs.
+	AddressP2SH
+
+	// AddressP2WPKH identifies pay-to-witness-pubkey-hash outputs.
+	AddressP2WPKH
+
+	// AddressP2WSH identifies pay-to-witness-script-hash outputs.
+	AddressP2WSH
+
+	// AddressP2TR identifies pay-to-taproot outputs.
+	AddressP2TR
+)
+
+// String returns the AddressType in human-readable form.
+func (t AddressType) String() string {
+	switch t {
+	case AddressP2PKH:
+		return "p2pkh"
+	case AddressP2SH:
+		return "p2sh"
+	case AddressP2WPKH:
+		return "p2wpkh"
+	case AddressP2WSH:
+		return "p2wsh"
+	case AddressP2TR:
+		return "p2tr"
+	}
+	return "unknown"
+}
+
+const (
+	// dustRelayFeeMultiplier is the factor the relay fee is multiplied with
+	// to obtain the fee rate outputs are considered dust at.  It matches
+	// the default dust relay fee of Bitcoin Core, which is three times its
+	// default relay fee.
+	dustRelayFeeMultiplier = 3
+
+	// spendInputSize is the size of an input spending a non-witness
+	// output as assumed by Bitcoin Core when computing the dust limit.
+	spendInputSize = 32 + 4 + 1 + 107 + 4
+
+	// spendWitnessInputSize is the virtual size of an input spending a
+	// witness output as assumed by Bitcoin Core when computing the dust
+	// limit.
+	spendWitnessInputSize = 32 + 4 + 1 + 107/4 + 4
+)
+
+// outputSize returns the serialized size of an output of the passed type and
+// whether it is a witness output.
+func (t AddressType) outputSize() (int64, bool) {
+	switch t {
+	case AddressP2PKH:
+		return 8 + 1 + 25, false
+	case AddressP2SH:
+		return 8 + 1 + 23, false
+	case AddressP2WPKH:
+		return 8 + 1 + 22, true
+	case AddressP2WSH, AddressP2TR:
+		return 8 + 1 + 34, true
+	}
+
+	// Unknown types are treated like the largest standard output.
+	return 8 + 1 + 25, false
+}
+
+// DustThreshold returns the smallest amount an output of the passed type may
+// carry without being rejected as dust by a server relaying transactions at
+// the passed fee rate, in satoshi per kilobyte.  Like Bitcoin Core, the limit
+// is the fee needed to both create and later spend the output at the dust
+// relay fee rate, which is three times the relay fee.
+//
+// At the default relay fee of 1000 satoshi per kilobyte, this is 546 satoshi
+// for P2PKH, 540 for P2SH, 294 for P2WPKH and 330 for P2WSH and P2TR outputs.
+func DustThreshold(addrType AddressType, relayFeePerKb btcutil.Amount) btcutil.Amount {
+	size, witness := addrType.outputSize()
+	if witness {
+		size += spendWitnessInputSize
+	} else {
+		size += spendInputSize
+	}
+	return btcutil.Amount(size * int64(relayFeePerKb) *
+		dustRelayFeeMultiplier / 1000)
+}
+
+// MinimumSendableAmount returns the smallest amount which may be sent to an
+// address of the passed type without the output being rejected as dust, based
+// on the relay fee reported by getnetworkinfo.  See DustThreshold for details.
+func (c *Client) MinimumSendableAmount(addrType AddressType) (btcutil.Amount, error) {
+	info, err := c.GetNetworkInfo()
+	if err != nil {
+		return 0, err
+	}
+	relayFee, err := btcutil.NewAmount(info.RelayFee)
+	if err != nil {
+		return 0, err
+	}
+	return DustThreshold(addrType, relayFee), nil
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2018 The box developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package serpcclient
+
+import (
+	"encoding/json"
+	"github.com/btcsuite/btcutil"
+	"github.com/zzpu/lib-bitcore/sebtcjson"
+	"testing"
+)
+
+// TestMinimumSendableAmount ensures the dust limit of each output type matches
+// the thresholds enforced by Bitcoin Core for the relay fee reported by
+// getnetworkinfo.
+func TestMinimumSendableAmount(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		relayFee float64
+		addrType AddressType
+		want     btcutil.Amount
+	}{
+		{"p2pkh default", 0.00001, AddressP2PKH, 546},
+		{"p2sh default", 0.00001, AddressP2SH, 540},
+		{"p2wpkh default", 0.00001, AddressP2WPKH, 294},
+		{"p2wsh default", 0.00001, AddressP2WSH, 330},
+		{"p2tr default", 0.00001, AddressP2TR, 330},
+		{"p2pkh doubled", 0.00002, AddressP2PKH, 1092},
+		{"p2wpkh doubled", 0.00002, AddressP2WPKH, 588},
+		{"p2wpkh zero", 0, AddressP2WPKH, 0},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		test := test
+		client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+			if method != "getnetworkinfo" {
+				return nil, sebtcjson.ErrRPCMethodNotFound
+			}
+			return map[string]interface{}{
+				"version":  220000,
+				"relayfee": test.relayFee,
+			}, nil
+		})
+
+		amount, err := client.MinimumSendableAmount(test.addrType)
+		closeTestClient(client, server)
+		if err != nil {
+			t.Errorf("Test #%d (%s) unexpected error: %v", i,
+				test.name, err)
+			continue
+		}
+		if amount != test.want {
+			t.Errorf("Test #%d (%s) unexpected amount - got %d, "+
+				"want %d", i, test.name, int64(amount),
+				int64(test.want))
+		}
+	}
+}
@@ -7,6 +7,7 @@ package serpcclient
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
@@ -193,6 +194,15 @@ func (c *Client) GetBestBlock() (*chainhash.Hash, int32, error) {
 	return c.GetBestBlockAsync().Receive()
 }
 
+// GetBestBlockCtx is like GetBestBlock, but returns the error of the passed
+// context once it is done before the server answers.
+//
+// NOTE: This is a btcd extension.
+func (c *Client) GetBestBlockCtx(ctx context.Context) (*chainhash.Hash, int32, error) {
+	cmd := sebtcjson.NewGetBestBlockCmd()
+	return FutureGetBestBlockResult(c.sendCmdCtx(ctx, cmd)).Receive()
+}
+
 // FutureGetCurrentNetResult is a future promise to deliver the result of a
 // GetCurrentNetAsync RPC invocation (or an applicable error).
 type FutureGetCurrentNetResult chan *response
@@ -421,6 +431,20 @@ func (c *Client) Session() (*sebtcjson.SessionResult, error) {
 	return c.SessionAsync().Receive()
 }
 
+// SessionCtx is like Session, but returns the error of the passed context once
+// it is done before the server answers.
+//
+// NOTE: This is a btcsuite extension.
+func (c *Client) SessionCtx(ctx context.Context) (*sebtcjson.SessionResult, error) {
+	// Not supported in HTTP POST mode.
+	if c.config.HTTPPostMode {
+		return nil, ErrWebsocketsRequired
+	}
+
+	cmd := sebtcjson.NewSessionCmd()
+	return FutureSessionResult(c.sendCmdCtx(ctx, cmd)).Receive()
+}
+
 // FutureVersionResult is a future promise to deliver the result of a version
 // RPC invocation (or an applicable error).
 //
@@ -470,3 +494,13 @@ func (c *Client) VersionAsync() FutureVersionResult {
 func (c *Client) Version() (map[string]sebtcjson.VersionResult, error) {
 	return c.VersionAsync().Receive()
 }
+
+// VersionCtx is like Version, but returns the error of the passed context once
+// it is done before the server answers.
+//
+// NOTE: This is a btcsuite extension ported from
+// github.com/decred/dcrrpcclient.
+func (c *Client) VersionCtx(ctx context.Context) (map[string]sebtcjson.VersionResult, error) {
+	cmd := sebtcjson.NewVersionCmd()
+	return FutureVersionResult(c.sendCmdCtx(ctx, cmd)).Receive()
+}
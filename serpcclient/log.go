@@ -30,6 +30,27 @@ func UseLogger(logger btclog.Logger) {
 	log = logger
 }
 
+// Logger is the interface used for the diagnostics of a single client, such as
+// reconnect events, retried requests, and dropped notifications.  It may be set
+// with the Logger field of ConnConfig to route those diagnostics into another
+// logging system.  A btclog.Logger satisfies it.
+type Logger interface {
+	Debugf(format string, params ...interface{})
+	Infof(format string, params ...interface{})
+	Warnf(format string, params ...interface{})
+	Errorf(format string, params ...interface{})
+}
+
+// logger returns the logger for the diagnostics of the client.  This is the
+// logger of the connection configuration when set, and the package logger,
+// which is disabled until UseLogger is called, otherwise.
+func (c *Client) logger() Logger {
+	if c.config.Logger != nil {
+		return c.config.Logger
+	}
+	return log
+}
+
 // LogClosure is a closure that can be printed with %v to be used to
 // generate expensive-to-create data for a detailed log level and avoid doing
 // the work if the data isn't printed.
@@ -0,0 +1,124 @@
+// Copyright (c) 2018 The box developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package serpcclient
+
+import (
+	"encoding/json"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/zzpu/lib-bitcore/sebtcjson"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+// TestMempoolDiff ensures the added and removed transactions between two
+// snapshots are reported in snapshot order and only once.
+func TestMempoolDiff(t *testing.T) {
+	t.Parallel()
+
+	a, b, c, d := &chainhash.Hash{0x0a}, &chainhash.Hash{0x0b},
+		&chainhash.Hash{0x0c}, &chainhash.Hash{0x0d}
+	tests := []struct {
+		name        string
+		prev, curr  []*chainhash.Hash
+		added, gone []*chainhash.Hash
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:  "initial",
+			curr:  []*chainhash.Hash{a, b},
+			added: []*chainhash.Hash{a, b},
+		},
+		{
+			name: "cleared",
+			prev: []*chainhash.Hash{a, b},
+			gone: []*chainhash.Hash{a, b},
+		},
+		{
+			name:  "changed",
+			prev:  []*chainhash.Hash{a, b, c},
+			curr:  []*chainhash.Hash{d, b},
+			added: []*chainhash.Hash{d},
+			gone:  []*chainhash.Hash{a, c},
+		},
+		{
+			name:  "duplicates",
+			prev:  []*chainhash.Hash{a, a, b},
+			curr:  []*chainhash.Hash{b, c, c},
+			added: []*chainhash.Hash{c},
+			gone:  []*chainhash.Hash{a},
+		},
+	}
+
+	for _, test := range tests {
+		added, removed := MempoolDiff(test.prev, test.curr)
+		if !reflect.DeepEqual(added, test.added) {
+			t.Errorf("%s: unexpected added - got %v, want %v",
+				test.name, added, test.added)
+		}
+		if !reflect.DeepEqual(removed, test.gone) {
+			t.Errorf("%s: unexpected removed - got %v, want %v",
+				test.name, removed, test.gone)
+		}
+	}
+}
+
+// TestMempoolTracker ensures each update reports the changes since the
+// previous successful snapshot and that failed updates keep the snapshot.
+func TestMempoolTracker(t *testing.T) {
+	t.Parallel()
+
+	a, b, c := chainhash.Hash{0x0a}, chainhash.Hash{0x0b}, chainhash.Hash{0x0c}
+	snapshots := [][]string{
+		{a.String(), b.String()},
+		nil,
+		{b.String(), c.String()},
+	}
+	var polls int32
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		if method != "getrawmempool" {
+			return nil, sebtcjson.ErrRPCMethodNotFound
+		}
+		n := int(atomic.AddInt32(&polls, 1)) - 1
+		if snapshots[n] == nil {
+			return nil, sebtcjson.NewRPCError(sebtcjson.ErrRPCMisc,
+				"unavailable")
+		}
+		return snapshots[n], nil
+	})
+	defer closeTestClient(client, server)
+
+	tracker := NewMempoolTracker(client)
+	added, removed, err := tracker.Update()
+	if err != nil {
+		t.Fatalf("Update: unexpected error: %v", err)
+	}
+	if len(added) != 2 || *added[0] != a || *added[1] != b ||
+		len(removed) != 0 {
+
+		t.Fatalf("unexpected first update - got added %v, removed %v",
+			added, removed)
+	}
+
+	if _, _, err := tracker.Update(); err == nil {
+		t.Fatalf("Update: expected error")
+	}
+	if snapshot := tracker.Snapshot(); len(snapshot) != 2 {
+		t.Fatalf("failed update replaced snapshot - got %v", snapshot)
+	}
+
+	added, removed, err = tracker.Update()
+	if err != nil {
+		t.Fatalf("Update: unexpected error: %v", err)
+	}
+	if len(added) != 1 || *added[0] != c ||
+		len(removed) != 1 || *removed[0] != a {
+
+		t.Fatalf("unexpected update - got added %v, removed %v",
+			added, removed)
+	}
+}
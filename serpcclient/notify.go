@@ -12,6 +12,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcutil"
 	"github.com/zzpu/lib-bitcore/sebtcjson"
@@ -89,6 +90,15 @@ type NotificationHandlers struct {
 	// notification handlers, and is safe for blocking client requests.
 	OnClientConnected func()
 
+	// OnReconnect is invoked after the client automatically reconnected
+	// to the RPC server, once the notifications registered through the
+	// client were registered again and the requests pending when the
+	// connection was lost were resent.  Subscriptions made outside of the
+	// client, such as with RawRequest, are lost across connections and
+	// may be made again from it.  Like OnClientConnected, it is safe for
+	// blocking client requests.
+	OnReconnect func()
+
 	// OnBlockConnected is invoked when a block is connected to the longest
 	// (best) chain.  It will only be invoked if a preceding call to
 	// NotifyBlocks has been made to register for the notification and the
@@ -210,6 +220,14 @@ type NotificationHandlers struct {
 // delivers the notification to the appropriate On<X> handler registered with
 // the client.
 func (c *Client) handleNotification(ntfn *rawNotification) {
+	// New blocks may cause a pruned server to discard old ones.
+	switch ntfn.Method {
+	case sebtcjson.BlockConnectedNtfnMethod,
+		sebtcjson.FilteredBlockConnectedNtfnMethod:
+
+		c.prune.invalidate()
+	}
+
 	// Ignore the notification if the client is not interested in any
 	// notifications.
 	if c.ntfnHandlers == nil {
@@ -227,7 +245,7 @@ func (c *Client) handleNotification(ntfn *rawNotification) {
 
 		blockHash, blockHeight, blockTime, err := parseChainNtfnParams(ntfn.Params)
 		if err != nil {
-			log.Warnf("Received invalid block connected "+
+			c.logger().Warnf("Received invalid block connected "+
 				"notification: %v", err)
 			return
 		}
@@ -245,7 +263,7 @@ func (c *Client) handleNotification(ntfn *rawNotification) {
 		blockHeight, blockHeader, transactions, err :=
 			parseFilteredBlockConnectedParams(ntfn.Params)
 		if err != nil {
-			log.Warnf("Received invalid filtered block "+
+			c.logger().Warnf("Received invalid filtered block "+
 				"connected notification: %v", err)
 			return
 		}
@@ -263,7 +281,7 @@ func (c *Client) handleNotification(ntfn *rawNotification) {
 
 		blockHash, blockHeight, blockTime, err := parseChainNtfnParams(ntfn.Params)
 		if err != nil {
-			log.Warnf("Received invalid block connected "+
+			c.logger().Warnf("Received invalid block connected "+
 				"notification: %v", err)
 			return
 		}
@@ -281,7 +299,7 @@ func (c *Client) handleNotification(ntfn *rawNotification) {
 		blockHeight, blockHeader, err :=
 			parseFilteredBlockDisconnectedParams(ntfn.Params)
 		if err != nil {
-			log.Warnf("Received invalid filtered block "+
+			c.logger().Warnf("Received invalid filtered block "+
 				"disconnected notification: %v", err)
 			return
 		}
@@ -299,7 +317,7 @@ func (c *Client) handleNotification(ntfn *rawNotification) {
 
 		tx, block, err := parseChainTxNtfnParams(ntfn.Params)
 		if err != nil {
-			log.Warnf("Received invalid recvtx notification: %v",
+			c.logger().Warnf("Received invalid recvtx notification: %v",
 				err)
 			return
 		}
@@ -316,7 +334,7 @@ func (c *Client) handleNotification(ntfn *rawNotification) {
 
 		tx, block, err := parseChainTxNtfnParams(ntfn.Params)
 		if err != nil {
-			log.Warnf("Received invalid redeemingtx "+
+			c.logger().Warnf("Received invalid redeemingtx "+
 				"notification: %v", err)
 			return
 		}
@@ -333,7 +351,7 @@ func (c *Client) handleNotification(ntfn *rawNotification) {
 
 		transaction, err := parseRelevantTxAcceptedParams(ntfn.Params)
 		if err != nil {
-			log.Warnf("Received invalid relevanttxaccepted "+
+			c.logger().Warnf("Received invalid relevanttxaccepted "+
 				"notification: %v", err)
 			return
 		}
@@ -350,7 +368,7 @@ func (c *Client) handleNotification(ntfn *rawNotification) {
 
 		hash, height, blkTime, err := parseRescanProgressParams(ntfn.Params)
 		if err != nil {
-			log.Warnf("Received invalid rescanfinished "+
+			c.logger().Warnf("Received invalid rescanfinished "+
 				"notification: %v", err)
 			return
 		}
@@ -367,7 +385,7 @@ func (c *Client) handleNotification(ntfn *rawNotification) {
 
 		hash, height, blkTime, err := parseRescanProgressParams(ntfn.Params)
 		if err != nil {
-			log.Warnf("Received invalid rescanprogress "+
+			c.logger().Warnf("Received invalid rescanprogress "+
 				"notification: %v", err)
 			return
 		}
@@ -384,7 +402,7 @@ func (c *Client) handleNotification(ntfn *rawNotification) {
 
 		hash, amt, err := parseTxAcceptedNtfnParams(ntfn.Params)
 		if err != nil {
-			log.Warnf("Received invalid tx accepted "+
+			c.logger().Warnf("Received invalid tx accepted "+
 				"notification: %v", err)
 			return
 		}
@@ -401,7 +419,7 @@ func (c *Client) handleNotification(ntfn *rawNotification) {
 
 		rawTx, err := parseTxAcceptedVerboseNtfnParams(ntfn.Params)
 		if err != nil {
-			log.Warnf("Received invalid tx accepted verbose "+
+			c.logger().Warnf("Received invalid tx accepted verbose "+
 				"notification: %v", err)
 			return
 		}
@@ -418,7 +436,7 @@ func (c *Client) handleNotification(ntfn *rawNotification) {
 
 		connected, err := parseBtcdConnectedNtfnParams(ntfn.Params)
 		if err != nil {
-			log.Warnf("Received invalid btcd connected "+
+			c.logger().Warnf("Received invalid btcd connected "+
 				"notification: %v", err)
 			return
 		}
@@ -435,7 +453,7 @@ func (c *Client) handleNotification(ntfn *rawNotification) {
 
 		account, bal, conf, err := parseAccountBalanceNtfnParams(ntfn.Params)
 		if err != nil {
-			log.Warnf("Received invalid account balance "+
+			c.logger().Warnf("Received invalid account balance "+
 				"notification: %v", err)
 			return
 		}
@@ -454,7 +472,7 @@ func (c *Client) handleNotification(ntfn *rawNotification) {
 		// discarded.
 		_, locked, err := parseWalletLockStateNtfnParams(ntfn.Params)
 		if err != nil {
-			log.Warnf("Received invalid wallet lock state "+
+			c.logger().Warnf("Received invalid wallet lock state "+
 				"notification: %v", err)
 			return
 		}
@@ -1354,3 +1372,44 @@ func (c *Client) LoadTxFilterAsync(reload bool, addresses []btcutil.Address,
 func (c *Client) LoadTxFilter(reload bool, addresses []btcutil.Address, outPoints []wire.OutPoint) error {
 	return c.LoadTxFilterAsync(reload, addresses, outPoints).Receive()
 }
+
+// LoadTxFilterScriptsAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See LoadTxFilterScripts for the blocking version and more details.
+//
+// NOTE: This is a btcd extension ported from github.com/decred/dcrrpcclient
+// and requires a websocket connection.
+func (c *Client) LoadTxFilterScriptsAsync(reload bool, pkScripts [][]byte,
+	outPoints []wire.OutPoint) FutureLoadTxFilterResult {
+
+	var addresses []btcutil.Address
+	for _, pkScript := range pkScripts {
+		_, addrs, _, err := txscript.ExtractPkScriptAddrs(pkScript,
+			c.chainParams())
+		if err != nil {
+			return newFutureError(err)
+		}
+		if len(addrs) == 0 {
+			return newFutureError(fmt.Errorf("output script %x does "+
+				"not pay to an address", pkScript))
+		}
+		addresses = append(addresses, addrs...)
+	}
+	return c.LoadTxFilterAsync(reload, addresses, outPoints)
+}
+
+// LoadTxFilterScripts is like LoadTxFilter, but the transactions to watch are
+// selected by the output scripts they pay to rather than by address, so light
+// clients tracking scripts are notified through OnRelevantTxAccepted without
+// rescanning.  The filter of the server only holds addresses, so each script
+// is loaded as the addresses it pays to on the network set by the ChainParams
+// field of the connection configuration.  An error is returned without issuing
+// the RPC when a script does not pay to any address.
+//
+// NOTE: This is a btcd extension ported from github.com/decred/dcrrpcclient
+// and requires a websocket connection.
+func (c *Client) LoadTxFilterScripts(reload bool, pkScripts [][]byte, outPoints []wire.OutPoint) error {
+	return c.LoadTxFilterScriptsAsync(reload, pkScripts, outPoints).Receive()
+}
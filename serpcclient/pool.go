@@ -0,0 +1,379 @@
+// Copyright (c) 2018 The box developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package serpcclient
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/zzpu/lib-bitcore/sebtcjson"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// defaultPoolHeartbeat is the interval at which a pool probes the
+	// health of its nodes when no interval is passed to NewPool.
+	defaultPoolHeartbeat = 30 * time.Second
+
+	// defaultPoolRequestTimeout is the request timeout of the clients of a
+	// pool whose connection configuration sets none, so a request to a
+	// node which stopped answering fails over instead of blocking forever.
+	defaultPoolRequestTimeout = time.Minute
+)
+
+// NodeHealth describes the health of a node of a pool as of the last request
+// sent to it or the last heartbeat, whichever happened last.
+type NodeHealth struct {
+	// Host is the host of the node as set in its connection configuration.
+	Host string
+
+	// Healthy reports whether the node was reachable.  Nodes are assumed
+	// healthy until a request to them fails.
+	Healthy bool
+
+	// LastError is the error which marked the node unhealthy.  It is nil
+	// for healthy nodes.
+	LastError error
+
+	// LastChecked is the time the health of the node was last updated.  It
+	// is zero until then.
+	LastChecked time.Time
+}
+
+// poolNode houses a client of a pool along with its health.
+type poolNode struct {
+	client *Client
+
+	// connected is set once the websocket connection of the client was
+	// established, and from the start in HTTP POST mode.  probing is set
+	// while a heartbeat probe of the node is running.
+	connected int32 // atomic
+	probing   int32 // atomic
+
+	mtx         sync.Mutex
+	healthy     bool
+	lastErr     error
+	lastChecked time.Time
+}
+
+// isHealthy returns whether the node was reachable when it was last used.
+func (n *poolNode) isHealthy() bool {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	return n.healthy
+}
+
+// connect makes a single attempt at establishing the websocket connection of
+// the node.
+func (n *poolNode) connect() error {
+	err := n.client.Connect(1)
+	if err != nil && err != ErrClientAlreadyConnected {
+		return err
+	}
+	atomic.StoreInt32(&n.connected, 1)
+	return nil
+}
+
+// probe returns an error when the node can not serve requests.  Websocket
+// nodes which are not connected yet are connected first.
+func (n *poolNode) probe() error {
+	if atomic.LoadInt32(&n.connected) == 0 {
+		if err := n.connect(); err != nil {
+			return err
+		}
+	}
+	return probeNode(n.client)
+}
+
+// record updates the health of the node with the outcome of a request.
+func (n *poolNode) record(err error) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	if err != nil && n.healthy {
+		n.client.logger().Warnf("Marking node %s unhealthy: %v",
+			n.client.config.Host, err)
+	}
+	n.healthy = err == nil
+	n.lastErr = err
+	n.lastChecked = time.Now()
+}
+
+// Pool routes requests across several redundant nodes, such as bitcoind
+// instances behind different hosts, and fails over to another node when one
+// can not be reached.  A heartbeat probes every node at a fixed interval, and
+// the outcome of every request routed by the pool also updates the health of
+// its node, so requests are only sent to nodes which are unhealthy when no
+// healthy node is left.
+//
+// Requests which may safely be sent more than once, such as reads, are spread
+// over the healthy nodes and retried on the next node after a connection error.
+// Requests which are not idempotent, such as sends, always go to the first
+// healthy node in the order the nodes were configured and are never retried,
+// since a node which did not answer may still have processed them.
+//
+// Errors returned by a node, such as an unknown block, are returned as is
+// without failing over since the other nodes would answer the same.
+type Pool struct {
+	nodes []*poolNode
+	next  uint32 // atomic
+
+	quit     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewPool returns a pool with a client for each of the passed connection
+// configurations, which probes the health of the nodes with getblockcount
+// every heartbeat interval.  The interval defaults to 30 seconds when it is
+// zero.  Notifications are not supported by clients of a pool.
+//
+// The configurations are copied.  Websocket clients are created with
+// DisableConnectOnNew set and connected by the pool, so nodes which can not be
+// reached are marked unhealthy instead of failing NewPool, and the heartbeat
+// keeps trying to connect them.  Clients without a RequestTimeout use a
+// timeout of one minute so a node which accepts requests but never answers
+// them can not block the pool.
+func NewPool(configs []*ConnConfig, heartbeat time.Duration) (*Pool, error) {
+	if len(configs) == 0 {
+		return nil, errors.New("pool requires at least one node")
+	}
+	if heartbeat < 0 {
+		return nil, errors.New("heartbeat interval must not be negative")
+	}
+	if heartbeat == 0 {
+		heartbeat = defaultPoolHeartbeat
+	}
+
+	p := &Pool{quit: make(chan struct{})}
+	for _, config := range configs {
+		config := *config
+		config.DisableConnectOnNew = !config.HTTPPostMode
+		if config.RequestTimeout == 0 {
+			config.RequestTimeout = defaultPoolRequestTimeout
+		}
+		client, err := New(&config, nil)
+		if err != nil {
+			for _, node := range p.nodes {
+				node.client.Shutdown()
+			}
+			return nil, err
+		}
+		node := &poolNode{client: client, healthy: true}
+		if config.HTTPPostMode {
+			node.connected = 1
+		}
+		p.nodes = append(p.nodes, node)
+	}
+
+	// Connect the websocket nodes concurrently so a node which does not
+	// answer does not hold up the others.
+	var wg sync.WaitGroup
+	for _, node := range p.nodes {
+		if node.connected != 0 {
+			continue
+		}
+		wg.Add(1)
+		go func(node *poolNode) {
+			defer wg.Done()
+			if err := node.connect(); err != nil {
+				node.record(err)
+			}
+		}(node)
+	}
+	wg.Wait()
+
+	p.wg.Add(1)
+	go p.heartbeatHandler(heartbeat)
+	return p, nil
+}
+
+// heartbeatHandler probes the health of every node each heartbeat interval
+// until the pool is shut down.  It must be run as a goroutine.
+func (p *Pool) heartbeatHandler(interval time.Duration) {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-p.quit:
+			return
+		}
+
+		// Nodes are probed independently, and a node whose previous
+		// probe is still running is skipped, so a node which never
+		// answers does not delay the probes of the others.
+		for _, node := range p.nodes {
+			if !atomic.CompareAndSwapInt32(&node.probing, 0, 1) {
+				continue
+			}
+			p.wg.Add(1)
+			go func(node *poolNode) {
+				defer p.wg.Done()
+				defer atomic.StoreInt32(&node.probing, 0)
+				node.record(node.probe())
+			}(node)
+		}
+	}
+}
+
+// probeNode returns an error when the passed client can not serve requests.  Any
+// answer of the server counts, except for an error reporting that it is still
+// starting up.
+func probeNode(client *Client) error {
+	_, err := client.GetBlockCount()
+	if rpcErr, ok := err.(*sebtcjson.RPCError); ok &&
+		rpcErr.Code != sebtcjson.ErrRPCInWarmup {
+
+		return nil
+	}
+	return err
+}
+
+// isConnectionError returns whether the passed error means the node could not
+// be reached, as opposed to an error returned by the node itself.
+func isConnectionError(err error) bool {
+	if err == ErrClientNotConnected || err == ErrClientDisconnect ||
+		err == ErrRequestTimeout {
+
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// Do invokes the passed function with the client of a healthy node.  The nodes
+// take turns, and when the function fails with a connection error, the node is
+// marked unhealthy and the function is invoked again with the client of the
+// next node until every node was tried.  The error of the last attempt is
+// returned.
+//
+// The function may be invoked more than once, so it must only issue requests
+// which are idempotent.  Use DoOnce for others.
+func (p *Pool) Do(fn func(*Client) error) error {
+	start := int(atomic.AddUint32(&p.next, 1) - 1)
+
+	// Healthy nodes are tried first, starting with the one whose turn it
+	// is, followed by the others in case they recovered.
+	ordered := make([]*poolNode, 0, len(p.nodes))
+	var unhealthy []*poolNode
+	for i := range p.nodes {
+		node := p.nodes[(start+i)%len(p.nodes)]
+		if node.isHealthy() {
+			ordered = append(ordered, node)
+		} else {
+			unhealthy = append(unhealthy, node)
+		}
+	}
+	ordered = append(ordered, unhealthy...)
+
+	var err error
+	for _, node := range ordered {
+		err = p.invoke(node, fn)
+		if !isConnectionError(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// DoOnce invokes the passed function once with the client of the first healthy
+// node, or of the first node when none is healthy.  The function is not invoked
+// again when it fails, so it may issue requests which are not idempotent, such
+// as sendtoaddress.
+func (p *Pool) DoOnce(fn func(*Client) error) error {
+	node := p.nodes[0]
+	for _, n := range p.nodes {
+		if n.isHealthy() {
+			node = n
+			break
+		}
+	}
+	return p.invoke(node, fn)
+}
+
+// invoke calls the passed function with the client of the passed node and
+// records the health of the node from the returned error.  Websocket nodes
+// which are not connected are skipped, since requests to them would wait for
+// the connection to be established.
+func (p *Pool) invoke(node *poolNode, fn func(*Client) error) error {
+	var err error
+	switch {
+	case atomic.LoadInt32(&node.connected) == 0:
+		err = ErrClientNotConnected
+	case !node.client.config.HTTPPostMode && node.client.Disconnected():
+		err = ErrClientDisconnect
+	default:
+		err = fn(node.client)
+	}
+	if isConnectionError(err) {
+		node.record(err)
+	} else if err != ErrClientShutdown {
+		node.record(nil)
+	}
+	return err
+}
+
+// RawRequest sends the passed raw request like Client.RawRequest, routing it
+// with Do when the method is idempotent and with DoOnce otherwise.  Methods
+// which are not registered with sebtcjson are treated as not idempotent.
+func (p *Pool) RawRequest(method string, params []json.RawMessage) (json.RawMessage, error) {
+	var result json.RawMessage
+	fn := func(c *Client) error {
+		var err error
+		result, err = c.RawRequest(method, params)
+		return err
+	}
+
+	var err error
+	if isIdempotent(method) {
+		err = p.Do(fn)
+	} else {
+		err = p.DoOnce(fn)
+	}
+	return result, err
+}
+
+// Health returns the health of every node of the pool, in the order the nodes
+// were configured.
+func (p *Pool) Health() []NodeHealth {
+	health := make([]NodeHealth, 0, len(p.nodes))
+	for _, node := range p.nodes {
+		node.mtx.Lock()
+		health = append(health, NodeHealth{
+			Host:        node.client.config.Host,
+			Healthy:     node.healthy,
+			LastError:   node.lastErr,
+			LastChecked: node.lastChecked,
+		})
+		node.mtx.Unlock()
+	}
+	return health
+}
+
+// Shutdown stops the heartbeat and shuts down the clients of every node.  It is
+// idempotent.  Use WaitForShutdown to block until they are stopped.
+func (p *Pool) Shutdown() {
+	p.stopOnce.Do(func() {
+		close(p.quit)
+	})
+	for _, node := range p.nodes {
+		node.client.Shutdown()
+	}
+}
+
+// WaitForShutdown blocks until the heartbeat and the clients of every node are
+// stopped.
+func (p *Pool) WaitForShutdown() {
+	p.wg.Wait()
+	for _, node := range p.nodes {
+		node.client.WaitForShutdown()
+	}
+}
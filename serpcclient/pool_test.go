@@ -0,0 +1,302 @@
+// Copyright (c) 2018 The box developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package serpcclient
+
+import (
+	"encoding/json"
+	"github.com/btcsuite/websocket"
+	"github.com/zzpu/lib-bitcore/sebtcjson"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestPool returns a pool with a node for each of the passed test clients
+// and, when dead is set, a leading node which refuses connections.
+func newTestPool(t *testing.T, dead bool, heartbeat time.Duration, clients ...*Client) *Pool {
+	var configs []*ConnConfig
+	if dead {
+		s := httptest.NewServer(http.NotFoundHandler())
+		s.Close()
+		configs = append(configs, &ConnConfig{
+			Host:         strings.TrimPrefix(s.URL, "http://"),
+			DisableTLS:   true,
+			HTTPPostMode: true,
+		})
+	}
+	for _, client := range clients {
+		config := *client.config
+		configs = append(configs, &config)
+	}
+
+	pool, err := NewPool(configs, heartbeat)
+	if err != nil {
+		t.Fatalf("NewPool: unexpected error: %v", err)
+	}
+	return pool
+}
+
+// closeTestPool shuts down the passed pool.
+func closeTestPool(pool *Pool) {
+	pool.Shutdown()
+	pool.WaitForShutdown()
+}
+
+// TestPoolDo ensures idempotent requests are spread over the healthy nodes and
+// fail over to another node when one can not be reached.
+func TestPoolDo(t *testing.T) {
+	t.Parallel()
+
+	handler := func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		return 100, nil
+	}
+	client1, server1 := newTestClient(t, handler)
+	defer closeTestClient(client1, server1)
+	client2, server2 := newTestClient(t, handler)
+	defer closeTestClient(client2, server2)
+
+	pool := newTestPool(t, true, time.Hour, client1, client2)
+	defer closeTestPool(pool)
+
+	for i := 0; i < 6; i++ {
+		err := pool.Do(func(c *Client) error {
+			_, err := c.GetBlockCount()
+			return err
+		})
+		if err != nil {
+			t.Fatalf("Do #%d: unexpected error: %v", i, err)
+		}
+	}
+
+	// The dead node is tried once, after which its turns go to the next
+	// node with the load split over the live ones.
+	n1, n2 := server1.calls("getblockcount"), server2.calls("getblockcount")
+	if n1+n2 != 6 || n1 < 2 || n2 < 2 {
+		t.Errorf("unexpected distribution of requests - got %d and %d",
+			n1, n2)
+	}
+
+	health := pool.Health()
+	if len(health) != 3 {
+		t.Fatalf("unexpected number of nodes %d", len(health))
+	}
+	if health[0].Healthy || health[0].LastError == nil {
+		t.Errorf("dead node not marked unhealthy: %+v", health[0])
+	}
+	for _, h := range health[1:] {
+		if !h.Healthy || h.LastError != nil || h.LastChecked.IsZero() {
+			t.Errorf("live node not marked healthy: %+v", h)
+		}
+	}
+
+	// Errors returned by a node are not retried elsewhere.
+	calls := server1.calls("getbestblockhash") + server2.calls("getbestblockhash")
+	err := pool.Do(func(c *Client) error {
+		_, err := c.GetBestBlockHash()
+		return err
+	})
+	if err == nil {
+		t.Fatalf("Do: expected error for invalid result")
+	}
+	got := server1.calls("getbestblockhash") + server2.calls("getbestblockhash")
+	if got-calls != 1 {
+		t.Errorf("request failing on the node was sent %d times",
+			got-calls)
+	}
+}
+
+// TestPoolDoOnce ensures requests which are not idempotent stick to the first
+// healthy node and are never retried.
+func TestPoolDoOnce(t *testing.T) {
+	t.Parallel()
+
+	handler := func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		return "txid", nil
+	}
+	client1, server1 := newTestClient(t, handler)
+	defer closeTestClient(client1, server1)
+	client2, server2 := newTestClient(t, handler)
+	defer closeTestClient(client2, server2)
+
+	pool := newTestPool(t, true, time.Hour, client1, client2)
+	defer closeTestPool(pool)
+
+	// The dead node is still assumed healthy, so the send fails without
+	// being sent to another node.
+	params := []json.RawMessage{json.RawMessage(`"addr"`), json.RawMessage(`1`)}
+	if _, err := pool.RawRequest("sendtoaddress", params); err == nil {
+		t.Fatalf("RawRequest: expected error for dead node")
+	}
+	if n := server1.calls("sendtoaddress") + server2.calls("sendtoaddress"); n != 0 {
+		t.Fatalf("failed send was retried %d times", n)
+	}
+
+	// Once it is marked unhealthy, sends go to the first live node only.
+	for i := 0; i < 3; i++ {
+		if _, err := pool.RawRequest("sendtoaddress", params); err != nil {
+			t.Fatalf("RawRequest #%d: unexpected error: %v", i, err)
+		}
+	}
+	if n1, n2 := server1.calls("sendtoaddress"), server2.calls("sendtoaddress"); n1 != 3 || n2 != 0 {
+		t.Errorf("sends did not stick to one node - got %d and %d",
+			n1, n2)
+	}
+}
+
+// TestPoolHeartbeat ensures the heartbeat marks nodes which can not be reached
+// unhealthy without any requests being routed to them.
+func TestPoolHeartbeat(t *testing.T) {
+	t.Parallel()
+
+	client, server := newTestClient(t, func(method string, params []json.RawMessage) (interface{}, *sebtcjson.RPCError) {
+		return 100, nil
+	})
+	defer closeTestClient(client, server)
+
+	pool := newTestPool(t, true, 10*time.Millisecond, client)
+	defer closeTestPool(pool)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		health := pool.Health()
+		if !health[0].Healthy && health[1].Healthy &&
+			!health[1].LastChecked.IsZero() {
+
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("heartbeat did not update health: %+v", health)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// newWSTestNode returns the connection configuration of a websocket server
+// which answers every request with 100, or never answers when hung is set.
+func newWSTestNode(t *testing.T, hung bool) (*ConnConfig, *httptest.Server) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			_, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			var req sebtcjson.Request
+			if err := json.Unmarshal(msg, &req); err != nil {
+				return
+			}
+			if hung {
+				continue
+			}
+			reply, err := sebtcjson.MarshalResponse(req.ID, 100, nil)
+			if err != nil {
+				return
+			}
+			conn.WriteMessage(websocket.TextMessage, reply)
+		}
+	}))
+	return &ConnConfig{
+		Host:       strings.TrimPrefix(server.URL, "http://"),
+		Endpoint:   "ws",
+		User:       "user",
+		Pass:       "pass",
+		DisableTLS: true,
+	}, server
+}
+
+// TestPoolWebsocket ensures a pool of websocket nodes is created while a node
+// is down and fails over from nodes which are down or never answer.
+func TestPoolWebsocket(t *testing.T) {
+	t.Parallel()
+
+	dead, deadServer := newWSTestNode(t, false)
+	deadServer.Close()
+	hung, hungServer := newWSTestNode(t, true)
+	defer hungServer.Close()
+	hung.RequestTimeout = 100 * time.Millisecond
+	live, liveServer := newWSTestNode(t, false)
+	defer liveServer.Close()
+
+	pool, err := NewPool([]*ConnConfig{dead, hung, live}, time.Hour)
+	if err != nil {
+		t.Fatalf("NewPool: unexpected error: %v", err)
+	}
+	defer closeTestPool(pool)
+
+	if health := pool.Health(); health[0].Healthy || !health[1].Healthy ||
+		!health[2].Healthy {
+
+		t.Fatalf("unexpected health after NewPool: %+v", health)
+	}
+
+	for i := 0; i < 4; i++ {
+		err := pool.Do(func(c *Client) error {
+			count, err := c.GetBlockCount()
+			if err == nil && count != 100 {
+				t.Errorf("Do #%d: unexpected block count %d", i, count)
+			}
+			return err
+		})
+		if err != nil {
+			t.Fatalf("Do #%d: unexpected error: %v", i, err)
+		}
+	}
+
+	health := pool.Health()
+	if health[0].Healthy || health[0].LastError == nil {
+		t.Errorf("dead node not marked unhealthy: %+v", health[0])
+	}
+	if health[1].Healthy || health[1].LastError != ErrRequestTimeout {
+		t.Errorf("hung node not marked unhealthy: %+v", health[1])
+	}
+	if !health[2].Healthy {
+		t.Errorf("live node not marked healthy: %+v", health[2])
+	}
+}
+
+// TestPoolHeartbeatHung ensures a node which never answers the heartbeat does
+// not hold up the probes of the other nodes.
+func TestPoolHeartbeatHung(t *testing.T) {
+	t.Parallel()
+
+	hung, hungServer := newWSTestNode(t, true)
+	defer hungServer.Close()
+	live, liveServer := newWSTestNode(t, false)
+	defer liveServer.Close()
+
+	pool, err := NewPool([]*ConnConfig{hung, live}, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewPool: unexpected error: %v", err)
+	}
+	defer closeTestPool(pool)
+
+	// The probe of the hung node lasts until the pool is shut down, while
+	// the live node keeps being probed.
+	var checked []time.Time
+	deadline := time.Now().Add(5 * time.Second)
+	for len(checked) < 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("heartbeat stalled: %+v", pool.Health())
+		}
+		last := pool.Health()[1].LastChecked
+		if !last.IsZero() && (len(checked) == 0 ||
+			last.After(checked[len(checked)-1])) {
+
+			checked = append(checked, last)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if health := pool.Health(); !health[0].LastChecked.IsZero() {
+		t.Errorf("hung node answered the heartbeat: %+v", health[0])
+	}
+}
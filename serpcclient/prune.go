@@ -0,0 +1,139 @@
+// Copyright (c) 2018 The box developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package serpcclient
+
+import (
+	"errors"
+	"github.com/zzpu/lib-bitcore/sebtcjson"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// pruneHeightRefreshInterval is the time after which the prune height
+	// cached for a pruned server is fetched again.  The cache is also
+	// refreshed after each block connected notification.
+	pruneHeightRefreshInterval = 10 * time.Minute
+)
+
+// ErrBlockPruned is returned when a block is requested which a pruned server
+// no longer stores.  The errors returned when the server itself reports this
+// condition match it with errors.Is and still unwrap to the
+// *sebtcjson.RPCError of the server.
+var ErrBlockPruned = errors.New("block has been pruned")
+
+// pruneState caches whether the server prunes its blocks and the height of
+// the lowest block it still stores.  Once the server reports that it is not
+// pruned, the state is never fetched again.
+//
+// The state is safe for concurrent access.  A nil state caches nothing.
+type pruneState struct {
+	mtx       sync.Mutex
+	fetchedAt time.Time
+	disabled  bool
+	height    int32
+}
+
+// get returns the cached prune height and whether it is known, which is the
+// case when it was fetched within the refresh interval.  The height is zero
+// for servers which are not pruned.
+func (s *pruneState) get() (int32, bool) {
+	if s == nil {
+		return 0, false
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.disabled {
+		return 0, true
+	}
+	if s.fetchedAt.IsZero() ||
+		time.Since(s.fetchedAt) >= pruneHeightRefreshInterval {
+
+		return 0, false
+	}
+	return s.height, true
+}
+
+// set records the state reported by getblockchaininfo.
+func (s *pruneState) set(pruned bool, height int32) {
+	if s == nil {
+		return
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.disabled = !pruned
+	s.height = height
+	s.fetchedAt = time.Now()
+}
+
+// invalidate forces the prune height to be fetched again on next use.
+func (s *pruneState) invalidate() {
+	if s == nil {
+		return
+	}
+
+	s.mtx.Lock()
+	s.fetchedAt = time.Time{}
+	s.mtx.Unlock()
+}
+
+// pruneHeight returns the height of the lowest block stored by the server like
+// LowestStoredBlock, but the height is cached and only fetched when it is
+// unknown or stale.
+func (c *Client) pruneHeight() (int32, error) {
+	if height, ok := c.prune.get(); ok {
+		return height, nil
+	}
+	return c.LowestStoredBlock()
+}
+
+// LowestStoredBlock returns the height of the lowest block the server stores,
+// as reported by getblockchaininfo.  It is zero when the server is not pruned,
+// so blocks below it have to be fetched from elsewhere.
+func (c *Client) LowestStoredBlock() (int32, error) {
+	info, err := c.GetBlockChainInfo()
+	if err != nil {
+		return 0, err
+	}
+	height := info.PruneHeight
+	if !info.Pruned {
+		height = 0
+	}
+	c.prune.set(info.Pruned, height)
+	return height, nil
+}
+
+// prunedBlockError wraps the error returned by the server when a block was
+// requested which it no longer stores.
+type prunedBlockError struct {
+	*sebtcjson.RPCError
+}
+
+// Is returns whether the target is ErrBlockPruned.
+func (e *prunedBlockError) Is(target error) bool {
+	return target == ErrBlockPruned
+}
+
+// Unwrap returns the error returned by the server.
+func (e *prunedBlockError) Unwrap() error {
+	return e.RPCError
+}
+
+// newPrunedBlockError returns a prunedBlockError for the passed error when the
+// server reports that the requested block was pruned, and the error itself
+// otherwise.  Bitcoin Core reports this with a generic error code, so the
+// message is matched.
+func newPrunedBlockError(err error) error {
+	rpcErr, ok := err.(*sebtcjson.RPCError)
+	if !ok || !strings.Contains(rpcErr.Message, "pruned data") {
+		return err
+	}
+	return &prunedBlockError{rpcErr}
+}
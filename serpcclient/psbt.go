@@ -0,0 +1,279 @@
+// Copyright (c) 2018 The box developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package serpcclient
+
+import (
+	"encoding/json"
+	"github.com/btcsuite/btcutil"
+	"github.com/zzpu/lib-bitcore/sebtcjson"
+)
+
+// FutureWalletCreateFundedPsbtResult is a future promise to deliver the result
+// of a WalletCreateFundedPsbtAsync RPC invocation (or an applicable error).
+type FutureWalletCreateFundedPsbtResult chan *response
+
+// Receive waits for the response promised by the future and returns the funded
+// PSBT along with its fee and the position of its change output.
+func (r FutureWalletCreateFundedPsbtResult) Receive() (*sebtcjson.WalletCreateFundedPsbtResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a walletcreatefundedpsbt result object.
+	var result sebtcjson.WalletCreateFundedPsbtResult
+	err = json.Unmarshal(res, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// WalletCreateFundedPsbtAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See WalletCreateFundedPsbt for the blocking version and more details.
+func (c *Client) WalletCreateFundedPsbtAsync(inputs []sebtcjson.TransactionInput,
+	outputs map[btcutil.Address]btcutil.Amount, lockTime *uint32,
+	options *sebtcjson.WalletCreateFundedPsbtOpts, bip32Derivs *bool) FutureWalletCreateFundedPsbtResult {
+
+	convertedAmts := make(map[string]float64, len(outputs))
+	for addr, amount := range outputs {
+		convertedAmts[addr.EncodeAddress()] = amount.ToBTC()
+	}
+	cmd := sebtcjson.NewWalletCreateFundedPsbtCmd(inputs, convertedAmts,
+		lockTime, options, bip32Derivs)
+	return c.sendCmd(cmd)
+}
+
+// WalletCreateFundedPsbt returns a PSBT, encoded in base64, paying the provided
+// outputs and funded by the wallet.  The passed inputs are always spent, and the
+// wallet adds inputs of its own and a change output until the outputs and the
+// fee are covered.  The options, which may be nil, select the change address,
+// the fee rate and whether the transaction signals BIP 125 replaceability,
+// among others.  BIP 32 derivation paths are included unless bip32Derivs is
+// false.
+//
+// The PSBT is not signed; see WalletProcessPsbt.
+func (c *Client) WalletCreateFundedPsbt(inputs []sebtcjson.TransactionInput,
+	outputs map[btcutil.Address]btcutil.Amount, lockTime *uint32,
+	options *sebtcjson.WalletCreateFundedPsbtOpts, bip32Derivs *bool) (*sebtcjson.WalletCreateFundedPsbtResult, error) {
+
+	return c.WalletCreateFundedPsbtAsync(inputs, outputs, lockTime, options,
+		bip32Derivs).Receive()
+}
+
+// FutureWalletProcessPsbtResult is a future promise to deliver the result of a
+// WalletProcessPsbtAsync RPC invocation (or an applicable error).
+type FutureWalletProcessPsbtResult chan *response
+
+// Receive waits for the response promised by the future and returns the
+// updated PSBT and whether all of its inputs are now signed.
+func (r FutureWalletProcessPsbtResult) Receive() (*sebtcjson.WalletProcessPsbtResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a walletprocesspsbt result object.
+	var result sebtcjson.WalletProcessPsbtResult
+	err = json.Unmarshal(res, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// WalletProcessPsbtAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See WalletProcessPsbt for the blocking version and more details.
+func (c *Client) WalletProcessPsbtAsync(psbt string, sign *bool,
+	hashType SigHashType, bip32Derivs *bool) FutureWalletProcessPsbtResult {
+
+	var sigHashType *string
+	if hashType != "" {
+		sigHashType = sebtcjson.String(string(hashType))
+	}
+	cmd := sebtcjson.NewWalletProcessPsbtCmd(psbt, sign, sigHashType,
+		bip32Derivs)
+	return c.sendCmd(cmd)
+}
+
+// WalletProcessPsbt updates the passed PSBT with the information the wallet
+// has about its inputs and, unless sign is false, signs the inputs the wallet
+// has the keys for with the specified signature hash type.  The signature hash
+// type defaults to SigHashAll when it is empty.
+func (c *Client) WalletProcessPsbt(psbt string, sign *bool,
+	hashType SigHashType, bip32Derivs *bool) (*sebtcjson.WalletProcessPsbtResult, error) {
+
+	return c.WalletProcessPsbtAsync(psbt, sign, hashType,
+		bip32Derivs).Receive()
+}
+
+// FutureFinalizePsbtResult is a future promise to deliver the result of a
+// FinalizePsbtAsync RPC invocation (or an applicable error).
+type FutureFinalizePsbtResult chan *response
+
+// Receive waits for the response promised by the future and returns the
+// finalized PSBT or the extracted transaction along with whether the PSBT is
+// complete.
+func (r FutureFinalizePsbtResult) Receive() (*sebtcjson.FinalizePsbtResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a finalizepsbt result object.
+	var result sebtcjson.FinalizePsbtResult
+	err = json.Unmarshal(res, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// FinalizePsbtAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See FinalizePsbt for the blocking version and more details.
+func (c *Client) FinalizePsbtAsync(psbt string, extract *bool) FutureFinalizePsbtResult {
+	cmd := sebtcjson.NewFinalizePsbtCmd(psbt, extract)
+	return c.sendCmd(cmd)
+}
+
+// FinalizePsbt finalizes the inputs of the passed PSBT which carry enough
+// signatures.  Once every input is final and extract is not false, the result
+// holds the hex-encoded network serialized transaction ready to be broadcast
+// with SendRawTransaction.  Otherwise it holds the finalized PSBT, encoded in
+// base64, and the complete flag reports whether every input is final.
+func (c *Client) FinalizePsbt(psbt string, extract *bool) (*sebtcjson.FinalizePsbtResult, error) {
+	return c.FinalizePsbtAsync(psbt, extract).Receive()
+}
+
+// FutureCombinePsbtResult is a future promise to deliver the result of a
+// CombinePsbtAsync RPC invocation (or an applicable error).
+type FutureCombinePsbtResult chan *response
+
+// Receive waits for the response promised by the future and returns the
+// combined PSBT encoded in base64.
+func (r FutureCombinePsbtResult) Receive() (string, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return "", err
+	}
+
+	// Unmarshal result as a string.
+	var psbt string
+	err = json.Unmarshal(res, &psbt)
+	if err != nil {
+		return "", err
+	}
+
+	return psbt, nil
+}
+
+// CombinePsbtAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See CombinePsbt for the blocking version and more details.
+func (c *Client) CombinePsbtAsync(psbts []string) FutureCombinePsbtResult {
+	cmd := sebtcjson.NewCombinePsbtCmd(psbts)
+	return c.sendCmd(cmd)
+}
+
+// CombinePsbt merges the passed PSBTs, which must all be of the same unsigned
+// transaction, into one which carries the signatures and other data of every
+// one of them.
+func (c *Client) CombinePsbt(psbts []string) (string, error) {
+	return c.CombinePsbtAsync(psbts).Receive()
+}
+
+// FutureDecodePsbtResult is a future promise to deliver the result of a
+// DecodePsbtAsync RPC invocation (or an applicable error).
+type FutureDecodePsbtResult chan *response
+
+// Receive waits for the response promised by the future and returns the
+// decoded PSBT.
+func (r FutureDecodePsbtResult) Receive() (*sebtcjson.DecodePsbtResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a decodepsbt result object.
+	var result sebtcjson.DecodePsbtResult
+	err = json.Unmarshal(res, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// DecodePsbtAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See DecodePsbt for the blocking version and more details.
+func (c *Client) DecodePsbtAsync(psbt string) FutureDecodePsbtResult {
+	cmd := sebtcjson.NewDecodePsbtCmd(psbt)
+	return c.sendCmd(cmd)
+}
+
+// DecodePsbt returns the unsigned transaction of the passed PSBT along with the
+// data it carries for each of its inputs and outputs.
+func (c *Client) DecodePsbt(psbt string) (*sebtcjson.DecodePsbtResult, error) {
+	return c.DecodePsbtAsync(psbt).Receive()
+}
+
+// FutureAnalyzePsbtResult is a future promise to deliver the result of an
+// AnalyzePsbtAsync RPC invocation (or an applicable error).
+type FutureAnalyzePsbtResult chan *response
+
+// Receive waits for the response promised by the future and returns the
+// analysis of the PSBT.
+func (r FutureAnalyzePsbtResult) Receive() (*sebtcjson.AnalyzePsbtResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as an analyzepsbt result object.
+	var result sebtcjson.AnalyzePsbtResult
+	err = json.Unmarshal(res, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// AnalyzePsbtAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See AnalyzePsbt for the blocking version and more details.
+func (c *Client) AnalyzePsbtAsync(psbt string) FutureAnalyzePsbtResult {
+	cmd := sebtcjson.NewAnalyzePsbtCmd(psbt)
+	return c.sendCmd(cmd)
+}
+
+// AnalyzePsbt reports which data each input of the passed PSBT is missing and
+// which role, such as the signer or the finalizer, has to process it next,
+// along with the estimated size and fee rate of the transaction once they can
+// be computed.
+//
+// NOTE: This requires Bitcoin Core 0.18 or later.
+func (c *Client) AnalyzePsbt(psbt string) (*sebtcjson.AnalyzePsbtResult, error) {
+	return c.AnalyzePsbtAsync(psbt).Receive()
+}
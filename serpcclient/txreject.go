@@ -0,0 +1,255 @@
+// Copyright (c) 2018 The box developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package serpcclient
+
+import (
+	"github.com/zzpu/lib-bitcore/sebtcjson"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// TxRejectKind classifies the reason a transaction was rejected by the server.
+type TxRejectKind int
+
+const (
+	// TxRejectUnknown is used for reject reasons which are not recognized.
+	TxRejectUnknown TxRejectKind = iota
+
+	// TxRejectInsufficientFee indicates the fee of the transaction is too
+	// low for the mempool or to replace the transactions it conflicts with.
+	TxRejectInsufficientFee
+
+	// TxRejectHighFee indicates the fee of the transaction exceeds the
+	// maximum the server is configured to accept.
+	TxRejectHighFee
+
+	// TxRejectDoubleSpend indicates the transaction spends outputs which
+	// are already spent by another transaction in the mempool.
+	TxRejectDoubleSpend
+
+	// TxRejectMissingInputs indicates the transaction spends outputs which
+	// are unknown or already spent in the chain.
+	TxRejectMissingInputs
+
+	// TxRejectAlreadyKnown indicates the transaction is already in the
+	// mempool or the chain.
+	TxRejectAlreadyKnown
+
+	// TxRejectNonStandard indicates the transaction is valid but violates
+	// the standardness policy of the server.
+	TxRejectNonStandard
+
+	// TxRejectInvalid indicates the transaction violates the consensus
+	// rules.
+	TxRejectInvalid
+)
+
+// String returns the TxRejectKind in human-readable form.
+func (k TxRejectKind) String() string {
+	switch k {
+	case TxRejectInsufficientFee:
+		return "insufficient-fee"
+	case TxRejectHighFee:
+		return "high-fee"
+	case TxRejectDoubleSpend:
+		return "double-spend"
+	case TxRejectMissingInputs:
+		return "missing-inputs"
+	case TxRejectAlreadyKnown:
+		return "already-known"
+	case TxRejectNonStandard:
+		return "non-standard"
+	case TxRejectInvalid:
+		return "invalid"
+	}
+	return "unknown"
+}
+
+// txRejectKinds maps the reject reasons reported by the various versions of
+// Bitcoin Core, in lower case, to their kind.  Reasons which are not listed
+// are matched by txRejectPrefixes.
+var txRejectKinds = map[string]TxRejectKind{
+	// Fee policy.
+	"min relay fee not met":   TxRejectInsufficientFee,
+	"mempool min fee not met": TxRejectInsufficientFee,
+	"insufficient fee":        TxRejectInsufficientFee,
+	"insufficient priority":   TxRejectInsufficientFee,
+	"absurdly-high-fee":       TxRejectHighFee,
+	"max-fee-exceeded":        TxRejectHighFee,
+
+	// Returned by sendrawtransaction itself rather than the mempool from
+	// 0.19.
+	"fee exceeds maximum configured by user": TxRejectHighFee,
+
+	// Conflicts and inputs.
+	"txn-mempool-conflict":           TxRejectDoubleSpend,
+	"bad-txns-spends-conflicting-tx": TxRejectDoubleSpend,
+	"missing inputs":                 TxRejectMissingInputs,
+	"missing-inputs":                 TxRejectMissingInputs,
+	"bad-txns-inputs-missingorspent": TxRejectMissingInputs,
+	"inputs missing or spent":        TxRejectMissingInputs,
+
+	// Transactions the server already has.
+	"txn-already-in-mempool":                  TxRejectAlreadyKnown,
+	"txn-already-known":                       TxRejectAlreadyKnown,
+	"txn-same-nonwitness-data-in-mempool":     TxRejectAlreadyKnown,
+	"transaction already in block chain":      TxRejectAlreadyKnown,
+	"transaction outputs already in utxo set": TxRejectAlreadyKnown,
+
+	// Standardness policy.
+	"dust":                        TxRejectNonStandard,
+	"bare-multisig":               TxRejectNonStandard,
+	"multi-op-return":             TxRejectNonStandard,
+	"scriptpubkey":                TxRejectNonStandard,
+	"scriptsig-size":              TxRejectNonStandard,
+	"scriptsig-not-pushonly":      TxRejectNonStandard,
+	"tx-size":                     TxRejectNonStandard,
+	"tx-size-small":               TxRejectNonStandard,
+	"version":                     TxRejectNonStandard,
+	"bad-txns-nonstandard-inputs": TxRejectNonStandard,
+	"bad-witness-nonstandard":     TxRejectNonStandard,
+	"too-long-mempool-chain":      TxRejectNonStandard,
+	"non-final":                   TxRejectNonStandard,
+	"non-bip68-final":             TxRejectNonStandard,
+}
+
+// txRejectPrefixes maps the prefixes of reject reasons which carry a varying
+// suffix to their kind.  The prefixes are in lower case.
+var txRejectPrefixes = []struct {
+	prefix string
+	kind   TxRejectKind
+}{
+	{"non-mandatory-script-verify-flag", TxRejectNonStandard},
+	{"mandatory-script-verify-flag-failed", TxRejectInvalid},
+	{"bad-txns-", TxRejectInvalid},
+}
+
+var (
+	// txRejectCodePrefix matches the reject code which versions of
+	// Bitcoin Core before 0.14 put in front of the reason, such as
+	// "66: min relay fee not met".
+	txRejectCodePrefix = regexp.MustCompile(`^(\d+): `)
+
+	// txRejectCodeSuffix matches the reject code which versions of
+	// Bitcoin Core from 0.14 until 0.20 put after the reason, such as
+	// "min relay fee not met (code 66)".
+	txRejectCodeSuffix = regexp.MustCompile(` \(code (\d+)\)$`)
+)
+
+// TxRejection houses the reject information embedded in the error message of
+// the server when a transaction is not accepted.
+type TxRejection struct {
+	// Reason is the reject reason, such as "min relay fee not met".  It is
+	// the full message when the message could not be parsed.
+	Reason string
+
+	// Code is the P2P reject code reported by versions of Bitcoin Core
+	// before 0.20, such as 66 for insufficient fees.  It is zero when the
+	// message does not include one.
+	Code int
+
+	// Details is the additional debug information following the reason,
+	// such as the fees involved.  It is empty when there is none.
+	Details string
+}
+
+// Kind returns the classification of the reject reason.
+func (r *TxRejection) Kind() TxRejectKind {
+	reason := strings.ToLower(r.Reason)
+	if kind, ok := txRejectKinds[reason]; ok {
+		return kind
+	}
+	for _, p := range txRejectPrefixes {
+		if strings.HasPrefix(reason, p.prefix) {
+			return p.kind
+		}
+	}
+	return TxRejectUnknown
+}
+
+// ParseTxRejection parses the reject information out of the passed error message
+// of the server.  The formats used by Bitcoin Core from 0.10 onwards are
+// supported:
+//
+//	66: min relay fee not met
+//	min relay fee not met (code 66)
+//	min relay fee not met, 100 < 141
+//	mandatory-script-verify-flag-failed (Script evaluated without error but finished with a false/empty top stack element)
+//
+// The whole message is used as the reason when it can not be parsed.
+func ParseTxRejection(message string) TxRejection {
+	var rejection TxRejection
+	rest := strings.TrimSpace(message)
+
+	if m := txRejectCodePrefix.FindStringSubmatch(rest); m != nil {
+		rejection.Code, _ = strconv.Atoi(m[1])
+		rest = rest[len(m[0]):]
+	} else if m := txRejectCodeSuffix.FindStringSubmatch(rest); m != nil {
+		rejection.Code, _ = strconv.Atoi(m[1])
+		rest = rest[:len(rest)-len(m[0])]
+	}
+
+	// The debug information is either separated with a comma or, for
+	// script failures, given in parentheses.  Either may contain the other
+	// separator, so the first one found is used.
+	comma := strings.Index(rest, ", ")
+	paren := strings.Index(rest, " (")
+	switch {
+	case paren >= 0 && (comma < 0 || paren < comma) &&
+		strings.HasSuffix(rest, ")"):
+
+		rejection.Reason = rest[:paren]
+		rejection.Details = rest[paren+2 : len(rest)-1]
+
+	case comma >= 0:
+		rejection.Reason = rest[:comma]
+		rejection.Details = rest[comma+2:]
+
+	default:
+		rejection.Reason = rest
+	}
+
+	if rejection.Reason == "" {
+		return TxRejection{Reason: message}
+	}
+	return rejection
+}
+
+// TxRejectedError is the error returned by SendRawTransaction when the server
+// rejects the transaction.  It wraps the error returned by the server along
+// with the reject information parsed from its message.
+type TxRejectedError struct {
+	*sebtcjson.RPCError
+
+	// Rejection is the reject information parsed from the message.
+	Rejection TxRejection
+}
+
+// Unwrap returns the error returned by the server.
+func (e *TxRejectedError) Unwrap() error {
+	return e.RPCError
+}
+
+// newTxRejectedError returns a TxRejectedError for the passed error when it is
+// a transaction rejection reported by the server, and the error itself
+// otherwise.
+func newTxRejectedError(err error) error {
+	rpcErr, ok := err.(*sebtcjson.RPCError)
+	if !ok {
+		return err
+	}
+	switch rpcErr.Code {
+	case sebtcjson.ErrRPCVerify, sebtcjson.ErrRPCVerifyRejected,
+		sebtcjson.ErrRPCVerifyAlreadyInChain:
+	default:
+		return err
+	}
+
+	return &TxRejectedError{
+		RPCError:  rpcErr,
+		Rejection: ParseTxRejection(rpcErr.Message),
+	}
+}